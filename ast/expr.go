@@ -305,6 +305,7 @@ type BinOpExpr struct {
 	Lhss     []Expr
 	Operator string
 	Rhss     []Expr
+	IntOp    bool // проверка типов установила, что оба операнда целые числа
 }
 
 func (x *BinOpExpr) Simplify() Expr {
@@ -368,7 +369,11 @@ func (e *BinOpExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool
 		bins.Append(binstmt.NewBinLABEL(lab, e))
 	default:
 		e.Rhss[0].BinTo(bins, reg+1, lid, false, maxreg)
-		bins.Append(binstmt.NewBinOPER(reg, reg+1, oper, e))
+		if e.IntOp {
+			bins.Append(binstmt.NewBinOPINT(reg, reg+1, oper, e))
+		} else {
+			bins.Append(binstmt.NewBinOPER(reg, reg+1, oper, e))
+		}
 	}
 	if reg+1 > *maxreg {
 		*maxreg = reg + 1
@@ -489,12 +494,15 @@ func (e *AnonCallExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt b
 	// помещаем в регистр значение функции (тип func, или ссылку на него, или интерфейс с ним)
 	e.Expr.BinTo(bins, reg, lid, false, maxreg)
 	// далее аргументы, как при вызове обычной функции
-	(&CallExpr{
+	call := &CallExpr{
 		Name:     0,
 		SubExprs: e.SubExprs,
 		VarArg:   e.VarArg,
 		Go:       e.Go,
-	}).BinTo(bins, reg, lid, false, maxreg) // передаем именно reg, т.к. он для Name==0 означает функцию, которую надо вызвать в BinCALL
+	}
+	// позиция нужна, чтобы ошибки методов указывали на место вызова
+	call.SetPosition(e.Position())
+	call.BinTo(bins, reg, lid, false, maxreg) // передаем именно reg, т.к. он для Name==0 означает функцию, которую надо вызвать в BinCALL
 	if reg > *maxreg {
 		*maxreg = reg
 	}
@@ -634,22 +642,97 @@ func (e *SliceExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool
 	}
 }
 
+// FuncParam - параметр в объявлении функции или процедуры: [Знач] Имя[: Тип] [= ЗначениеПоУмолчанию]
+type FuncParam struct {
+	Name    int // string
+	Type    int // string, 0 - без аннотации типа
+	ByVal   bool
+	Default Expr
+}
+
 // FuncExpr provide function expression.
 type FuncExpr struct {
 	ExprImpl
-	Name   int // string
-	Stmts  Stmts
-	Args   []int // string
-	VarArg bool
+	Name     int // string
+	Stmts    Stmts
+	Args     []int // string
+	VarArg   bool
+	Defaults []Expr // значения по умолчанию, nil для обязательных параметров
+	ByVal    []bool // параметры, передаваемые по значению (Знач)
+	Export   bool
+	Method   bool  // метод пользовательского типа
+	ArgTypes []int // аннотации типов параметров, nil если их нет
+	RetType  int   // аннотация типа возвращаемого значения
+}
+
+// SetParams заполняет параметры функции из объявления
+func (x *FuncExpr) SetParams(ps []*FuncParam) {
+	x.Args = make([]int, len(ps))
+	for i, p := range ps {
+		x.Args[i] = p.Name
+		if p.Default != nil {
+			if x.Defaults == nil {
+				x.Defaults = make([]Expr, len(ps))
+			}
+			x.Defaults[i] = p.Default
+		}
+		if p.ByVal {
+			if x.ByVal == nil {
+				x.ByVal = make([]bool, len(ps))
+			}
+			x.ByVal[i] = true
+		}
+		if p.Type != 0 {
+			if x.ArgTypes == nil {
+				x.ArgTypes = make([]int, len(ps))
+			}
+			x.ArgTypes[i] = p.Type
+		}
+	}
+}
+
+// NewMethodExpr создает метод пользовательского типа, объект передается в него первым параметром ЭтотОбъект
+func NewMethodExpr(name int, ps []*FuncParam, stmts Stmts) *FuncExpr {
+	x := &FuncExpr{Name: name, Stmts: stmts, Method: true}
+	x.SetParams(append([]*FuncParam{{Name: names.UniqueNames.Set(core.ThisObjectName)}}, ps...))
+	return x
 }
 
 func (x *FuncExpr) Simplify() Expr {
+	for i := range x.Defaults {
+		if x.Defaults[i] != nil {
+			x.Defaults[i] = x.Defaults[i].Simplify()
+		}
+	}
 	for i := range x.Stmts {
 		x.Stmts[i].Simplify()
 	}
 	return x
 }
 
+// defaultValues возвращает значения параметров по умолчанию,
+// которые, как и в 1С, должны быть константами
+func (x *FuncExpr) defaultValues() (core.VMSlice, []bool) {
+	if x.Defaults == nil {
+		return nil, nil
+	}
+	vals := make(core.VMSlice, len(x.Args))
+	opt := make([]bool, len(x.Args))
+	for i, d := range x.Defaults {
+		vals[i] = core.VMNil
+		if d == nil {
+			continue
+		}
+		nv, ok := d.Simplify().(*NativeExpr)
+		if !ok {
+			panic(binstmt.NewStringError(x, "Значение параметра по умолчанию должно быть константой"))
+		}
+		vals[i] = nv.Value
+		opt[i] = true
+	}
+	return vals, opt
+}
+
 func (e *FuncExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
 	*lid++
 	lstart := *lid
@@ -659,12 +742,111 @@ func (e *FuncExpr) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool,
 	bins.Append(binstmt.NewBinFUNC(reg, e.Name, e.Args, e.VarArg, lstart, lend, e))
 	bins.Append(binstmt.NewBinLABEL(lstart, e))
 	e.Stmts.BinTo(bins, reg, lid, maxreg)
+	fn := (*bins)[ii].(*binstmt.BinFUNC)
+	fn.Defaults, fn.Optional = e.defaultValues()
+	fn.ByVal = e.ByVal
+	fn.Export = e.Export
+	fn.Method = e.Method
+	fn.ArgTypes = e.ArgTypes
+	fn.RetType = e.RetType
+	if fn.IsAnon() {
+		fn.Upvalues, fn.Assigned = upvalues((*bins)[ii+2:], e.Args)
+	}
+	fn.Generator = hasYield((*bins)[ii+2:])
 	bins.Append(binstmt.NewBinRET(reg, e))
 	bins.Append(binstmt.NewBinLABEL(lend, e))
 	if reg > *maxreg {
 		*maxreg = reg
 	}
-	(*bins)[ii].(*binstmt.BinFUNC).MaxReg = *maxreg
+	fn.MaxReg = *maxreg
+}
+
+// upvalues определяет внешние переменные анонимной функции: ups - имена, которые читаются
+// до первого присваивания, assigned - имена, которым сначала присваивается значение.
+// Имена из assigned захватываются, только если при создании замыкания они уже определены
+// во внешней функции, иначе это локальные переменные. Параметры всегда локальные.
+// Тела вложенных функций не просматриваются, вместо них учитываются их внешние переменные.
+func upvalues(body binstmt.BinStmts, args []int) (ups, assigned []int) {
+	local := make(map[int]bool)
+	for _, a := range args {
+		local[a] = true
+	}
+	use := func(id int) {
+		if id != 0 && !local[id] {
+			local[id] = true
+			ups = append(ups, id)
+		}
+	}
+	assign := func(id int) {
+		if id != 0 && !local[id] {
+			local[id] = true
+			assigned = append(assigned, id)
+		}
+	}
+	skip := -1
+	for _, st := range body {
+		if skip >= 0 {
+			if l, ok := st.(*binstmt.BinLABEL); ok && l.Label == skip {
+				skip = -1
+			}
+			continue
+		}
+		switch s := st.(type) {
+		case *binstmt.BinGET:
+			use(s.Id)
+		case *binstmt.BinCALL:
+			use(s.Name)
+		case *binstmt.BinSET:
+			assign(s.Id)
+		case *binstmt.BinFUNC:
+			for _, id := range s.Upvalues {
+				use(id)
+			}
+			for _, id := range s.Assigned {
+				assign(id)
+			}
+			if !s.IsAnon() {
+				local[s.Name] = true
+			}
+			skip = s.LabelEnd
+		}
+	}
+	return ups, assigned
+}
+
+// hasYield определяет, есть ли Выдать в теле функции, не считая тел вложенных функций
+func hasYield(body binstmt.BinStmts) bool {
+	skip := -1
+	for _, st := range body {
+		if skip >= 0 {
+			if l, ok := st.(*binstmt.BinLABEL); ok && l.Label == skip {
+				skip = -1
+			}
+			continue
+		}
+		switch s := st.(type) {
+		case *binstmt.BinYIELD:
+			return true
+		case *binstmt.BinFUNC:
+			skip = s.LabelEnd
+		}
+	}
+	return false
+}
+
+// markIterVar отмечает переменную цикла у замыканий в теле цикла, которые ее используют,
+// чтобы каждое замыкание получило значение своей итерации
+func markIterVar(body binstmt.BinStmts, id int) {
+	for _, st := range body {
+		if fn, ok := st.(*binstmt.BinFUNC); ok {
+			for _, u := range append(fn.Upvalues[:len(fn.Upvalues):len(fn.Upvalues)], fn.Assigned...) {
+				if u == id {
+					fn.IterVars = append(fn.IterVars, id)
+					break
+				}
+			}
+		}
+	}
 }
 
 // LetExpr provide expression to let variable.
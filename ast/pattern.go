@@ -0,0 +1,278 @@
+package ast
+
+import (
+	"sort"
+
+	"github.com/shinanca/gonec/bincode/binstmt"
+	"github.com/shinanca/gonec/core"
+)
+
+// Шаблоны в ветках оператора Выбор:
+//
+//	Выбор Значение:
+//	Когда 1, 2, 3:            // одно из значений
+//	Когда 10 По 20:           // диапазон, включая границы
+//	Когда Строка:             // тип значения, в том числе пользовательский или интерфейс
+//	Когда [х, у] Если х > у:  // массив из двух элементов и условие
+//	Когда {"имя": и, "возраст": _}: // структура с ключами, _ - любое значение
+//	Когда х Если х < 0:       // новое имя связывается со значением, имя переменной сравнивается с ней
+//	Другое:
+//	КонецВыбора
+//
+// Имя в ветке проверяет тип значения, если это тип или интерфейс, значение существующей переменной
+// сравнивается со значением Выбор, а еще не определенное имя связывается со значением.
+// Имена внутри [...] и {...} всегда связываются с элементами значения.
+// Связанные имена видны только в условии и теле ветки и не меняют переменные с тем же именем
+// снаружи Выбор, а остальные переменные, которым присваиваются значения в ветке, остаются
+// доступны и после нее.
+// Если все ветки - константы без условий, Выбор компилируется в таблицу переходов.
+
+// WildcardName имя в шаблоне, которое совпадает с любым значением и не связывается с ним
+const WildcardName = "_"
+
+// Pattern шаблон ветки Когда
+type Pattern interface {
+	Expr
+	// MatchTo компилирует проверку значения в регистре reg, при несовпадении переход на метку lfail
+	MatchTo(bins *binstmt.BinStmts, reg int, lfail int, lid *int, maxreg *int)
+}
+
+// NewCasePattern преобразует выражение ветки Когда в шаблон
+func NewCasePattern(e Expr) Expr {
+	if x, ok := e.(*IdentExpr); ok {
+		p := &NamePattern{Name: x.Id}
+		p.SetPosition(x.Position())
+		return p
+	}
+	return newNestedPattern(e)
+}
+
+// newNestedPattern преобразует выражения массивов и структур в шаблоны деструктуризации
+func newNestedPattern(e Expr) Expr {
+	switch x := e.(type) {
+	case *IdentExpr:
+		p := &BindPattern{Name: x.Id}
+		if x.Lit == WildcardName {
+			p.Name = 0
+		}
+		p.SetPosition(x.Position())
+		return p
+	case *ArrayExpr:
+		p := &SlicePattern{Items: make([]Expr, len(x.Exprs))}
+		for i, ee := range x.Exprs {
+			p.Items[i] = newNestedPattern(ee)
+		}
+		p.SetPosition(x.Position())
+		return p
+	case *MapExpr:
+		p := &MapPattern{}
+		for k := range x.MapExpr {
+			p.Keys = append(p.Keys, k)
+		}
+		sort.Strings(p.Keys)
+		for _, k := range p.Keys {
+			p.Items = append(p.Items, newNestedPattern(x.MapExpr[k]))
+		}
+		p.SetPosition(x.Position())
+		return p
+	}
+	return e
+}
+
+// bindsNames сообщает, может ли шаблон связать имя со значением
+func bindsNames(e Expr) bool {
+	switch e.(type) {
+	case *NamePattern, *SlicePattern, *MapPattern:
+		return true
+	}
+	return false
+}
+
+// matchTo компилирует проверку шаблона или сравнение со значением выражения
+func matchTo(e Expr, bins *binstmt.BinStmts, reg int, lfail int, lid *int, maxreg *int) {
+	if p, ok := e.(Pattern); ok {
+		p.MatchTo(bins, reg, lfail, lid, maxreg)
+		return
+	}
+	e.BinTo(bins, reg+1, lid, false, maxreg)
+	bins.Append(binstmt.NewBinEQUAL(reg+1, reg, reg+1, e))
+	bins.Append(binstmt.NewBinJFALSE(reg+1, lfail, e))
+	if reg+1 > *maxreg {
+		*maxreg = reg + 1
+	}
+}
+
+// constPattern возвращает значение шаблона-константы, подходящее для таблицы переходов
+func constPattern(e Expr) (core.VMValue, bool) {
+	x, ok := e.(*NativeExpr)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := binstmt.SwitchKey(x.Value); !ok {
+		return nil, false
+	}
+	if _, ok := x.Value.(core.VMDecNum); ok {
+		// дробные значения сравниваются только последовательно
+		return nil, false
+	}
+	return x.Value, true
+}
+
+func patternError(e Expr) {
+	panic(binstmt.NewStringError(e, "Шаблон допустим только в ветке Когда"))
+}
+
+// RangePattern диапазон значений: Когда 1 По 10
+type RangePattern struct {
+	ExprImpl
+	From Expr
+	To   Expr
+}
+
+func (x *RangePattern) Simplify() Expr {
+	x.From = x.From.Simplify()
+	x.To = x.To.Simplify()
+	return x
+}
+
+func (e *RangePattern) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+	patternError(e)
+}
+
+func (e *RangePattern) MatchTo(bins *binstmt.BinStmts, reg int, lfail int, lid *int, maxreg *int) {
+	e.From.BinTo(bins, reg+1, lid, false, maxreg)
+	e.To.BinTo(bins, reg+2, lid, false, maxreg)
+	bins.Append(binstmt.NewBinINRANGE(reg, reg+1, reg+2, reg+1, e))
+	bins.Append(binstmt.NewBinJFALSE(reg+1, lfail, e))
+	if reg+2 > *maxreg {
+		*maxreg = reg + 2
+	}
+}
+
+// TypePattern тип значения: Когда Число
+type TypePattern struct {
+	ExprImpl
+	Type int
+}
+
+func (x *TypePattern) Simplify() Expr { return x }
+
+func (e *TypePattern) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+	patternError(e)
+}
+
+func (e *TypePattern) MatchTo(bins *binstmt.BinStmts, reg int, lfail int, lid *int, maxreg *int) {
+	bins.Append(binstmt.NewBinISTYPE(reg, e.Type, reg+1, e))
+	bins.Append(binstmt.NewBinJFALSE(reg+1, lfail, e))
+	if reg+1 > *maxreg {
+		*maxreg = reg + 1
+	}
+}
+
+// NamePattern имя в ветке Когда: тип, интерфейс, существующая переменная или новое имя
+type NamePattern struct {
+	ExprImpl
+	Name int
+}
+
+func (x *NamePattern) Simplify() Expr { return x }
+
+func (e *NamePattern) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+	patternError(e)
+}
+
+func (e *NamePattern) MatchTo(bins *binstmt.BinStmts, reg int, lfail int, lid *int, maxreg *int) {
+	bins.Append(binstmt.NewBinMATCHNAME(reg, e.Name, reg+1, e))
+	bins.Append(binstmt.NewBinJFALSE(reg+1, lfail, e))
+	if reg+1 > *maxreg {
+		*maxreg = reg + 1
+	}
+}
+
+// BindPattern имя внутри деструктуризации, связывается с элементом значения.
+// Name равен нулю для шаблона _
+type BindPattern struct {
+	ExprImpl
+	Name int
+}
+
+func (x *BindPattern) Simplify() Expr { return x }
+
+func (e *BindPattern) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+	patternError(e)
+}
+
+func (e *BindPattern) MatchTo(bins *binstmt.BinStmts, reg int, lfail int, lid *int, maxreg *int) {
+	if e.Name != 0 {
+		bins.Append(binstmt.NewBinBIND(reg, e.Name, e))
+	}
+}
+
+// SlicePattern массив заданной длины: Когда [а, б]
+type SlicePattern struct {
+	ExprImpl
+	Items []Expr
+}
+
+func (x *SlicePattern) Simplify() Expr {
+	for i := range x.Items {
+		x.Items[i] = x.Items[i].Simplify()
+	}
+	return x
+}
+
+func (e *SlicePattern) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+	patternError(e)
+}
+
+func (e *SlicePattern) MatchTo(bins *binstmt.BinStmts, reg int, lfail int, lid *int, maxreg *int) {
+	bins.Append(binstmt.NewBinMATCHSLICE(reg, len(e.Items), reg+1, e))
+	bins.Append(binstmt.NewBinJFALSE(reg+1, lfail, e))
+	for i, item := range e.Items {
+		if b, ok := item.(*BindPattern); ok && b.Name == 0 {
+			continue
+		}
+		// элемент помещаем в следующий регистр и проверяем его вложенным шаблоном
+		bins.Append(binstmt.NewBinMV(reg, reg+1, item))
+		bins.Append(binstmt.NewBinLOAD(reg+2, core.VMInt(i), false, item))
+		bins.Append(binstmt.NewBinGETIDX(reg+1, reg+2, item))
+		matchTo(item, bins, reg+1, lfail, lid, maxreg)
+	}
+	if reg+2 > *maxreg {
+		*maxreg = reg + 2
+	}
+}
+
+// MapPattern структура с ключами: Когда {"имя": и}.
+// Подходят также соответствия со строковыми ключами и объекты пользовательских типов с такими полями.
+type MapPattern struct {
+	ExprImpl
+	Keys  []string
+	Items []Expr
+}
+
+func (x *MapPattern) Simplify() Expr {
+	for i := range x.Items {
+		x.Items[i] = x.Items[i].Simplify()
+	}
+	return x
+}
+
+func (e *MapPattern) BinTo(bins *binstmt.BinStmts, reg int, lid *int, inStmt bool, maxreg *int) {
+	patternError(e)
+}
+
+func (e *MapPattern) MatchTo(bins *binstmt.BinStmts, reg int, lfail int, lid *int, maxreg *int) {
+	if len(e.Keys) == 0 {
+		bins.Append(binstmt.NewBinMATCHKEY(reg, "", reg+1, reg+2, e))
+		bins.Append(binstmt.NewBinJFALSE(reg+2, lfail, e))
+	}
+	for i, k := range e.Keys {
+		bins.Append(binstmt.NewBinMATCHKEY(reg, k, reg+1, reg+2, e.Items[i]))
+		bins.Append(binstmt.NewBinJFALSE(reg+2, lfail, e.Items[i]))
+		matchTo(e.Items[i], bins, reg+1, lfail, lid, maxreg)
+	}
+	if reg+2 > *maxreg {
+		*maxreg = reg + 2
+	}
+}
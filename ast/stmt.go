@@ -255,7 +255,9 @@ func (s *ForStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
 	// устанавливаем переменную-итератор
 	bins.Append(binstmt.NewBinSET(regval, s.Var, s))
 
+	ib := len(*bins)
 	s.Stmts.BinTo(bins, regsub, lid, maxreg)
+	markIterVar((*bins)[ib:], s.Var)
 
 	// повторяем итерацию
 	bins.Append(binstmt.NewBinJMP(li, s))
@@ -333,7 +335,9 @@ func (s *NumForStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 	// устанавливаем переменную-итератор
 	bins.Append(binstmt.NewBinSET(reg, s.Name, s))
 
+	ib := len(*bins)
 	s.Stmts.BinTo(bins, regsub, lid, maxreg)
+	markIterVar((*bins)[ib:], s.Name)
 	// повторяем итерацию
 	bins.Append(binstmt.NewBinJMP(li, s))
 
@@ -492,6 +496,24 @@ func (s *ThrowStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int
 	}
 }
 
+// YieldStmt оператор Выдать: передает значение потребителю генератора
+type YieldStmt struct {
+	StmtImpl
+	Expr Expr
+}
+
+func (x *YieldStmt) Simplify() {
+	x.Expr = x.Expr.Simplify()
+}
+
+func (s *YieldStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+	s.Expr.BinTo(bins, reg, lid, false, maxreg)
+	bins.Append(binstmt.NewBinYIELD(reg, s))
+	if reg > *maxreg {
+		*maxreg = reg
+	}
+}
+
 // ModuleStmt provide "module" expression statement.
 type ModuleStmt struct {
 	StmtImpl
@@ -530,6 +552,96 @@ func (s *ModuleStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 	}
 }
 
+// ClassMember - поле или метод в объявлении пользовательского типа
+type ClassMember struct {
+	Field   int // string
+	Type    int // string, аннотация типа поля
+	Default Expr
+	Method  *FuncExpr
+}
+
+// ClassStmt объявление пользовательского типа: Класс Имя [Реализует Интерфейс, ...] ... КонецКласса
+type ClassStmt struct {
+	StmtImpl
+	Name       int // string
+	Implements []int
+	Fields     []int
+	FieldTypes []int // аннотации типов полей, 0 - без аннотации
+	Defaults   []Expr
+	Methods    []*FuncExpr
+}
+
+func NewClassStmt(name int, implements []int, members []*ClassMember) *ClassStmt {
+	s := &ClassStmt{Name: name, Implements: implements}
+	for _, m := range members {
+		if m.Method != nil {
+			s.Methods = append(s.Methods, m.Method)
+			continue
+		}
+		d := m.Default
+		if d == nil {
+			d = &ConstExpr{Value: "неопределено"}
+		}
+		s.Fields = append(s.Fields, m.Field)
+		s.FieldTypes = append(s.FieldTypes, m.Type)
+		s.Defaults = append(s.Defaults, d)
+	}
+	return s
+}
+
+func (x *ClassStmt) Simplify() {
+	for i := range x.Defaults {
+		x.Defaults[i] = x.Defaults[i].Simplify()
+	}
+	for _, m := range x.Methods {
+		m.Simplify()
+	}
+}
+
+func (s *ClassStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+	r := reg + 1
+	for _, d := range s.Defaults {
+		d.BinTo(bins, r, lid, false, maxreg)
+		r++
+	}
+	methods := make([]int, len(s.Methods))
+	methodargs := make([]int, len(s.Methods))
+	for i, m := range s.Methods {
+		m.BinTo(bins, r, lid, false, maxreg)
+		methods[i] = m.Name
+		// первый параметр - ЭтотОбъект
+		methodargs[i] = len(m.Args) - 1
+		r++
+	}
+	bins.Append(binstmt.NewBinCLASS(reg, s.Name, s.Fields, methods, methodargs, s.Implements, s))
+	if r > *maxreg {
+		*maxreg = r
+	}
+}
+
+// InterfaceStmt объявление интерфейса: Интерфейс Имя Функция Метод(Параметры) ... КонецИнтерфейса
+type InterfaceStmt struct {
+	StmtImpl
+	Name    int // string
+	Methods []Expr
+}
+
+func (x *InterfaceStmt) Simplify() {}
+
+func (s *InterfaceStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
+	methods := make([]int, len(s.Methods))
+	numargs := make([]int, len(s.Methods))
+	for i, m := range s.Methods {
+		fn := m.(*FuncExpr)
+		methods[i] = fn.Name
+		numargs[i] = len(fn.Args)
+		if fn.VarArg {
+			numargs[i] = -1
+		}
+	}
+	bins.Append(binstmt.NewBinINTERFACE(s.Name, methods, numargs, s))
+}
+
 // SwitchStmt provide switch statement.
 type SwitchStmt struct {
 	StmtImpl
@@ -546,24 +658,61 @@ func (x *SwitchStmt) Simplify() {
 
 func (s *SwitchStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int) {
 	s.Expr.BinTo(bins, reg, lid, true, maxreg)
-	// сравниваем с каждым case
 	*lid++
 	lend := *lid
 	var default_stmt *DefaultStmt
 	for _, ss := range s.Cases {
 		if ssd, ok := ss.(*DefaultStmt); ok {
 			default_stmt = ssd
+		}
+	}
+	if s.jumpTable(bins, reg, lid, lend, default_stmt, maxreg) {
+		return
+	}
+	// сопоставляем с шаблонами каждого case по порядку
+	for _, ss := range s.Cases {
+		case_stmt, ok := ss.(*CaseStmt)
+		if !ok {
 			continue
 		}
 		*lid++
 		li := *lid
-		case_stmt := ss.(*CaseStmt)
-		case_stmt.Expr.BinTo(bins, reg+1, lid, false, maxreg)
-		bins.Append(binstmt.NewBinEQUAL(reg+2, reg, reg+1, case_stmt))
-		bins.Append(binstmt.NewBinJFALSE(reg+2, li, case_stmt))
+		*lid++
+		lbody := *lid
+		// имена, связанные шаблонами, определяются в отдельном окружении ветки
+		scoped := false
+		for _, p := range case_stmt.Patterns {
+			scoped = scoped || bindsNames(p)
+		}
+		if scoped {
+			bins.Append(binstmt.NewBinPUSHSCOPE(case_stmt))
+		}
+		for i, p := range case_stmt.Patterns {
+			if i == len(case_stmt.Patterns)-1 {
+				matchTo(p, bins, reg, li, lid, maxreg)
+				break
+			}
+			// при несовпадении проверяем следующий шаблон ветки
+			*lid++
+			lnext := *lid
+			matchTo(p, bins, reg, lnext, lid, maxreg)
+			bins.Append(binstmt.NewBinJMP(lbody, p))
+			bins.Append(binstmt.NewBinLABEL(lnext, p))
+		}
+		bins.Append(binstmt.NewBinLABEL(lbody, case_stmt))
+		if case_stmt.Guard != nil {
+			case_stmt.Guard.BinTo(bins, reg+1, lid, false, maxreg)
+			bins.Append(binstmt.NewBinJFALSE(reg+1, li, case_stmt.Guard))
+		}
 		case_stmt.Stmts.BinTo(bins, reg, lid, maxreg)
+		if scoped {
+			bins.Append(binstmt.NewBinPOPSCOPE(case_stmt))
+		}
 		bins.Append(binstmt.NewBinJMP(lend, case_stmt))
 		bins.Append(binstmt.NewBinLABEL(li, case_stmt))
+		if scoped {
+			bins.Append(binstmt.NewBinPOPSCOPE(case_stmt))
+		}
 	}
 	if default_stmt != nil {
 		default_stmt.Stmts.BinTo(bins, reg, lid, maxreg)
@@ -576,6 +725,56 @@ func (s *SwitchStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 	}
 }
 
+// jumpTable компилирует Выбор в таблицу переходов, если все ветки - константы без условий
+func (s *SwitchStmt) jumpTable(bins *binstmt.BinStmts, reg int, lid *int, lend int, default_stmt *DefaultStmt, maxreg *int) bool {
+	var vals core.VMSlice
+	var labels []int
+	lcases := make([]int, 0, len(s.Cases))
+	for _, ss := range s.Cases {
+		case_stmt, ok := ss.(*CaseStmt)
+		if !ok {
+			continue
+		}
+		if case_stmt.Guard != nil {
+			return false
+		}
+		*lid++
+		lcases = append(lcases, *lid)
+		for _, p := range case_stmt.Patterns {
+			v, ok := constPattern(p)
+			if !ok {
+				return false
+			}
+			vals = append(vals, v)
+			labels = append(labels, *lid)
+		}
+	}
+	if len(vals) == 0 {
+		return false
+	}
+	*lid++
+	ldefault := *lid
+	bins.Append(binstmt.NewBinSWITCH(reg, vals, labels, ldefault, s))
+	i := 0
+	for _, ss := range s.Cases {
+		if case_stmt, ok := ss.(*CaseStmt); ok {
+			bins.Append(binstmt.NewBinLABEL(lcases[i], case_stmt))
+			case_stmt.Stmts.BinTo(bins, reg, lid, maxreg)
+			bins.Append(binstmt.NewBinJMP(lend, case_stmt))
+			i++
+		}
+	}
+	bins.Append(binstmt.NewBinLABEL(ldefault, s))
+	if default_stmt != nil {
+		default_stmt.Stmts.BinTo(bins, reg, lid, maxreg)
+	}
+	bins.Append(binstmt.NewBinLABEL(lend, s))
+	if reg > *maxreg {
+		*maxreg = reg
+	}
+	return true
+}
+
 // SelectStmt provide switch statement.
 type SelectStmt struct {
 	StmtImpl
@@ -605,7 +804,7 @@ func (s *SelectStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 		li := *lid
 		case_stmt := ss.(*CaseStmt)
 		e, ok := case_stmt.Expr.(*ChanExpr)
-		if !ok {
+		if !ok || len(case_stmt.Patterns) > 1 || case_stmt.Guard != nil {
 			panic(binstmt.NewStringError(case_stmt, "При выборе вариантов из каналов допустимы только выражения с каналами"))
 		}
 		// определяем значение справа
@@ -683,12 +882,29 @@ func (s *SelectStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *in
 // CaseStmt provide switch/case statement.
 type CaseStmt struct {
 	StmtImpl
-	Expr  Expr
-	Stmts Stmts
+	Expr     Expr   // выражение с каналом в Выбор без значения
+	Patterns []Expr // шаблоны ветки, достаточно совпадения одного из них
+	Guard    Expr   // условие Если после шаблонов
+	Stmts    Stmts
+}
+
+// NewCaseStmt создает ветку Когда со списком выражений, которые преобразуются в шаблоны
+func NewCaseStmt(exprs []Expr, guard Expr, stmts Stmts) *CaseStmt {
+	s := &CaseStmt{Expr: exprs[0], Guard: guard, Stmts: stmts}
+	for _, e := range exprs {
+		s.Patterns = append(s.Patterns, NewCasePattern(e))
+	}
+	return s
 }
 
 func (x *CaseStmt) Simplify() {
 	x.Expr = x.Expr.Simplify()
+	for i := range x.Patterns {
+		x.Patterns[i] = x.Patterns[i].Simplify()
+	}
+	if x.Guard != nil {
+		x.Guard = x.Guard.Simplify()
+	}
 	for _, st := range x.Stmts {
 		st.Simplify()
 	}
@@ -795,6 +1011,7 @@ func (s *LetsStmt) BinTo(bins *binstmt.BinStmts, reg int, lid *int, maxreg *int)
 type VarStmt struct {
 	StmtImpl
 	Names []int // string
+	Types []int // string, аннотации типов переменных (Перем Имя: Тип = Значение), проверяются командой gonec -check
 	Exprs []Expr
 }
 
@@ -9,14 +9,25 @@ import (
 type VMRegs struct {
 	Env *core.Env
 	// Reg          []core.VMValuer // регистры значений
-	Labels       []int // [label]=index в BinCode
-	TryLabel     []int // последний элемент - это метка на текущий обработчик CATCH
-	TryRegErr    []int // последний элемент - это регистр с ошибкой текущего обработчика
-	ForBreaks    []int // последний элемент - это метка для break
-	ForContinues []int // последний элемент - это метка для continue
+	Labels       []int       // [label]=index в BinCode
+	TryLabel     []int       // последний элемент - это метка на текущий обработчик CATCH
+	TryRegErr    []int       // последний элемент - это регистр с ошибкой текущего обработчика
+	ForBreaks    []int       // последний элемент - это метка для break
+	ForContinues []int       // последний элемент - это метка для continue
+	Iters        []openIter  // итераторы с ресурсами в открытых циклах Для каждого
+	TryIters     []int       // число открытых итераторов при входе в обработчик ошибок
+	Scopes       []*core.Env // окружения, действовавшие до входа в ветки Когда
+	TryScopes    []int       // глубина Scopes при входе в обработчик ошибок
+	ForScopes    []int       // глубина Scopes при входе в цикл
 	// ReturnTo     []int           // стек возвратов по RET
 }
 
+// openIter итератор цикла, который нужно закрыть при выходе из цикла
+type openIter struct {
+	label int // метка продолжения цикла, по ней POPFOR находит свой итератор
+	it    core.VMIteratorCloser
+}
+
 // func (v *VMRegs) FreeFromReg(reg int) {
 // 	// освобождаем память, начиная с reg, для сборщика мусора
 // 	// v.Reg = v.Reg[:reg]
@@ -33,6 +44,17 @@ type VMRegs struct {
 func (v *VMRegs) PushTry(reg, label int) {
 	v.TryRegErr = append(v.TryRegErr, reg)
 	v.TryLabel = append(v.TryLabel, label)
+	v.TryIters = append(v.TryIters, len(v.Iters))
+	v.TryScopes = append(v.TryScopes, len(v.Scopes))
+}
+
+// TopTryIters возвращает число итераторов, открытых до входа в текущий обработчик ошибок
+func (v *VMRegs) TopTryIters() int {
+	l := len(v.TryIters)
+	if l == 0 {
+		return 0
+	}
+	return v.TryIters[l-1]
 }
 
 func (v *VMRegs) TopTryLabel() int {
@@ -52,9 +74,20 @@ func (v *VMRegs) PopTry() (reg int, label int) {
 	v.TryRegErr = v.TryRegErr[0 : l-1]
 	label = v.TryLabel[l-1]
 	v.TryLabel = v.TryLabel[0 : l-1]
+	v.TryIters = v.TryIters[0 : l-1]
+	v.TryScopes = v.TryScopes[0 : l-1]
 	return
 }
 
+// TopTryScopes возвращает глубину окружений веток Когда при входе в текущий обработчик ошибок
+func (v *VMRegs) TopTryScopes() int {
+	l := len(v.TryScopes)
+	if l == 0 {
+		return 0
+	}
+	return v.TryScopes[l-1]
+}
+
 func (v *VMRegs) PushBreak(label int) {
 	v.ForBreaks = append(v.ForBreaks, label)
 }
@@ -79,6 +112,16 @@ func (v *VMRegs) PopBreak() (label int) {
 
 func (v *VMRegs) PushContinue(label int) {
 	v.ForContinues = append(v.ForContinues, label)
+	v.ForScopes = append(v.ForScopes, len(v.Scopes))
+}
+
+// TopForScopes возвращает глубину окружений веток Когда при входе в текущий цикл
+func (v *VMRegs) TopForScopes() int {
+	l := len(v.ForScopes)
+	if l == 0 {
+		return 0
+	}
+	return v.ForScopes[l-1]
 }
 
 func (v *VMRegs) TopContinue() int {
@@ -95,6 +138,50 @@ func (v *VMRegs) PopContinue() (label int) {
 		return -1
 	}
 	label = v.ForContinues[l-1]
-	v.ForBreaks = v.ForContinues[0 : l-1]
+	v.ForContinues = v.ForContinues[0 : l-1]
+	v.ForScopes = v.ForScopes[0 : l-1]
 	return
 }
+
+func (v *VMRegs) PushIter(label int, it core.VMIteratorCloser) {
+	v.Iters = append(v.Iters, openIter{label: label, it: it})
+}
+
+// PopIter снимает со стека итератор цикла с меткой label, если цикл его открывал
+func (v *VMRegs) PopIter(label int) core.VMIteratorCloser {
+	l := len(v.Iters)
+	if l == 0 || v.Iters[l-1].label != label {
+		return nil
+	}
+	it := v.Iters[l-1].it
+	v.Iters = v.Iters[0 : l-1]
+	return it
+}
+
+// CloseIters закрывает итераторы, открытые сверх depth, начиная с вложенных циклов.
+// Возвращает первую ошибку закрытия
+func (v *VMRegs) CloseIters(depth int) (err error) {
+	for l := len(v.Iters); l > depth; l-- {
+		if e := v.Iters[l-1].it.Close(); e != nil && err == nil {
+			err = e
+		}
+		v.Iters = v.Iters[0 : l-1]
+	}
+	return
+}
+
+// PushScope запоминает окружение env и возвращает окружение ветки Когда
+func (v *VMRegs) PushScope(env *core.Env) *core.Env {
+	v.Scopes = append(v.Scopes, env)
+	return env.NewPatternEnv()
+}
+
+// PopScopes возвращает окружение, действовавшее до веток Когда, открытых сверх depth,
+// например, при выходе из ветки по Прервать или по ошибке. Если таких веток нет, возвращает env
+func (v *VMRegs) PopScopes(depth int, env *core.Env) *core.Env {
+	if len(v.Scopes) > depth {
+		env = v.Scopes[depth]
+		v.Scopes = v.Scopes[0:depth]
+	}
+	return env
+}
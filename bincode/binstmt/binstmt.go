@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/shinanca/gonec/core"
@@ -178,6 +179,7 @@ func init() {
 	gob.Register(&BinJTRUE{})
 	gob.Register(&BinJFALSE{})
 	gob.Register(&BinOPER{})
+	gob.Register(&BinOPINT{})
 	gob.Register(&BinCALL{})
 	gob.Register(&BinGETMEMBER{})
 	gob.Register(&BinGETIDX{})
@@ -191,11 +193,21 @@ func init() {
 	gob.Register(&BinCHANSEND{})
 	gob.Register(&BinISKIND{})
 	gob.Register(&BinISSLICE{})
+	gob.Register(&BinISTYPE{})
+	gob.Register(&BinMATCHNAME{})
+	gob.Register(&BinBIND{})
+	gob.Register(&BinINRANGE{})
+	gob.Register(&BinMATCHSLICE{})
+	gob.Register(&BinMATCHKEY{})
+	gob.Register(&BinPUSHSCOPE{})
+	gob.Register(&BinPOPSCOPE{})
+	gob.Register(&BinSWITCH{})
 	gob.Register(&BinTRY{})
 	gob.Register(&BinCATCH{})
 	gob.Register(&BinPOPTRY{})
 	gob.Register(&BinFOREACH{})
 	gob.Register(&BinNEXT{})
+	gob.Register(&BinYIELD{})
 	gob.Register(&BinPOPFOR{})
 	gob.Register(&BinFORNUM{})
 	gob.Register(&BinNEXTNUM{})
@@ -205,6 +217,8 @@ func init() {
 	gob.Register(&BinRET{})
 	gob.Register(&BinTHROW{})
 	gob.Register(&BinMODULE{})
+	gob.Register(&BinCLASS{})
+	gob.Register(&BinINTERFACE{})
 	gob.Register(&BinERROR{})
 	gob.Register(&BinTRYRECV{})
 	gob.Register(&BinTRYSEND{})
@@ -770,6 +784,30 @@ func NewBinOPER(regl, regr int, op core.VMOperation, e pos.Pos) *BinOPER {
 	return v
 }
 
+// BinOPINT - операция, оба операнда которой по результатам проверки типов целые числа.
+// Если во время исполнения там окажутся значения других типов, выполняется как BinOPER.
+type BinOPINT struct {
+	BinStmtImpl
+
+	RegL int // сюда же помещается результат
+	RegR int
+	Op   core.VMOperation
+}
+
+func (v BinOPINT) String() string {
+	return fmt.Sprintf("OPINT r%d, %q, r%d", v.RegL, core.OperMapR[v.Op], v.RegR)
+}
+
+func NewBinOPINT(regl, regr int, op core.VMOperation, e pos.Pos) *BinOPINT {
+	v := &BinOPINT{
+		RegL: regl,
+		RegR: regr,
+		Op:   op,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
 type BinCALL struct {
 	BinStmtImpl
 
@@ -899,6 +937,27 @@ type BinFUNC struct {
 	VarArg     bool
 	// ReturnTo int //метка инструкции возврата из функции
 	MaxReg int // максимальный регистр, достигаемый внутри функции, без учета вызова вложенных функций
+
+	Defaults core.VMSlice // значения параметров по умолчанию
+	Optional []bool       // параметры, которые можно не передавать
+	ByVal    []bool       // параметры Знач, массивы и структуры копируются
+	Export   bool         // функция экспортируется из модуля
+	Method   bool         // метод пользовательского типа, в окружении не определяется
+	ArgTypes []int        // аннотации типов параметров, 0 - без аннотации
+	RetType  int          // аннотация типа возвращаемого значения
+	// в теле есть Выдать: вызов функции возвращает генератор,
+	// а тело выполняется по мере перебора его значений
+	Generator bool
+
+	// внешние переменные, которые читает анонимная функция (upvalues),
+	// определяются при компиляции и захватываются по ссылке при создании замыкания
+	Upvalues []int
+	// переменные, которым анонимная функция присваивает значение до чтения,
+	// захватываются, только если уже определены во внешней функции
+	Assigned []int
+	// переменные циклов, внутри которых создается замыкание,
+	// захватываются по значению на каждой итерации
+	IterVars []int
 }
 
 func (v *BinFUNC) SwapId(m map[int]int) {
@@ -912,21 +971,61 @@ func (v *BinFUNC) SwapId(m map[int]int) {
 			// log.Printf("Замена в аргументах %#v %v\n",v, v)
 		}
 	}
+	for i := range v.Upvalues {
+		if newid, ok := m[v.Upvalues[i]]; ok && v.Upvalues[i] != 0 {
+			v.Upvalues[i] = newid
+		}
+	}
+	for i := range v.IterVars {
+		if newid, ok := m[v.IterVars[i]]; ok && v.IterVars[i] != 0 {
+			v.IterVars[i] = newid
+		}
+	}
+	swapIds(v.Assigned, m)
+	swapIds(v.ArgTypes, m)
+	if newid, ok := m[v.RetType]; ok && v.RetType != 0 {
+		v.RetType = newid
+	}
 }
 
 func (v BinFUNC) String() string {
 	s := ""
-	for _, a := range v.Args {
+	for i, a := range v.Args {
 		if s != "" {
 			s += ", "
 		}
 		s += names.UniqueNames.Get(a)
+		if v.ArgTypes != nil && v.ArgTypes[i] != 0 {
+			s += ": " + names.UniqueNames.Get(v.ArgTypes[i])
+		}
 	}
 	vrg := ""
 	if v.VarArg {
 		vrg = "..."
 	}
-	return fmt.Sprintf("FUNC r%d, %q (%s%s) BEGIN L%d END L%d", v.Reg, names.UniqueNames.Get(v.Name), s, vrg, v.LabelStart, v.LabelEnd)
+	up := ""
+	for _, a := range append(v.Upvalues[:len(v.Upvalues):len(v.Upvalues)], v.Assigned...) {
+		if up != "" {
+			up += ", "
+		}
+		up += names.UniqueNames.Get(a)
+	}
+	ret := ""
+	if v.RetType != 0 {
+		ret = ": " + names.UniqueNames.Get(v.RetType)
+	}
+	gen := ""
+	if v.Generator {
+		gen = " GENERATOR"
+	}
+	return fmt.Sprintf("FUNC r%d, %q (%s%s)%s%s UPVALUES (%s) BEGIN L%d END L%d", v.Reg, names.UniqueNames.Get(v.Name), s, vrg, ret, gen, up, v.LabelStart, v.LabelEnd)
+}
+
+// AnonFuncName - имя, которое парсер присваивает анонимным функциям
+const AnonFuncName = "<анонимная функция>"
+
+func (v *BinFUNC) IsAnon() bool {
+	return v.Name == 0 || names.UniqueNames.Get(v.Name) == AnonFuncName
 }
 
 func NewBinFUNC(reg, name int, args []int, vararg bool, lbeg, lend int, e pos.Pos) *BinFUNC {
@@ -1100,6 +1199,263 @@ func NewBinISSLICE(reg, regbool int, e pos.Pos) *BinISSLICE {
 	return v
 }
 
+// BinISTYPE проверяет тип значения в ветке Когда Тип
+type BinISTYPE struct {
+	BinStmtImpl
+
+	Reg     int // значение для проверки
+	Type    int // имя типа или интерфейса
+	RegBool int // сюда возвращается bool
+}
+
+func (v *BinISTYPE) SwapId(m map[int]int) {
+	if newid, ok := m[v.Type]; ok {
+		v.Type = newid
+	}
+}
+
+func (v BinISTYPE) String() string {
+	return fmt.Sprintf("ISTYPE r%d, r%d is %q", v.RegBool, v.Reg, names.UniqueNames.Get(v.Type))
+}
+
+func NewBinISTYPE(reg, typ, regbool int, e pos.Pos) *BinISTYPE {
+	v := &BinISTYPE{
+		Reg:     reg,
+		Type:    typ,
+		RegBool: regbool,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinMATCHNAME сопоставляет значение с именем в ветке Когда:
+// для интерфейса и типа проверяется тип значения, существующая переменная сравнивается по значению,
+// иначе новое имя связывается со значением в окружении ветки.
+type BinMATCHNAME struct {
+	BinStmtImpl
+
+	Reg     int
+	Name    int
+	RegBool int
+}
+
+func (v *BinMATCHNAME) SwapId(m map[int]int) {
+	if newid, ok := m[v.Name]; ok {
+		v.Name = newid
+	}
+}
+
+func (v BinMATCHNAME) String() string {
+	return fmt.Sprintf("MATCHNAME r%d, r%d ~ %q", v.RegBool, v.Reg, names.UniqueNames.Get(v.Name))
+}
+
+func NewBinMATCHNAME(reg, name int, regbool int, e pos.Pos) *BinMATCHNAME {
+	v := &BinMATCHNAME{
+		Reg:     reg,
+		Name:    name,
+		RegBool: regbool,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinBIND связывает имя из шаблона деструктуризации со значением в окружении ветки Когда
+type BinBIND struct {
+	BinStmtImpl
+
+	Reg  int
+	Name int
+}
+
+func (v *BinBIND) SwapId(m map[int]int) {
+	if newid, ok := m[v.Name]; ok {
+		v.Name = newid
+	}
+}
+
+func (v BinBIND) String() string {
+	return fmt.Sprintf("BIND %q, r%d", names.UniqueNames.Get(v.Name), v.Reg)
+}
+
+func NewBinBIND(reg, name int, e pos.Pos) *BinBIND {
+	v := &BinBIND{
+		Reg:  reg,
+		Name: name,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinPUSHSCOPE создает окружение ветки Когда для имен, связанных шаблонами
+type BinPUSHSCOPE struct {
+	BinStmtImpl
+}
+
+func (v BinPUSHSCOPE) String() string {
+	return fmt.Sprintf("PUSHSCOPE")
+}
+
+func NewBinPUSHSCOPE(e pos.Pos) *BinPUSHSCOPE {
+	v := &BinPUSHSCOPE{}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinPOPSCOPE возвращает окружение, действовавшее до ветки Когда
+type BinPOPSCOPE struct {
+	BinStmtImpl
+}
+
+func (v BinPOPSCOPE) String() string {
+	return fmt.Sprintf("POPSCOPE")
+}
+
+func NewBinPOPSCOPE(e pos.Pos) *BinPOPSCOPE {
+	v := &BinPOPSCOPE{}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinINRANGE проверяет, что значение находится в диапазоне включая границы
+type BinINRANGE struct {
+	BinStmtImpl
+
+	Reg     int
+	RegFrom int
+	RegTo   int
+	RegBool int
+}
+
+func (v BinINRANGE) String() string {
+	return fmt.Sprintf("INRANGE r%d, r%d in [r%d, r%d]", v.RegBool, v.Reg, v.RegFrom, v.RegTo)
+}
+
+func NewBinINRANGE(reg, regfrom, regto, regbool int, e pos.Pos) *BinINRANGE {
+	v := &BinINRANGE{
+		Reg:     reg,
+		RegFrom: regfrom,
+		RegTo:   regto,
+		RegBool: regbool,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinMATCHSLICE проверяет, что значение - массив длины Len
+type BinMATCHSLICE struct {
+	BinStmtImpl
+
+	Reg     int
+	Len     int
+	RegBool int
+}
+
+func (v BinMATCHSLICE) String() string {
+	return fmt.Sprintf("MATCHSLICE r%d, r%d len %d", v.RegBool, v.Reg, v.Len)
+}
+
+func NewBinMATCHSLICE(reg, ln, regbool int, e pos.Pos) *BinMATCHSLICE {
+	v := &BinMATCHSLICE{
+		Reg:     reg,
+		Len:     ln,
+		RegBool: regbool,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinMATCHKEY получает значение ключа структуры, соответствия или поля объекта, если оно есть.
+// При пустом ключе проверяется только то, что значение является структурой или соответствием.
+type BinMATCHKEY struct {
+	BinStmtImpl
+
+	Reg     int
+	Key     string
+	RegVal  int
+	RegBool int
+}
+
+func (v BinMATCHKEY) String() string {
+	return fmt.Sprintf("MATCHKEY r%d, r%d = r%d[%q]", v.RegBool, v.RegVal, v.Reg, v.Key)
+}
+
+func NewBinMATCHKEY(reg int, key string, regval, regbool int, e pos.Pos) *BinMATCHKEY {
+	v := &BinMATCHKEY{
+		Reg:     reg,
+		Key:     key,
+		RegVal:  regval,
+		RegBool: regbool,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinSWITCH таблица переходов для Выбор, в котором все ветки - константы.
+// Поддерживаются целые числа, строки и булево, целое значение ЧислоСТочкой совпадает с ЧислоЦелое.
+type BinSWITCH struct {
+	BinStmtImpl
+
+	Reg     int
+	Values  core.VMSlice
+	Labels  []int // метки веток для значений с тем же индексом
+	Default int   // метка ветки Другое или конца оператора
+
+	once  sync.Once
+	table map[interface{}]int
+}
+
+// SwitchKey возвращает ключ значения в таблице переходов
+func SwitchKey(x core.VMValue) (interface{}, bool) {
+	switch v := x.(type) {
+	case core.VMInt:
+		return int64(v), true
+	case core.VMString:
+		return string(v), true
+	case core.VMBool:
+		return bool(v), true
+	case core.VMDecNum:
+		if i := v.Int(); bool(v.Equal(core.NewVMDecNumFromInt64(i))) {
+			return i, true
+		}
+	}
+	return nil, false
+}
+
+// JumpTo возвращает метку, на которую нужно перейти для значения
+func (v *BinSWITCH) JumpTo(x core.VMValue) int {
+	v.once.Do(func() {
+		v.table = make(map[interface{}]int, len(v.Values))
+		for i, val := range v.Values {
+			k, _ := SwitchKey(val)
+			if _, ok := v.table[k]; !ok {
+				// при повторе значения срабатывает первая ветка
+				v.table[k] = v.Labels[i]
+			}
+		}
+	})
+	if k, ok := SwitchKey(x); ok {
+		if lb, ok := v.table[k]; ok {
+			return lb
+		}
+	}
+	return v.Default
+}
+
+func (v *BinSWITCH) String() string {
+	return fmt.Sprintf("SWITCH r%d, %d values, default L%d", v.Reg, len(v.Values), v.Default)
+}
+
+func NewBinSWITCH(reg int, vals core.VMSlice, labels []int, def int, e pos.Pos) *BinSWITCH {
+	v := &BinSWITCH{
+		Reg:     reg,
+		Values:  vals,
+		Labels:  labels,
+		Default: def,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
 type BinTRY struct {
 	BinStmtImpl
 
@@ -1226,6 +1582,24 @@ func NewBinPOPFOR(lb int, e pos.Pos) *BinPOPFOR {
 	return v
 }
 
+type BinYIELD struct {
+	BinStmtImpl
+
+	Reg int // значение, передаваемое потребителю генератора
+}
+
+func (v BinYIELD) String() string {
+	return fmt.Sprintf("YIELD r%d", v.Reg)
+}
+
+func NewBinYIELD(reg int, e pos.Pos) *BinYIELD {
+	v := &BinYIELD{
+		Reg: reg,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
 type BinFORNUM struct {
 	BinStmtImpl
 
@@ -1388,6 +1762,95 @@ func NewBinMODULE(name int, code BinCode, e pos.Pos) *BinMODULE {
 	return v
 }
 
+func swapIds(ids []int, m map[int]int) {
+	for i := range ids {
+		if newid, ok := m[ids[i]]; ok && ids[i] != 0 {
+			ids[i] = newid
+		}
+	}
+}
+
+func joinNames(ids []int) string {
+	s := ""
+	for _, id := range ids {
+		if s != "" {
+			s += ", "
+		}
+		s += names.UniqueNames.Get(id)
+	}
+	return s
+}
+
+// BinCLASS объявляет пользовательский тип.
+// Начальные значения полей находятся в регистрах начиная с Reg+1, за ними методы.
+type BinCLASS struct {
+	BinStmtImpl
+
+	Reg        int
+	Name       int
+	Fields     []int
+	Methods    []int
+	MethodArgs []int // количество параметров методов без ЭтотОбъект, -1 - переменное
+	Implements []int // интерфейсы
+}
+
+func (v *BinCLASS) SwapId(m map[int]int) {
+	if newid, ok := m[v.Name]; ok {
+		v.Name = newid
+	}
+	swapIds(v.Fields, m)
+	swapIds(v.Methods, m)
+	swapIds(v.Implements, m)
+}
+
+func (v BinCLASS) String() string {
+	return fmt.Sprintf("CLASS %s IMPLEMENTS (%s) FIELDS (%s) r%d METHODS (%s)", names.UniqueNames.Get(v.Name),
+		joinNames(v.Implements), joinNames(v.Fields), v.Reg+1, joinNames(v.Methods))
+}
+
+func NewBinCLASS(reg, name int, fields, methods, methodargs, implements []int, e pos.Pos) *BinCLASS {
+	v := &BinCLASS{
+		Reg:        reg,
+		Name:       name,
+		Fields:     fields,
+		Methods:    methods,
+		MethodArgs: methodargs,
+		Implements: implements,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
+// BinINTERFACE объявляет интерфейс в текущем окружении
+type BinINTERFACE struct {
+	BinStmtImpl
+
+	Name    int
+	Methods []int
+	NumArgs []int
+}
+
+func (v *BinINTERFACE) SwapId(m map[int]int) {
+	if newid, ok := m[v.Name]; ok {
+		v.Name = newid
+	}
+	swapIds(v.Methods, m)
+}
+
+func (v BinINTERFACE) String() string {
+	return fmt.Sprintf("INTERFACE %s (%s)", names.UniqueNames.Get(v.Name), joinNames(v.Methods))
+}
+
+func NewBinINTERFACE(name int, methods, numargs []int, e pos.Pos) *BinINTERFACE {
+	v := &BinINTERFACE{
+		Name:    name,
+		Methods: methods,
+		NumArgs: numargs,
+	}
+	v.SetPosition(e.Position())
+	return v
+}
+
 type BinERROR struct {
 	BinStmtImpl
 
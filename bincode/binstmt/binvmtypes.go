@@ -19,6 +19,8 @@ var (
 	ContinueError  = errors.New("Неверное применение оператора Продолжить")
 	ReturnError    = errors.New("Неверное применение оператора Возврат")
 	InterruptError = errors.New("Выполнение прервано")
+	// GeneratorClosedError завершает тело генератора, закрытого потребителем
+	GeneratorClosedError = errors.New("Генератор закрыт")
 )
 
 // NewStringError makes error interface with message.
@@ -40,7 +42,7 @@ func NewError(pos posit.Pos, err error) error {
 	if err == nil {
 		return nil
 	}
-	if err == BreakError || err == ContinueError || err == ReturnError {
+	if err == BreakError || err == ContinueError || err == ReturnError || err == GeneratorClosedError {
 		return err
 	}
 	// if pe, ok := err.(*parser.Error); ok {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/shinanca/gonec/ast"
 	"github.com/shinanca/gonec/bincode/binstmt"
+	"github.com/shinanca/gonec/checker"
 	"github.com/shinanca/gonec/core"
 	"github.com/shinanca/gonec/names"
 	"github.com/shinanca/gonec/parser"
@@ -24,6 +25,23 @@ func Interrupt(env *core.Env) {
 
 // ParseSrc provides way to parse the code from source.
 func ParseSrc(src string) (prs ast.Stmts, bin binstmt.BinCode, err error) {
+	return parseSrc(src, nil)
+}
+
+// ParseSrcChecked разбирает исходный код, проверяет типы по аннотациям и сигнатурам встроенных функций
+// и, если ошибок типов нет, компилирует его. Операции, оба операнда которых по результатам проверки
+// целые числа, компилируются в специализированные инструкции OPINT.
+func ParseSrcChecked(src string) (prs ast.Stmts, bin binstmt.BinCode, typeErrs []error, err error) {
+	prs, bin, err = parseSrc(src, func(stmts ast.Stmts) bool {
+		typeErrs = checker.Check(stmts, true)
+		return len(typeErrs) == 0
+	})
+	return
+}
+
+// parseSrc разбирает и компилирует исходный код.
+// Функция check вызывается после свертки констант, если она возвращает false, код не компилируется.
+func parseSrc(src string, check func(ast.Stmts) bool) (prs ast.Stmts, bin binstmt.BinCode, err error) {
 	defer func() {
 		// если это не паника из кода языка
 		// if os.Getenv("GONEC_DEBUG") == "" {
@@ -52,6 +70,9 @@ func ParseSrc(src string) (prs ast.Stmts, bin binstmt.BinCode, err error) {
 	// оптимизируем дерево AST
 	// свертка констант и нативные значения
 	prs = parser.ConstFolding(prs)
+	if check != nil && !check(prs) {
+		return prs, bin, nil
+	}
 	// компиляция в бинарный код
 	lid := 0
 	bin = prs.BinaryCode(0, &lid)
@@ -152,6 +173,169 @@ func Run(stmts binstmt.BinCode, env *core.Env) (retval core.VMValue, reterr erro
 }
 
 // RunWorker исполняет кусок кода, начиная с инструкции idx
+// closureEnv возвращает окружение, которое удерживает создаваемая функция:
+// именованные функции наследуют от модуля или глобального окружения при вызове,
+// анонимные захватывают только используемые ими внешние переменные
+func closureEnv(fn *binstmt.BinFUNC, env *core.Env) *core.Env {
+	if !fn.IsAnon() {
+		return env
+	}
+	return env.Capture(fn.Upvalues, fn.Assigned, fn.IterVars)
+}
+
+// scriptFunc создает функцию на языке Гонец. Функция не встраивается, чтобы у всех функций
+// на языке Гонец был один адрес кода, по которому их отличает core.IsScriptFunc
+//
+//go:noinline
+func scriptFunc(expr *binstmt.BinFUNC, fstmts binstmt.BinStmts, flabels []int, fenv *core.Env) core.VMFunc {
+	return func(args core.VMSlice, rets *core.VMSlice) error {
+		if !expr.VarArg && !argsCountValid(expr, args) {
+			return binstmt.NewStringError(expr, "Неверное количество аргументов")
+		}
+		var newenv *core.Env
+		if expr.IsAnon() {
+			// наследуем от окружения замыкания с захваченными переменными
+			newenv = fenv.NewSubEnv()
+		} else {
+			// наследуем от модуля или глобального окружения
+			newenv = fenv.NewScopeEnv()
+		}
+
+		// параметры всегда локальные, даже если снаружи замыкания есть переменная с тем же именем
+		// переменное число аргументов передается как один параметр-слайс
+		if expr.VarArg {
+			newenv.DefineLocal(expr.Args[0], args)
+		} else {
+			for i, arg := range expr.Args {
+				if err := checkArgType(expr, newenv, args, i); err != nil {
+					newenv.Destroy()
+					return err
+				}
+				newenv.DefineLocal(arg, argValue(expr, args, i))
+			}
+		}
+		if expr.Generator {
+			// тело выполняется при переборе значений генератора
+			gen := core.NewVMGenerator(func(yield func(core.VMValue) bool) error {
+				newenv.SetYield(yield)
+				_, err := RunWorker(fstmts, flabels, expr.MaxReg+1, newenv, flabels[expr.LabelStart])
+				if err == binstmt.ReturnError || err == binstmt.GeneratorClosedError {
+					err = nil
+				}
+				newenv.Destroy()
+				return err
+			})
+			// брошенный генератор закрывается сборщиком мусора или при уничтожении глобального окружения,
+			// поэтому тело не должно ссылаться на сам генератор
+			gen.BindEnv(newenv)
+			rets.Append(gen)
+			return nil
+		}
+
+		// вызов функции возвращает одиночное значение (в т.ч. VMNil) или VMSlice
+
+		rr, err := RunWorker(fstmts, flabels, expr.MaxReg+1, newenv, flabels[expr.LabelStart])
+
+		if err == binstmt.ReturnError {
+			err = nil
+		}
+		if err == nil && expr.RetType != 0 {
+			if terr := newenv.CheckValueType(rr, expr.RetType); terr != nil {
+				err = binstmt.NewErrorf(expr, "Функция %s: %s", names.UniqueNames.Get(expr.Name), terr)
+			}
+		}
+		// возврат массива возвращается сразу, иначе добавляется
+		if vsl, ok := rr.(core.VMSlice); ok {
+			*rets = vsl
+		} else {
+			rets.Append(rr)
+		}
+		newenv.Destroy()
+		return err
+	}
+}
+
+func init() {
+	core.RegisterScriptFunc(scriptFunc(nil, nil, nil, nil))
+}
+
+// argsCountValid проверяет количество аргументов: лишних быть не может,
+// а недостающими могут быть только параметры со значениями по умолчанию
+func argsCountValid(fn *binstmt.BinFUNC, args core.VMSlice) bool {
+	if len(args) > len(fn.Args) {
+		return false
+	}
+	for i := len(args); i < len(fn.Args); i++ {
+		if fn.Optional == nil || !fn.Optional[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// argValue возвращает значение i-го параметра функции.
+// Пропущенный при вызове параметр, например Ф(1, , 3), передается как nil
+// и получает значение по умолчанию или Неопределено.
+func argValue(fn *binstmt.BinFUNC, args core.VMSlice, i int) core.VMValue {
+	var v core.VMValue
+	if i < len(args) {
+		v = args[i]
+	}
+	if v == nil {
+		if fn.Defaults == nil {
+			return core.VMNil
+		}
+		v = fn.Defaults[i]
+	} else if fn.ByVal == nil || !fn.ByVal[i] {
+		return v
+	}
+	// значения по умолчанию и параметры Знач не должны разделять данные с другими вызовами
+	switch vv := v.(type) {
+	case core.VMSlice:
+		return vv.CopyRecursive()
+	case core.VMStringMap:
+		return vv.CopyRecursive()
+	}
+	return v
+}
+
+// checkArgType проверяет аннотацию типа параметра i.
+// Значения по умолчанию не проверяются, так же как пропущенные при вызове параметры.
+func checkArgType(fn *binstmt.BinFUNC, env *core.Env, args core.VMSlice, i int) error {
+	if fn.ArgTypes == nil || fn.ArgTypes[i] == 0 || i >= len(args) || args[i] == nil {
+		return nil
+	}
+	if err := env.CheckValueType(args[i], fn.ArgTypes[i]); err != nil {
+		return binstmt.NewErrorf(fn, "Параметр %s: %s", names.UniqueNames.Get(fn.Args[i]), err)
+	}
+	return nil
+}
+
+// evalBinOp вычисляет бинарную операцию над значениями регистров
+// isType проверяет тип значения в ветке Когда так же, как аннотацию типа
+func isType(env *core.Env, v core.VMValue, typ int) (bool, error) {
+	if !env.IsTypeName(typ) {
+		return false, fmt.Errorf("Тип неопределен '%s'", names.UniqueNames.Get(typ))
+	}
+	return env.CheckValueType(v, typ) == nil, nil
+}
+
+func evalBinOp(stmt binstmt.BinStmt, op core.VMOperation, v1, v2 core.VMValue) (core.VMValue, error) {
+	vv1, ok := v1.(core.VMOperationer)
+	if !ok {
+		return nil, binstmt.NewStringError(stmt, "Значение нельзя использовать в выражении")
+	}
+	vv2, ok := v2.(core.VMOperationer)
+	if !ok {
+		return nil, binstmt.NewStringError(stmt, "Значение нельзя использовать в выражении")
+	}
+	rv, err := vv1.EvalBinOp(op, vv2)
+	if err != nil {
+		return nil, binstmt.NewError(stmt, err)
+	}
+	return rv, nil
+}
+
 func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.Env, idx int) (retval core.VMValue, reterr error) {
 	defer func() {
 		// если это не паника из кода языка
@@ -180,6 +364,15 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 		ForBreaks:    make([]int, 0, 8),
 		ForContinues: make([]int, 0, 8),
 	}
+	defer func() {
+		// выход по Возврат или ошибке из незавершенных циклов Для каждого
+		if err := regs.CloseIters(0); err != nil && (reterr == nil || reterr == binstmt.ReturnError) {
+			if idx >= len(stmts) {
+				idx = len(stmts) - 1
+			}
+			retval, reterr = nil, binstmt.NewError(stmts[idx], err)
+		}
+	}()
 
 	var catcherr error
 
@@ -248,44 +441,33 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			env.Define(s.Id, registers[s.Reg])
 
 		case *binstmt.BinOPER:
-			v1 := registers[s.RegL]
-			v2 := registers[s.RegR]
-			if vv1, ok := v1.(core.VMOperationer); ok {
-				if vv2, ok := v2.(core.VMOperationer); ok {
-					if rv, err := vv1.EvalBinOp(s.Op, vv2); err == nil {
-						registers[s.RegL] = rv
-					} else {
-						catcherr = binstmt.NewError(stmt, err)
-						goto catching
-					}
-				} else {
-					catcherr = binstmt.NewStringError(stmt, "Значение нельзя использовать в выражении")
-					goto catching
-				}
-			} else {
-				catcherr = binstmt.NewStringError(stmt, "Значение нельзя использовать в выражении")
+			rv, err := evalBinOp(stmt, s.Op, registers[s.RegL], registers[s.RegR])
+			if err != nil {
+				catcherr = err
 				goto catching
 			}
+			registers[s.RegL] = rv
 
-		case *binstmt.BinEQUAL:
-			v1 := registers[s.Reg1]
-			v2 := registers[s.Reg2]
-			if vv1, ok := v1.(core.VMOperationer); ok {
-				if vv2, ok := v2.(core.VMOperationer); ok {
-					if rv, err := vv1.EvalBinOp(core.EQL, vv2); err == nil {
-						registers[s.Reg] = rv
-					} else {
-						catcherr = binstmt.NewError(stmt, err)
+		case *binstmt.BinOPINT:
+			v1, v2 := registers[s.RegL], registers[s.RegR]
+			if x, ok := v1.(core.VMInt); ok {
+				if y, ok := v2.(core.VMInt); ok {
+					if rv, ok := core.IntBinOp(s.Op, x, y); ok {
+						registers[s.RegL] = rv
 						break
 					}
-				} else {
-					catcherr = binstmt.NewStringError(stmt, "Значение нельзя сравнивать")
-					break
 				}
-			} else {
-				catcherr = binstmt.NewStringError(stmt, "Значение нельзя сравнивать")
-				break
 			}
+			rv, err := evalBinOp(stmt, s.Op, v1, v2)
+			if err != nil {
+				catcherr = err
+				goto catching
+			}
+			registers[s.RegL] = rv
+
+		case *binstmt.BinEQUAL:
+			// значения разных типов в ветках Когда не совпадают, а не вызывают ошибку
+			registers[s.Reg] = core.VMBool(core.EqualVMValues(registers[s.Reg1], registers[s.Reg2]))
 
 		case *binstmt.BinCASTNUM:
 			// ошибки обрабатываем в попытке
@@ -330,6 +512,10 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			mv := registers[s.RegVal]
 			switch mm := m.(type) {
 			case core.VMMetaObject:
+				if !mm.VMIsField(s.Id) {
+					catcherr = binstmt.NewErrorf(stmt, "Нет поля с именем %s", names.UniqueNames.Get(s.Id))
+					goto catching
+				}
 				mm.VMSetField(s.Id, mv.(core.VMInterfacer))
 			case core.VMStringMap:
 				mm[names.UniqueNames.Get(s.Id)] = mv
@@ -357,6 +543,19 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				argsl = registers[s.RegArgs : s.RegArgs+s.NumArgs]
 			}
 			if fnc, ok := fgnc.(core.VMFunc); ok {
+				// пропущенные параметры, например Ф(1, , 3), передаются как nil только функциям
+				// на языке Гонец, функции стандартной библиотеки получают Неопределено
+				for i, a := range argsl {
+					if a == nil && !core.IsScriptFunc(fnc) {
+						for j := i; j < len(argsl); j++ {
+							if argsl[j] == nil {
+								argsl[j] = core.VMNil
+							}
+						}
+						break
+					}
+				}
+
 				// если ее надо вызвать в горутине - вызываем
 				if s.Go {
 					// env.SetGoRunned(true)
@@ -407,49 +606,14 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 
 		case *binstmt.BinFUNC:
 
-			f := func(expr *binstmt.BinFUNC, fstmts binstmt.BinStmts, flabels []int, fenv *core.Env) core.VMFunc {
-				return func(args core.VMSlice, rets *core.VMSlice) error {
-					if !expr.VarArg {
-						if len(args) != len(expr.Args) {
-							return binstmt.NewStringError(expr, "Неверное количество аргументов")
-						}
-					}
-					var newenv *core.Env
-					if expr.Name == 0 {
-						// наследуем от окружения текущей функции
-						newenv = fenv.NewSubEnv()
-					} else {
-						// наследуем от модуля или глобального окружения
-						newenv = fenv.NewEnv()
-					}
-
-					// переменное число аргументов передается как один параметр-слайс
-					if expr.VarArg {
-						newenv.Define(expr.Args[0], args)
-					} else {
-						for i, arg := range expr.Args {
-							newenv.Define(arg, args[i])
-						}
-					}
-					// вызов функции возвращает одиночное значение (в т.ч. VMNil) или VMSlice
+			f := scriptFunc(s, stmts, labels, closureEnv(s, env))
 
-					rr, err := RunWorker(fstmts, flabels, expr.MaxReg+1, newenv, flabels[expr.LabelStart])
-
-					if err == binstmt.ReturnError {
-						err = nil
-					}
-					// возврат массива возвращается сразу, иначе добавляется
-					if vsl, ok := rr.(core.VMSlice); ok {
-						*rets = vsl
-					} else {
-						rets.Append(rr)
-					}
-					newenv.Destroy()
-					return err
-				}
-			}(s, stmts, labels, env)
-
-			env.Define(s.Name, f)
+			if !s.Method {
+				env.Define(s.Name, f)
+			}
+			if s.Export {
+				env.Export(s.Name)
+			}
 			registers[s.Reg] = f
 			idx = regs.Labels[s.LabelEnd]
 
@@ -471,8 +635,12 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			switch vv := v.(type) {
 			case *core.Env:
 				// это идентификатор из модуля или окружения
-				m, err := vv.Get(s.Name)
-				if m == nil || err != nil {
+				m, err := vv.GetExported(s.Name)
+				if err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+				if m == nil {
 					catcherr = binstmt.NewStringError(stmt, "Имя не найдено")
 					goto catching
 				}
@@ -493,6 +661,13 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				if vv.VMIsField(s.Name) {
 					registers[s.Reg] = vv.VMGetField(s.Name)
 				} else {
+					if o, ok := vv.(*core.VMObject); ok {
+						// объект, восстановленный из бинарного представления, получает методы своего типа
+						if err := o.Bind(env); err != nil {
+							catcherr = binstmt.NewError(stmt, err)
+							goto catching
+						}
+					}
 					if ff, ok := vv.VMGetMethod(s.Name); ok {
 						registers[s.Reg] = ff
 					} else {
@@ -554,6 +729,13 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 					catcherr = binstmt.NewStringError(stmt, "Ключ должен быть строкой")
 					goto catching
 				}
+			case *core.VMOrderedMap:
+				rv, _, err := vv.Get(i)
+				if err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+				registers[s.Reg] = rv
 			case core.VMIndexer:
 				if iv, ok := i.(core.VMInt); ok {
 					ii := int(iv)
@@ -565,7 +747,7 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 						catcherr = binstmt.NewStringError(stmt, "Индекс за пределами границ")
 						goto catching
 					}
-					registers[s.Reg] = vv.IndexVal(iv)
+					registers[s.Reg] = vv.IndexVal(core.VMInt(ii))
 				} else {
 					catcherr = binstmt.NewStringError(stmt, "Индекс должен быть целым числом")
 					goto catching
@@ -602,6 +784,11 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				if s, ok := i.(core.VMString); ok {
 					vv[string(s)] = rv
 				}
+			case *core.VMOrderedMap:
+				if err := vv.Set(i, rv); err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
 			default:
 				catcherr = binstmt.NewStringError(stmt, "Неверная операция")
 				goto catching
@@ -850,7 +1037,17 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				break
 			}
 			var v reflect.Value
-			if rt.Kind() == reflect.Map {
+			if rt == core.ReflectVMObject {
+				// пользовательский тип, объявленный в коде
+				cls, err := env.Class(int(eType))
+				if err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					break
+				}
+				obj := &core.VMObject{}
+				obj.SetClass(cls)
+				v = reflect.ValueOf(obj)
+			} else if rt.Kind() == reflect.Map {
 				v = reflect.MakeMap(reflect.MapOf(rt.Key(), rt.Elem())).Convert(rt)
 			} else if rt.Kind() == reflect.Struct {
 				// структуру создаем всегда ссылочной
@@ -889,6 +1086,10 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 					catcherr = binstmt.NewStringError(stmt, "Переданы аргументы для создания объекта типа, не имеющего конструктора")
 					break
 				}
+				// объекты, владеющие внешними ресурсами, регистрируются в окружении
+				if eb, ok := registers[s.Reg].(core.VMEnvBinder); ok {
+					eb.BindEnv(env)
+				}
 			} else {
 				catcherr = binstmt.NewStringError(stmt, "Неизвестный тип")
 				break
@@ -949,6 +1150,70 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			_, ok := registers[s.Reg].(core.VMSlice)
 			registers[s.RegBool] = core.VMBool(ok)
 
+		case *binstmt.BinISTYPE:
+			ok, err := isType(env, registers[s.Reg], s.Type)
+			if err != nil {
+				catcherr = binstmt.NewError(stmt, err)
+				break
+			}
+			registers[s.RegBool] = core.VMBool(ok)
+
+		case *binstmt.BinMATCHNAME:
+			v := registers[s.Reg]
+			x, errGet := env.Get(s.Name)
+			if i, ok := x.(*core.VMInterface); ok && errGet == nil {
+				registers[s.RegBool] = core.VMBool(i.ImplementedBy(v))
+			} else if ok, err := isType(env, v, s.Name); err == nil {
+				registers[s.RegBool] = core.VMBool(ok)
+			} else if errGet == nil {
+				// существующая переменная сравнивается по значению
+				registers[s.RegBool] = core.VMBool(core.EqualVMValues(v, x))
+			} else {
+				// имя связывается со значением в окружении ветки, переменные снаружи не меняются
+				env.DefineLocal(s.Name, v)
+				registers[s.RegBool] = core.VMBool(true)
+			}
+
+		case *binstmt.BinBIND:
+			env.DefineLocal(s.Name, registers[s.Reg])
+
+		case *binstmt.BinPUSHSCOPE:
+			env = regs.PushScope(env)
+
+		case *binstmt.BinPOPSCOPE:
+			env = regs.PopScopes(len(regs.Scopes)-1, env)
+
+		case *binstmt.BinINRANGE:
+			v := registers[s.Reg]
+			registers[s.RegBool] = core.VMBool(core.BoolOperVMValues(v, registers[s.RegFrom], core.GEQ) &&
+				core.BoolOperVMValues(v, registers[s.RegTo], core.LEQ))
+
+		case *binstmt.BinMATCHSLICE:
+			v, ok := registers[s.Reg].(core.VMSlice)
+			registers[s.RegBool] = core.VMBool(ok && len(v) == s.Len)
+
+		case *binstmt.BinMATCHKEY:
+			var val core.VMValue
+			ok := false
+			switch vv := registers[s.Reg].(type) {
+			case core.VMStringMap:
+				val, ok = vv[s.Key]
+				ok = ok || s.Key == ""
+			case *core.VMOrderedMap:
+				val, ok, _ = vv.Get(core.VMString(s.Key))
+				ok = ok || s.Key == ""
+			case *core.VMObject:
+				if id := names.UniqueNames.Set(s.Key); s.Key != "" && vv.VMIsField(id) {
+					val, ok = vv.VMGetField(id), true
+				}
+			}
+			registers[s.RegVal] = val
+			registers[s.RegBool] = core.VMBool(ok)
+
+		case *binstmt.BinSWITCH:
+			idx = regs.Labels[s.JumpTo(registers[s.Reg])]
+			continue
+
 		case *binstmt.BinINC:
 			v := registers[s.Reg]
 			var x core.VMValue
@@ -994,6 +1259,16 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			case core.VMSlicer:
 				registers[s.RegIter] = core.VMInt(-1)
 				registers[s.Reg] = vv.Slice()
+			case core.VMIterable:
+				it, err := vv.Iterator()
+				if err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+				registers[s.RegIter] = it
+			case core.VMIterator:
+				// генератор или итератор, полученный из метода объекта
+				registers[s.RegIter] = vv
 			case core.VMChan:
 				registers[s.RegIter] = nil
 			default:
@@ -1003,6 +1278,9 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 
 			regs.PushBreak(s.BreakLabel)
 			regs.PushContinue(s.ContinueLabel)
+			if c, ok := registers[s.RegIter].(core.VMIteratorCloser); ok {
+				regs.PushIter(s.ContinueLabel, c)
+			}
 
 		case *binstmt.BinNEXT:
 			val := registers[s.Reg]
@@ -1018,6 +1296,17 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 					idx = regs.Labels[s.JumpTo]
 					continue
 				}
+			case core.VMIterable, core.VMIterator:
+				iv, ok, err := registers[s.RegIter].(core.VMIterator).Next()
+				if err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+				if !ok {
+					idx = regs.Labels[s.JumpTo]
+					continue
+				}
+				registers[s.RegVal] = iv
 			case core.VMChan:
 				iv, ok := vv.Recv()
 				if !ok {
@@ -1036,6 +1325,24 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				regs.PopContinue()
 				regs.PopBreak()
 			}
+			// цикл завершен или прерван, освобождаем ресурс итератора
+			if it := regs.PopIter(s.ContinueLabel); it != nil {
+				if err := it.Close(); err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+			}
+
+		case *binstmt.BinYIELD:
+			ok, err := env.Yield(registers[s.Reg])
+			if err != nil {
+				catcherr = binstmt.NewError(stmt, err)
+				goto catching
+			}
+			if !ok {
+				// потребитель закрыл генератор, завершаем тело функции
+				return nil, binstmt.GeneratorClosedError
+			}
 
 		case *binstmt.BinFORNUM:
 			if _, ok := registers[s.RegFrom].(core.VMInt); ok {
@@ -1095,6 +1402,47 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 				break
 			}
 
+		case *binstmt.BinCLASS:
+			cls := core.NewVMClass(names.UniqueNames.Get(s.Name))
+			r := s.Reg + 1
+			for _, f := range s.Fields {
+				cls.Fields = append(cls.Fields, f)
+				cls.Defaults = append(cls.Defaults, registers[r])
+				r++
+			}
+			for i, m := range s.Methods {
+				cls.Methods[m] = registers[r].(core.VMFunc)
+				cls.NumArgs[m] = s.MethodArgs[i]
+				r++
+			}
+			for _, id := range s.Implements {
+				v, err := env.Get(id)
+				if err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+				iface, ok := v.(*core.VMInterface)
+				if !ok {
+					catcherr = binstmt.NewErrorf(stmt, "%s не является интерфейсом", names.UniqueNames.Get(id))
+					goto catching
+				}
+				if err := cls.Implement(iface); err != nil {
+					catcherr = binstmt.NewError(stmt, err)
+					goto catching
+				}
+			}
+			if err := env.DefineClass(cls); err != nil {
+				catcherr = binstmt.NewError(stmt, err)
+				break
+			}
+
+		case *binstmt.BinINTERFACE:
+			env.Define(s.Name, &core.VMInterface{
+				Name:    names.UniqueNames.Get(s.Name),
+				Methods: s.Methods,
+				NumArgs: s.NumArgs,
+			})
+
 		case *binstmt.BinERROR:
 			// необрабатываемая в попытке ошибка
 			return retval, binstmt.NewStringError(s, s.Error)
@@ -1102,6 +1450,8 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 		case *binstmt.BinBREAK:
 			label := regs.PopBreak()
 			if label != -1 {
+				// выходим и из веток Когда внутри цикла
+				env = regs.PopScopes(regs.TopForScopes(), env)
 				regs.PopContinue()
 				idx = regs.Labels[label]
 				continue
@@ -1109,9 +1459,10 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			return nil, binstmt.BreakError
 
 		case *binstmt.BinCONTINUE:
-			label := regs.PopContinue()
+			// цикл продолжается, поэтому со стека циклов он не снимается
+			label := regs.TopContinue()
 			if label != -1 {
-				regs.PopBreak()
+				env = regs.PopScopes(regs.TopForScopes(), env)
 				idx = regs.Labels[label]
 				continue
 			}
@@ -1162,6 +1513,8 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 			if regs.TopTryLabel() == -1 {
 				return nil, nerr
 			} else {
+				// ошибка могла возникнуть в ветке Когда внутри Попытка
+				env = regs.PopScopes(regs.TopTryScopes(), env)
 				env.DefineS("описаниеошибки", func(s string) core.VMFunc {
 					return func(args core.VMSlice, rets *core.VMSlice) error {
 						if len(args) != 0 {
@@ -1172,6 +1525,8 @@ func RunWorker(stmts binstmt.BinStmts, labels []int, numofregs int, env *core.En
 					}
 				}(nerr.Error()))
 
+				// циклы внутри Попытка прерваны ошибкой, их итераторы закрываем
+				regs.CloseIters(regs.TopTryIters())
 				r, idxl := regs.PopTry()
 				registers[r] = core.VMString(nerr.Error())
 				idx = regs.Labels[idxl] // переходим в catch блок, функция с описанием ошибки определена
@@ -0,0 +1,833 @@
+// Package checker - статическая проверка типов кода на языке Гонец (команда gonec -check).
+//
+// Типы выводятся из литералов, аннотаций параметров, возвращаемых значений и переменных,
+// объявлений пользовательских типов и сигнатур встроенных функций, зарегистрированных в core.
+// Ошибкой считается только то, что гарантированно завершится ошибкой при исполнении:
+// если тип значения неизвестен, проверка его пропускает.
+package checker
+
+import (
+	"github.com/shinanca/gonec/ast"
+	"github.com/shinanca/gonec/bincode/binstmt"
+	"github.com/shinanca/gonec/core"
+	"github.com/shinanca/gonec/names"
+	"github.com/shinanca/gonec/pos"
+)
+
+// variable - переменная в области видимости, typ пустой, если тип неизвестен
+type variable struct {
+	typ       string
+	annotated bool // тип объявлен аннотацией и не может меняться
+}
+
+type scope struct {
+	parent  *scope
+	vars    map[int]*variable
+	pattern bool // ветка Когда: в ней определяются только имена, связанные шаблоном
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, vars: make(map[int]*variable)}
+}
+
+// owner возвращает область, в которой определяется имя при присваивании:
+// в ветке Когда определяются только имена, связанные шаблоном
+func (s *scope) owner(id int) *scope {
+	for s.pattern && s.vars[id] == nil {
+		s = s.parent
+	}
+	return s
+}
+
+func (s *scope) lookup(id int) *variable {
+	for ss := s; ss != nil; ss = ss.parent {
+		if v, ok := ss.vars[id]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// function - сигнатура функции или метода, объявленных в коде
+type function struct {
+	name     int
+	args     []int
+	types    []string
+	required int
+	vararg   bool
+	ret      string
+}
+
+// class - поля и методы пользовательского типа
+type class struct {
+	name       string
+	fields     map[int]string
+	methods    map[int]*function
+	implements []int
+}
+
+// Checker проверяет типы в дереве AST, полученном после свертки констант
+type Checker struct {
+	// Specialize отмечает операции над целыми числами для компиляции в инструкции OPINT
+	Specialize bool
+
+	env     *core.Env
+	errs    []error
+	funcs   map[int]*function
+	classes map[int]*class
+	ifaces  map[int]*ast.InterfaceStmt
+	fn      *function // функция, тело которой проверяется
+}
+
+func New() *Checker {
+	env := core.NewEnv()
+	core.LoadAllBuiltins(env)
+	return &Checker{
+		env:     env,
+		funcs:   make(map[int]*function),
+		classes: make(map[int]*class),
+		ifaces:  make(map[int]*ast.InterfaceStmt),
+	}
+}
+
+// Check проверяет код и возвращает найденные ошибки
+func Check(stmts ast.Stmts, specialize bool) []error {
+	c := New()
+	c.Specialize = specialize
+	return c.Check(stmts)
+}
+
+func (c *Checker) Check(stmts ast.Stmts) []error {
+	c.declare(stmts)
+	c.stmts(stmts, newScope(nil))
+	return c.errs
+}
+
+func (c *Checker) errorf(p pos.Pos, format string, args ...interface{}) {
+	c.errs = append(c.errs, binstmt.NewErrorf(p, format, args...))
+}
+
+// typeName возвращает имя типа из аннотации и проверяет, что такой тип существует
+func (c *Checker) typeName(p pos.Pos, id int) string {
+	if id == 0 {
+		return ""
+	}
+	s := names.UniqueNames.Get(id)
+	if core.SameTypeName(s, core.TypeAnyName) || core.SameTypeName(s, core.TypeNumberName) ||
+		core.SameTypeName(s, core.TypeIntName) || c.classes[id] != nil || c.ifaces[id] != nil {
+		return s
+	}
+	if _, err := c.env.Type(id); err != nil {
+		c.errorf(p, "Тип неопределен '%s'", s)
+		return ""
+	}
+	return s
+}
+
+// assignable проверяет совместимость типов с учетом интерфейсов, объявленных в коде
+func (c *Checker) assignable(want, got string) bool {
+	if core.TypeAssignable(want, got) {
+		return true
+	}
+	id := names.UniqueNames.Set(want)
+	if c.ifaces[id] == nil {
+		return false
+	}
+	cl := c.classes[names.UniqueNames.Set(got)]
+	if cl == nil {
+		// реализацию интерфейса системными типами проверяет только исполнение
+		return !isPrimitive(got)
+	}
+	for _, impl := range cl.implements {
+		if impl == id {
+			return true
+		}
+	}
+	return false
+}
+
+// declare собирает сигнатуры функций, пользовательские типы и интерфейсы до проверки,
+// чтобы вызовы могли предшествовать объявлениям
+func (c *Checker) declare(stmts ast.Stmts) {
+	var all ast.Stmts
+	for _, st := range stmts {
+		if m, ok := st.(*ast.ModuleStmt); ok {
+			all = append(all, m.Stmts...)
+		} else {
+			all = append(all, st)
+		}
+	}
+	for _, st := range all {
+		switch s := st.(type) {
+		case *ast.InterfaceStmt:
+			c.ifaces[s.Name] = s
+		case *ast.ClassStmt:
+			c.classes[s.Name] = &class{
+				name:       names.UniqueNames.Get(s.Name),
+				fields:     make(map[int]string),
+				methods:    make(map[int]*function),
+				implements: s.Implements,
+			}
+		case *ast.ExprStmt:
+			if fn, ok := s.Expr.(*ast.FuncExpr); ok && !fn.Method {
+				c.funcs[fn.Name] = nil
+			}
+		}
+	}
+	// типы в аннотациях могут ссылаться на классы и интерфейсы, объявленные ниже
+	for _, st := range all {
+		switch s := st.(type) {
+		case *ast.ClassStmt:
+			cl := c.classes[s.Name]
+			for i, f := range s.Fields {
+				cl.fields[f] = c.typeName(s, s.FieldTypes[i])
+			}
+			for _, m := range s.Methods {
+				sig := c.signature(m)
+				// первый параметр ЭтотОбъект передается неявно
+				sig.args, sig.types = sig.args[1:], sig.types[1:]
+				if sig.required > 0 {
+					sig.required--
+				}
+				cl.methods[m.Name] = sig
+			}
+		case *ast.ExprStmt:
+			if fn, ok := s.Expr.(*ast.FuncExpr); ok && !fn.Method {
+				c.funcs[fn.Name] = c.signature(fn)
+			}
+		}
+	}
+}
+
+func (c *Checker) signature(fn *ast.FuncExpr) *function {
+	sig := &function{
+		name:   fn.Name,
+		args:   fn.Args,
+		types:  make([]string, len(fn.Args)),
+		vararg: fn.VarArg,
+		ret:    c.typeName(fn, fn.RetType),
+	}
+	for i := range fn.Args {
+		if fn.ArgTypes != nil {
+			sig.types[i] = c.typeName(fn, fn.ArgTypes[i])
+		}
+		if !fn.VarArg && (fn.Defaults == nil || fn.Defaults[i] == nil) {
+			sig.required = i + 1
+		}
+	}
+	return sig
+}
+
+func (c *Checker) stmts(stmts ast.Stmts, sc *scope) {
+	for _, st := range stmts {
+		c.stmt(st, sc)
+	}
+}
+
+func (c *Checker) stmt(st ast.Stmt, sc *scope) {
+	switch s := st.(type) {
+	case *ast.ExprStmt:
+		if be, ok := s.Expr.(*ast.BinOpExpr); ok && core.OperMap[be.Operator] == core.EQL {
+			// присваивание в контексте блока кода
+			c.lets(be.Lhss, be.Rhss, sc)
+			return
+		}
+		c.expr(s.Expr, sc)
+	case *ast.LetsStmt:
+		c.lets(s.Lhss, s.Rhss, sc)
+	case *ast.VarStmt:
+		for i, name := range s.Names {
+			t := ""
+			if s.Types != nil {
+				t = c.typeName(s, s.Types[i])
+			}
+			var vt string
+			if len(s.Exprs) == 1 {
+				vt = c.expr(s.Exprs[0], sc)
+			} else if i < len(s.Exprs) {
+				vt = c.expr(s.Exprs[i], sc)
+			}
+			if t != "" {
+				if vt != core.TypeNilName && !c.assignable(t, vt) {
+					c.errorf(s, "Переменной %s типа %s присваивается значение типа %s", names.UniqueNames.Get(name), t, vt)
+				}
+				sc.owner(name).vars[name] = &variable{typ: t, annotated: true}
+			} else {
+				sc.owner(name).vars[name] = &variable{typ: vt}
+			}
+		}
+	case *ast.IfStmt:
+		c.expr(s.If, sc)
+		c.stmts(s.Then, sc)
+		c.stmts(s.ElseIf, sc)
+		c.stmts(s.Else, sc)
+	case *ast.TryStmt:
+		c.stmts(s.Try, sc)
+		c.stmts(s.Catch, sc)
+	case *ast.ForStmt:
+		c.expr(s.Value, sc)
+		c.assign(s.Var, "", s, sc)
+		c.stmts(s.Stmts, sc)
+	case *ast.NumForStmt:
+		t1 := c.expr(s.Expr1, sc)
+		t2 := c.expr(s.Expr2, sc)
+		if !c.assignable(core.TypeNumberName, t1) || !c.assignable(core.TypeNumberName, t2) {
+			c.errorf(s, "Границы цикла должны быть числами")
+		}
+		c.assign(s.Name, t1, s, sc)
+		c.stmts(s.Stmts, sc)
+	case *ast.LoopStmt:
+		if s.Expr != nil {
+			c.expr(s.Expr, sc)
+		}
+		c.stmts(s.Stmts, sc)
+	case *ast.ReturnStmt:
+		var t string
+		for _, e := range s.Exprs {
+			t = c.expr(e, sc)
+		}
+		if c.fn == nil || c.fn.ret == "" || len(s.Exprs) > 1 {
+			return
+		}
+		if len(s.Exprs) == 0 {
+			t = core.TypeNilName
+		}
+		if !c.assignable(c.fn.ret, t) {
+			c.errorf(s, "Функция %s должна возвращать значение типа %s, а не %s", names.UniqueNames.Get(c.fn.name), c.fn.ret, t)
+		}
+	case *ast.ThrowStmt:
+		c.expr(s.Expr, sc)
+	case *ast.YieldStmt:
+		c.expr(s.Expr, sc)
+	case *ast.ModuleStmt:
+		c.stmts(s.Stmts, sc)
+	case *ast.ClassStmt:
+		cl := c.classes[s.Name]
+		for i, f := range s.Fields {
+			t := c.expr(s.Defaults[i], sc)
+			if ft := cl.fields[f]; ft != "" && t != core.TypeNilName && !c.assignable(ft, t) {
+				c.errorf(s, "Полю %s типа %s присваивается значение типа %s", names.UniqueNames.Get(f), ft, t)
+			}
+		}
+		for _, m := range s.Methods {
+			c.function(m, cl.methods[m.Name], sc, cl.name)
+		}
+		for _, i := range s.Implements {
+			c.checkImplements(s, cl, i)
+		}
+	case *ast.InterfaceStmt:
+	case *ast.SwitchStmt:
+		c.expr(s.Expr, sc)
+		c.stmts(s.Cases, sc)
+	case *ast.SelectStmt:
+		c.stmts(s.Cases, sc)
+	case *ast.CaseStmt:
+		bsc := newScope(sc)
+		bsc.pattern = true
+		for _, p := range s.Patterns {
+			c.pattern(p, bsc)
+		}
+		if s.Guard != nil {
+			c.expr(s.Guard, bsc)
+		}
+		c.stmts(s.Stmts, bsc)
+	case *ast.DefaultStmt:
+		c.stmts(s.Stmts, sc)
+	}
+}
+
+// pattern проверяет шаблон ветки Когда и учитывает имена, которые он связывает со значением
+func (c *Checker) pattern(e ast.Expr, sc *scope) {
+	switch x := e.(type) {
+	case *ast.TypePattern:
+		c.typeName(x, x.Type)
+	case *ast.RangePattern:
+		c.expr(x.From, sc)
+		c.expr(x.To, sc)
+	case *ast.NamePattern:
+		if c.classes[x.Name] != nil || c.ifaces[x.Name] != nil || c.env.IsTypeName(x.Name) || sc.lookup(x.Name) != nil {
+			return
+		}
+		sc.vars[x.Name] = &variable{}
+	case *ast.BindPattern:
+		if x.Name != 0 {
+			sc.vars[x.Name] = &variable{}
+		}
+	case *ast.SlicePattern:
+		for _, item := range x.Items {
+			c.pattern(item, sc)
+		}
+	case *ast.MapPattern:
+		for _, item := range x.Items {
+			c.pattern(item, sc)
+		}
+	default:
+		c.expr(e, sc)
+	}
+}
+
+// checkImplements проверяет наличие методов интерфейса, объявленного в коде, и количество их параметров
+func (c *Checker) checkImplements(s *ast.ClassStmt, cl *class, iface int) {
+	is := c.ifaces[iface]
+	if is == nil {
+		return
+	}
+	for _, e := range is.Methods {
+		m := e.(*ast.FuncExpr)
+		sig, ok := cl.methods[m.Name]
+		if !ok || (!m.VarArg && !sig.vararg && len(sig.args) != len(m.Args)) {
+			c.errorf(s, "Тип %s не реализует метод %s интерфейса %s", cl.name, names.UniqueNames.Get(m.Name), names.UniqueNames.Get(iface))
+		}
+	}
+}
+
+// function проверяет тело функции в собственной области видимости
+func (c *Checker) function(fn *ast.FuncExpr, sig *function, sc *scope, this string) {
+	if sig == nil {
+		sig = c.signature(fn)
+	}
+	fsc := newScope(sc)
+	args := fn.Args
+	if fn.Method {
+		fsc.vars[args[0]] = &variable{typ: this, annotated: true}
+		args = args[1:]
+	}
+	for i, a := range args {
+		v := &variable{typ: sig.types[i], annotated: sig.types[i] != ""}
+		if fn.VarArg {
+			v.typ = "Массив"
+		}
+		fsc.vars[a] = v
+	}
+	prev := c.fn
+	c.fn = sig
+	c.stmts(fn.Stmts, fsc)
+	c.fn = prev
+}
+
+func (c *Checker) lets(lhss, rhss []ast.Expr, sc *scope) {
+	if len(lhss) != len(rhss) {
+		for _, e := range rhss {
+			c.expr(e, sc)
+		}
+		for _, e := range lhss {
+			c.assignTo(e, "", sc)
+		}
+		return
+	}
+	ts := make([]string, len(rhss))
+	for i, e := range rhss {
+		ts[i] = c.expr(e, sc)
+	}
+	for i, e := range lhss {
+		c.assignTo(e, ts[i], sc)
+	}
+}
+
+// assignTo проверяет присваивание значения типа t выражению слева
+func (c *Checker) assignTo(e ast.Expr, t string, sc *scope) {
+	switch x := e.(type) {
+	case *ast.IdentExpr:
+		c.assign(x.Id, t, x, sc)
+	case *ast.MemberExpr:
+		ot := c.expr(x.Expr, sc)
+		if cl := c.classes[names.UniqueNames.Set(ot)]; cl != nil && ot != "" {
+			ft, ok := cl.fields[x.Name]
+			if !ok {
+				c.errorf(x, "Нет поля с именем %s в типе %s", names.UniqueNames.Get(x.Name), cl.name)
+			} else if t != core.TypeNilName && !c.assignable(ft, t) {
+				c.errorf(x, "Полю %s типа %s присваивается значение типа %s", names.UniqueNames.Get(x.Name), ft, t)
+			}
+		}
+	default:
+		c.expr(e, sc)
+	}
+}
+
+// assign учитывает присваивание переменной, которое, как и при исполнении,
+// всегда определяет ее в текущей функции, а не во внешнем окружении
+func (c *Checker) assign(id int, t string, p pos.Pos, sc *scope) {
+	sc = sc.owner(id)
+	v := sc.vars[id]
+	if v == nil {
+		sc.vars[id] = &variable{typ: t}
+		return
+	}
+	if v.annotated {
+		if t != core.TypeNilName && !c.assignable(v.typ, t) {
+			c.errorf(p, "Переменной %s типа %s присваивается значение типа %s", names.UniqueNames.Get(id), v.typ, t)
+		}
+		return
+	}
+	if !core.SameTypeName(v.typ, t) {
+		// переменной без аннотации присваиваются значения разных типов
+		v.typ = ""
+	}
+}
+
+// expr проверяет выражение и возвращает тип его значения или пустую строку
+func (c *Checker) expr(e ast.Expr, sc *scope) string {
+	switch x := e.(type) {
+	case nil, *ast.NoneExpr:
+		return ""
+	case *ast.NativeExpr:
+		if x.Value == nil || x.Value == core.VMNil {
+			return core.TypeNilName
+		}
+		return x.Value.VMTypeString()
+	case *ast.NumberExpr:
+		for _, r := range x.Lit {
+			if r == '.' || r == 'e' || r == 'E' {
+				return core.TypeDecNumName
+			}
+		}
+		return core.TypeIntName
+	case *ast.StringExpr:
+		return core.TypeStringName
+	case *ast.ConstExpr:
+		return c.expr(x.Simplify(), sc)
+	case *ast.ArrayExpr:
+		for _, ee := range x.Exprs {
+			c.expr(ee, sc)
+		}
+		return "Массив"
+	case *ast.MapExpr:
+		for _, ee := range x.MapExpr {
+			c.expr(ee, sc)
+		}
+		return "Структура"
+	case *ast.PairExpr:
+		return c.expr(x.Value, sc)
+	case *ast.IdentExpr:
+		if v := sc.lookup(x.Id); v != nil {
+			return v.typ
+		}
+		if _, ok := c.funcs[x.Id]; ok {
+			return core.TypeFuncName
+		}
+		return ""
+	case *ast.ParenExpr:
+		return c.expr(x.SubExpr, sc)
+	case *ast.UnaryExpr:
+		t := c.expr(x.Expr, sc)
+		switch x.Operator {
+		case "!":
+			return core.TypeBoolName
+		case "-":
+			if c.assignable(core.TypeNumberName, t) {
+				return t
+			}
+			if t != "" && isPrimitive(t) {
+				c.errorf(x, "Операция %s недопустима для значения типа %s", x.Operator, t)
+			}
+		}
+		return ""
+	case *ast.BinOpExpr:
+		return c.binOp(x, sc)
+	case *ast.TernaryOpExpr:
+		c.expr(x.Expr, sc)
+		t1, t2 := c.expr(x.Lhs, sc), c.expr(x.Rhs, sc)
+		if core.SameTypeName(t1, t2) {
+			return t1
+		}
+		return ""
+	case *ast.CallExpr:
+		return c.call(x, x.Name, x.SubExprs, x.VarArg, sc)
+	case *ast.AnonCallExpr:
+		if m, ok := x.Expr.(*ast.MemberExpr); ok {
+			ot := c.expr(m.Expr, sc)
+			if cl := c.classes[names.UniqueNames.Set(ot)]; cl != nil && ot != "" {
+				if sig, ok := cl.methods[m.Name]; ok {
+					return c.callSig(x, sig, x.SubExprs, x.VarArg, sc)
+				}
+				if _, ok := cl.fields[m.Name]; !ok {
+					c.errorf(m, "Нет метода %s в типе %s", names.UniqueNames.Get(m.Name), cl.name)
+				}
+			}
+		} else {
+			c.expr(x.Expr, sc)
+		}
+		for _, a := range x.SubExprs {
+			c.expr(a, sc)
+		}
+		return ""
+	case *ast.MemberExpr:
+		ot := c.expr(x.Expr, sc)
+		if cl := c.classes[names.UniqueNames.Set(ot)]; cl != nil && ot != "" {
+			if ft, ok := cl.fields[x.Name]; ok {
+				return ft
+			}
+			if _, ok := cl.methods[x.Name]; ok {
+				return core.TypeFuncName
+			}
+			c.errorf(x, "Нет поля с именем %s в типе %s", names.UniqueNames.Get(x.Name), cl.name)
+		}
+		return ""
+	case *ast.ItemExpr:
+		t := c.expr(x.Value, sc)
+		c.expr(x.Index, sc)
+		if core.SameTypeName(t, core.TypeStringName) {
+			return t
+		}
+		return ""
+	case *ast.SliceExpr:
+		t := c.expr(x.Value, sc)
+		c.expr(x.Begin, sc)
+		c.expr(x.End, sc)
+		if core.SameTypeName(t, core.TypeStringName) || core.SameTypeName(t, "Массив") {
+			return t
+		}
+		return ""
+	case *ast.FuncExpr:
+		if x.Method {
+			return core.TypeFuncName
+		}
+		sig := c.funcs[x.Name]
+		if sig == nil || x.Name == names.UniqueNames.Set(binstmt.AnonFuncName) {
+			sig = c.signature(x)
+		}
+		for _, d := range x.Defaults {
+			c.expr(d, sc)
+		}
+		c.function(x, sig, sc, "")
+		if x.Name != names.UniqueNames.Set(binstmt.AnonFuncName) {
+			sc.owner(x.Name).vars[x.Name] = &variable{typ: core.TypeFuncName}
+		}
+		return core.TypeFuncName
+	case *ast.LetExpr:
+		t := c.expr(x.Rhs, sc)
+		c.assignTo(x.Lhs, t, sc)
+		return t
+	case *ast.AssocExpr:
+		lt := c.expr(x.Lhs, sc)
+		op := x.Operator
+		var rt string
+		if x.Rhs != nil {
+			rt = c.expr(x.Rhs, sc)
+			op = op[:len(op)-1]
+		} else {
+			// ++ и --
+			rt = core.TypeIntName
+			op = op[:1]
+		}
+		t, ok := opResult(core.OperMap[op], lt, rt)
+		if !ok {
+			c.errorf(x, "Операция %s недопустима для значений типов %s и %s", x.Operator, lt, rt)
+		}
+		c.assignTo(x.Lhs, t, sc)
+		return t
+	case *ast.TypeCast:
+		c.expr(x.CastExpr, sc)
+		if x.TypeExpr != nil {
+			c.expr(x.TypeExpr, sc)
+			return ""
+		}
+		return names.UniqueNames.Get(x.Type)
+	case *ast.MakeExpr:
+		for _, a := range x.SubExprs {
+			c.expr(a, sc)
+		}
+		if x.TypeExpr != nil {
+			c.expr(x.TypeExpr, sc)
+			return ""
+		}
+		if cl := c.classes[x.Type]; cl != nil {
+			if ctor, ok := cl.methods[names.UniqueNames.Set(core.ClassConstructorName)]; ok {
+				c.callSig(x, ctor, x.SubExprs, false, sc)
+			}
+			return cl.name
+		}
+		if _, err := c.env.Type(x.Type); err != nil {
+			c.errorf(x, "Тип неопределен '%s'", names.UniqueNames.Get(x.Type))
+			return ""
+		}
+		return names.UniqueNames.Get(x.Type)
+	case *ast.MakeChanExpr:
+		c.expr(x.SizeExpr, sc)
+		return "Канал"
+	case *ast.MakeArrayExpr:
+		c.expr(x.LenExpr, sc)
+		c.expr(x.CapExpr, sc)
+		return "Массив"
+	case *ast.ChanExpr:
+		c.expr(x.Lhs, sc)
+		c.expr(x.Rhs, sc)
+		return ""
+	}
+	return ""
+}
+
+func (c *Checker) binOp(x *ast.BinOpExpr, sc *scope) string {
+	if len(x.Lhss) != 1 || len(x.Rhss) != 1 {
+		for _, e := range x.Lhss {
+			c.expr(e, sc)
+		}
+		for _, e := range x.Rhss {
+			c.expr(e, sc)
+		}
+		return ""
+	}
+	lt, rt := c.expr(x.Lhss[0], sc), c.expr(x.Rhss[0], sc)
+	op := core.OperMap[x.Operator]
+	t, ok := opResult(op, lt, rt)
+	if !ok {
+		c.errorf(x, "Операция %s недопустима для значений типов %s и %s", x.Operator, lt, rt)
+		return ""
+	}
+	if c.Specialize && isInt(lt) && isInt(rt) {
+		// только операции, которые IntBinOp выполняет без обращения к общему коду
+		_, x.IntOp = core.IntBinOp(op, 0, 1)
+	}
+	return t
+}
+
+// call проверяет вызов функции по имени: объявленной в коде или встроенной
+func (c *Checker) call(p pos.Pos, name int, args []ast.Expr, vararg bool, sc *scope) string {
+	if v := sc.lookup(name); v != nil && c.funcs[name] == nil {
+		// вызов значения переменной
+		for _, a := range args {
+			c.expr(a, sc)
+		}
+		return ""
+	}
+	if sig := c.funcs[name]; sig != nil {
+		return c.callSig(p, sig, args, vararg, sc)
+	}
+	if sig, ok := core.SignatureById(name); ok {
+		return c.callBuiltin(p, name, sig, args, vararg, sc)
+	}
+	for _, a := range args {
+		c.expr(a, sc)
+	}
+	return ""
+}
+
+func (c *Checker) callSig(p pos.Pos, sig *function, args []ast.Expr, vararg bool, sc *scope) string {
+	ts := make([]string, len(args))
+	for i, a := range args {
+		ts[i] = c.expr(a, sc)
+	}
+	if vararg || sig.vararg {
+		return sig.ret
+	}
+	fname := names.UniqueNames.Get(sig.name)
+	if len(args) > len(sig.args) {
+		c.errorf(p, "Слишком много аргументов при вызове %s: %d вместо %d", fname, len(args), len(sig.args))
+		return sig.ret
+	}
+	if len(args) < sig.required {
+		c.errorf(p, "Недостаточно аргументов при вызове %s: %d вместо %d", fname, len(args), sig.required)
+	}
+	for i, a := range args {
+		if _, skipped := a.(*ast.NoneExpr); skipped || sig.types[i] == "" {
+			continue
+		}
+		if !c.assignable(sig.types[i], ts[i]) {
+			c.errorf(p, "Параметр %s функции %s имеет тип %s, передано значение типа %s",
+				names.UniqueNames.Get(sig.args[i]), fname, sig.types[i], ts[i])
+		}
+	}
+	return sig.ret
+}
+
+func (c *Checker) callBuiltin(p pos.Pos, name int, sig *core.VMSignature, args []ast.Expr, vararg bool, sc *scope) string {
+	ts := make([]string, len(args))
+	for i, a := range args {
+		ts[i] = c.expr(a, sc)
+	}
+	if vararg {
+		return sig.Result
+	}
+	fname := names.UniqueNames.Get(name)
+	if !sig.VarArgs && len(args) > len(sig.Params) {
+		c.errorf(p, "Слишком много аргументов при вызове %s: %d вместо %d", fname, len(args), len(sig.Params))
+	}
+	if len(args) < sig.Required {
+		c.errorf(p, "Недостаточно аргументов при вызове %s: %d вместо %d", fname, len(args), sig.Required)
+	}
+	for i := range args {
+		if i >= len(sig.Params) || sig.Params[i] == "" {
+			continue
+		}
+		if !c.assignable(sig.Params[i], ts[i]) {
+			c.errorf(p, "Параметр %d функции %s имеет тип %s, передано значение типа %s",
+				i+1, fname, sig.Params[i], ts[i])
+		}
+	}
+	return sig.Result
+}
+
+func isInt(t string) bool { return core.SameTypeName(t, core.TypeIntName) }
+
+func isNumber(t string) bool {
+	return isInt(t) || core.SameTypeName(t, core.TypeDecNumName) || core.SameTypeName(t, core.TypeNumberName)
+}
+
+func isPrimitive(t string) bool {
+	return isNumber(t) || core.SameTypeName(t, core.TypeStringName) || core.SameTypeName(t, core.TypeBoolName)
+}
+
+// opResult определяет тип результата бинарной операции над значениями известных типов
+// и сообщает false, если операция над ними гарантированно завершится ошибкой
+func opResult(op core.VMOperation, lt, rt string) (string, bool) {
+	switch op {
+	case core.LOR, core.LAND:
+		if core.SameTypeName(lt, core.TypeBoolName) && core.SameTypeName(rt, core.TypeBoolName) {
+			return core.TypeBoolName, true
+		}
+		return "", true
+	}
+	if lt == "" || rt == "" || !isPrimitive(lt) || !isPrimitive(rt) {
+		switch op {
+		case core.EQL, core.NEQ, core.GTR, core.GEQ, core.LSS, core.LEQ:
+			return core.TypeBoolName, true
+		}
+		return "", true
+	}
+	cmp := false
+	switch op {
+	case core.EQL, core.NEQ, core.GTR, core.GEQ, core.LSS, core.LEQ:
+		cmp = true
+	}
+	str := core.SameTypeName(lt, core.TypeStringName)
+	switch {
+	case isNumber(lt) && isNumber(rt):
+		switch {
+		case cmp:
+			return core.TypeBoolName, true
+		case op == core.QUO:
+			return core.TypeDecNumName, true
+		case isInt(lt) && isInt(rt):
+			switch op {
+			case core.ADD, core.SUB, core.MUL, core.REM, core.OR, core.AND, core.SHL, core.SHR:
+				return core.TypeIntName, true
+			}
+			return core.TypeNumberName, true
+		case core.SameTypeName(lt, core.TypeDecNumName) || core.SameTypeName(rt, core.TypeDecNumName):
+			return core.TypeDecNumName, true
+		}
+		return core.TypeNumberName, true
+	case str && core.SameTypeName(rt, core.TypeStringName):
+		switch {
+		case cmp:
+			return core.TypeBoolName, true
+		case op == core.ADD || op == core.SUB:
+			return core.TypeStringName, true
+		}
+		return "", false
+	case str && isNumber(rt):
+		if op == core.MUL {
+			return core.TypeStringName, true
+		}
+		return "", false
+	case isNumber(lt) && core.SameTypeName(rt, core.TypeStringName):
+		return "", false
+	case core.SameTypeName(lt, core.TypeBoolName) && core.SameTypeName(rt, core.TypeBoolName):
+		if cmp {
+			return core.TypeBoolName, true
+		}
+		return "", true
+	}
+	// сочетания с Булево выполнимы не всегда, но и не проверяются
+	return "", true
+}
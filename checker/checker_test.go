@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shinanca/gonec/ast"
+	"github.com/shinanca/gonec/bincode/binstmt"
+	"github.com/shinanca/gonec/parser"
+)
+
+func parse(t *testing.T, src string) ast.Stmts {
+	t.Helper()
+	s := &parser.Scanner{}
+	s.Init("Модуль _\n" + src)
+	stmts, err := parser.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parser.ConstFolding(stmts)
+}
+
+func check(t *testing.T, src string) []error {
+	t.Helper()
+	return Check(parse(t, src), false)
+}
+
+func TestCheckErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"параметр", "Функция Ф(а1: Число)\nКонецФункции\nФ(\"x\")", "Параметр а1 функции Ф имеет тип Число, передано значение типа Строка"},
+		{"переменная", "Перем з: Число = \"x\"", "Переменной з типа Число присваивается значение типа Строка"},
+		{"возврат", "Функция Г(): Число\n  Возврат \"x\"\nКонецФункции", "Функция Г должна возвращать значение типа Число, а не Строка"},
+		{"присваивание в ветке Когда", "Перем ч: Число = 1\nВыбор 2:\nКогда х:\n  ч = \"x\"\nКонецВыбора", "Переменной ч типа Число присваивается значение типа Строка"},
+		{"переменная в ветке Когда", "Перем ч: Число = 1\nВыбор 2:\nКогда ч:\n  ч = \"x\"\nКонецВыбора", "Переменной ч типа Число присваивается значение типа Строка"},
+		{"результат функции", "Функция Ф(): Строка\n  Возврат \"x\"\nКонецФункции\nПерем ч: Число = Ф()", "Переменной ч типа Число присваивается значение типа Строка"},
+	}
+	for _, tt := range tests {
+		errs := check(t, tt.src)
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.want) {
+			t.Errorf("%s: %v, want %q", tt.name, errs, tt.want)
+		}
+	}
+}
+
+func TestCheckValid(t *testing.T) {
+	src := `
+Функция Ф(а1: Число, б1: Строка = "x"): Строка
+  Возврат б1 + Строка(а1)
+КонецФункции
+Перем х: Строка = Ф(1)
+Перем ч: Число = 1.5
+ч = 2
+п = Ф(ч)
+Выбор [1, "x"]:
+Когда [ч, х]:
+  ч = "имя шаблона не связано с переменной снаружи"
+КонецВыбора
+`
+	if errs := check(t, src); len(errs) != 0 {
+		t.Errorf("ошибки в правильном коде: %v", errs)
+	}
+}
+
+func TestCheckSpecialize(t *testing.T) {
+	src := `
+Функция Сумма(а1: ЧислоЦелое, б1: ЧислоЦелое): ЧислоЦелое
+  Возврат а1 + б1
+КонецФункции
+Функция Любое(а1, б1)
+  Возврат а1 + б1
+КонецФункции
+`
+	stmts := parse(t, src)
+	if errs := Check(stmts, true); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	lid := 0
+	var ops []string
+	for _, st := range stmts.BinaryCode(0, &lid).Code {
+		switch st.(type) {
+		case *binstmt.BinOPINT:
+			ops = append(ops, "OPINT")
+		case *binstmt.BinOPER:
+			ops = append(ops, "OPER")
+		}
+	}
+	if strings.Join(ops, ",") != "OPINT,OPER" {
+		t.Errorf("инструкции %v, want [OPINT OPER]", ops)
+	}
+}
@@ -2,6 +2,8 @@
 package core
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
@@ -12,7 +14,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
@@ -23,7 +24,6 @@ import (
 	"C"
 	"github.com/djimenez/iconv-go"
 	"github.com/shinanca/gonec/externallibs/go-xlsx-templater"
-	"github.com/shinanca/gonec/names"
 	"moul.io/number-to-words"
 )
 
@@ -69,6 +69,28 @@ func intToWords(num int) string {
 	return resultString
 }
 
+// templateData заменяет в данных для макетов XLSX и PDF штрихкоды на пути к их картинкам,
+// чтобы их можно было вставить в макет через {{image поле}} или <img src="{{поле}}">
+func templateData(v VMValue) VMValue {
+	switch vv := v.(type) {
+	case VMStringMap:
+		rv := make(VMStringMap, len(vv))
+		for k, el := range vv {
+			rv[k] = templateData(el)
+		}
+		return rv
+	case VMSlice:
+		rv := make(VMSlice, len(vv))
+		for i, el := range vv {
+			rv[i] = templateData(el)
+		}
+		return rv
+	case imageFiler:
+		return VMString(vv.imagePath())
+	}
+	return v
+}
+
 func XmlToJson(xmlString string) (string, error) {
 	node := &XMLNode{}
 
@@ -146,8 +168,55 @@ func Import(env *Env) *Env {
 		return nil
 	}))
 
-	env.DefineS("текущаядата", VMFuncZeroParams(func(rets *VMSlice) error {
-		rets.Append(Now())
+	// ТекущаяДата(Зона) - в часовом поясе системы, если зона не указана
+	env.DefineS("текущаядата", VMFuncNParamsOptionals(0, 1, func(args VMSlice, rets *VMSlice) error {
+		loc, err := timeZoneArg(args, 0)
+		if err != nil {
+			return err
+		}
+		rets.Append(VMTime(time.Now().In(loc)))
+		return nil
+	}))
+
+	// НоваяДата(Год, Месяц, День, Час, Минута, Секунда, Зона) - зона-строка может следовать
+	// за любым количеством частей даты, начиная с дня: НоваяДата(2024, 1, 2, "UTC")
+	env.DefineS("новаядата", VMFuncNParamsOptionals(3, 4, func(args VMSlice, rets *VMSlice) error {
+		loc := time.Local
+		if last := len(args) - 1; last >= 3 {
+			if _, ok := args[last].(VMString); ok {
+				var err error
+				if loc, err = timeZoneArg(args, last); err != nil {
+					return err
+				}
+				args = args[:last]
+			}
+		}
+		if len(args) > 6 {
+			return VMErrorNeedInt
+		}
+		var parts [6]int
+		for i := range args {
+			n, ok := args[i].(VMInt)
+			if !ok {
+				return VMErrorNeedInt
+			}
+			parts[i] = int(n)
+		}
+		rets.Append(VMTime(time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], parts[5], 0, loc)))
+		return nil
+	}))
+
+	// РазобратьДату(Строка, Зона) - зона применяется, если смещение не указано в самой строке
+	env.DefineS("разобратьдату", VMFuncOneParamOptionals(1, func(s VMString, rest VMSlice, rets *VMSlice) error {
+		loc, err := timeZoneArg(rest, 0)
+		if err != nil {
+			return err
+		}
+		t, err := ParseTimeInLocation(string(s), loc)
+		if err != nil {
+			return err
+		}
+		rets.Append(t)
 		return nil
 	}))
 
@@ -329,18 +398,54 @@ func Import(env *Env) *Env {
 		return nil
 	}))
 
+	// Формат(ФорматнаяСтрока, Значения...) - строка формата Go, например Формат("%s: %.2f", Имя, Сумма);
+	// Формат(Значение, ФорматнаяСтрока) - форматная строка 1С ("ЧДЦ=2") или Go ("%.2f") для одного значения.
+	// Первый параметр-строка с подстановками Go всегда считается форматной строкой,
+	// иначе вид определяется по синтаксису второго параметра, а не по типу первого.
 	env.DefineS("формат", VMFunc(func(args VMSlice, rets *VMSlice) error {
 		if len(args) < 2 {
 			return VMErrorNeedFormatAndArgs
 		}
-		if v, ok := args[0].(VMString); ok {
-			as := VMSlice(args[1:]).Args()
-			rets.Append(VMString(env.Sprintf(string(v), as...)))
+		first, firstStr := args[0].(VMString)
+		if firstStr && hasFormatVerbs(string(first)) {
+			rets.Append(VMString(env.Sprintf(string(first), args[1:].Args()...)))
 			return nil
 		}
-		return VMErrorNeedString
+		if f, ok := args[1].(VMString); ok && len(args) == 2 {
+			// пустая форматная строка задает представление по умолчанию для значений, но не для строк
+			if IsFormatString1C(string(f)) || (!firstStr && strings.TrimSpace(string(f)) == "") {
+				res, err := Format1C(args[0], string(f))
+				if err != nil {
+					return err
+				}
+				rets.Append(VMString(res))
+				return nil
+			}
+			if strings.Contains(string(f), "%") {
+				rets.Append(VMString(env.Sprintf(string(f), args[0])))
+				return nil
+			}
+			if !firstStr {
+				// ни 1С, ни Go - ошибка укажет неизвестный параметр
+				_, err := Format1C(args[0], string(f))
+				if err == nil {
+					err = VMErrorFormatString
+				}
+				return err
+			}
+		}
+		if !firstStr {
+			return VMErrorNeedString
+		}
+		rets.Append(VMString(env.Sprintf(string(first), args[1:].Args()...)))
+		return nil
 	}))
 
+	env.DefineS("окр", VMFuncNParamsOptionals(1, 2, Окр))
+	env.DefineS("режимокругления", RoundingModes())
+	env.DefineS("распределитьпропорционально", VMFuncNParamsOptionals(2, 1, РаспределитьПропорционально))
+	env.DefineS("деньги", VMFuncNParamsOptionals(1, 2, Деньги))
+
 	env.DefineS("кодсимвола", VMFuncOneParam(func(vms VMStringer, rets *VMSlice) error {
 		s := vms.String()
 		if len(s) == 0 {
@@ -352,11 +457,17 @@ func Import(env *Env) *Env {
 	}))
 
 	env.DefineS("типзнч", VMFuncNParams(1, func(args VMSlice, rets *VMSlice) error {
-		if args[0] == nil || args[0] == VMNil {
-			rets.Append(VMString("Неопределено"))
-			return nil
+		rets.Append(VMString(env.ValueTypeName(args[0])))
+		return nil
+	}))
+
+	// РеализуетИнтерфейс(Значение, Интерфейс) проверяет наличие у значения всех методов интерфейса
+	env.DefineS("реализуетинтерфейс", VMFuncNParams(2, func(args VMSlice, rets *VMSlice) error {
+		i, ok := args[1].(*VMInterface)
+		if !ok {
+			return errors.New("Вторым параметром требуется интерфейс")
 		}
-		rets.Append(VMString(names.UniqueNames.Get(env.TypeName(reflect.TypeOf(args[0])))))
+		rets.Append(VMBool(i.ImplementedBy(args[0])))
 		return nil
 	}))
 
@@ -370,18 +481,14 @@ func Import(env *Env) *Env {
 		return nil
 	}))
 
-	env.DefineS("ЗаполнитьМакетXLSX", VMFunc(func(args VMSlice, rets *VMSlice) error {
-		if len(args) != 3 {
-			return VMErrorNeedArgs(3)
-		}
+	env.DefineS("ЗаполнитьМакетXLSX", VMFuncThreeParams(func(tpl VMString, data VMStringMap, out VMString, rets *VMSlice) error {
 		doc := xlst.New()
-		err := doc.ReadTemplate(string(args[0].(VMString)))
+		err := doc.ReadTemplate(string(tpl))
 		if err != nil {
-			return VMErrorReadXlsxTemplate
+			return fmt.Errorf("%w: %s", VMErrorReadXlsxTemplate, err)
 		}
 
-		data := args[1].(VMStringMap)
-		jsonStr, err := data.MarshalJSON()
+		jsonStr, err := templateData(data).(VMStringMap).MarshalJSON()
 		if err != nil {
 			return VMErrorFillXlsx
 		}
@@ -391,12 +498,16 @@ func Import(env *Env) *Env {
 		}
 		err = doc.Render(newData)
 		if err != nil {
+			var cellErr *xlst.CellError
+			if errors.As(err, &cellErr) {
+				return VMErrorFillXlsxCell(cellErr.Sheet, cellErr.Cell, cellErr.Placeholder, cellErr.Err)
+			}
 			return VMErrorFillXlsx
 		}
 
-		err = doc.Save(string(args[2].(VMString)))
+		err = doc.Save(string(out))
 		if err != nil {
-			return VMErrorSaveXlsx
+			return fmt.Errorf("%w: %s", VMErrorSaveXlsx, err)
 		}
 		return nil
 	}))
@@ -436,6 +547,38 @@ func Import(env *Env) *Env {
 		return nil
 	}))
 
+	env.DefineS("ДвоичныеДанныеИзBase64", VMFuncOneParam(func(s VMString, rets *VMSlice) error {
+		b, err := base64.StdEncoding.DecodeString(string(s))
+		if err != nil {
+			return err
+		}
+		rets.Append(VMBinaryData(b))
+		return nil
+	}))
+
+	env.DefineS("ДвоичныеДанныеИзHex", VMFuncOneParam(func(s VMString, rets *VMSlice) error {
+		b, err := hex.DecodeString(string(s))
+		if err != nil {
+			return err
+		}
+		rets.Append(VMBinaryData(b))
+		return nil
+	}))
+
+	// ДвоичныеДанныеИзСтроки(Строка, Кодировка) - байты строки в указанной кодировке, по умолчанию UTF-8
+	env.DefineS("ДвоичныеДанныеИзСтроки", VMFuncOneParamOptionals(1, func(s VMString, rest VMSlice, rets *VMSlice) error {
+		if len(rest) == 0 || isUTF8(rest[0].(VMStringer).String()) {
+			rets.Append(VMBinaryData(s))
+			return nil
+		}
+		out, err := iconv.ConvertString(string(s), "utf-8", rest[0].(VMStringer).String())
+		if err != nil {
+			return VMErrorEncoding
+		}
+		rets.Append(VMBinaryData(out))
+		return nil
+	}))
+
 	env.DefineS("чтениеизстрокиxml", VMFunc(func(args VMSlice, rets *VMSlice) error {
 		if len(args) != 1 {
 			env.Println()
@@ -513,11 +656,6 @@ func Import(env *Env) *Env {
 		return nil
 	}))
 
-	env.DefineS("текущаядата", VMFuncZeroParams(func(rets *VMSlice) error {
-		rets.Append(VMTime(time.Now()))
-		return nil
-	}))
-
 	// при изменении состава типов не забывать изменять их и в lexer.go
 	env.DefineTypeS(ReflectVMNul)
 	env.DefineTypeS(ReflectVMInt)
@@ -526,9 +664,11 @@ func Import(env *Env) *Env {
 	env.DefineTypeS(ReflectVMString)
 	env.DefineTypeS(ReflectVMSlice)
 	env.DefineTypeS(ReflectVMStringMap)
+	env.DefineTypeS(ReflectVMOrderedMap)
 	env.DefineTypeS(ReflectVMTime)
 	env.DefineTypeS(ReflectVMTimeDuration)
 	env.DefineTypeS(ReflectVMFunc)
+	env.DefineTypeS(ReflectVMBinaryData)
 
 	env.DefineTypeS(ReflectVMWaitGroup)
 	env.DefineTypeS(ReflectVMBoltDB)
@@ -540,6 +680,12 @@ func Import(env *Env) *Env {
 	env.DefineTypeStruct(&RconClient{})
 	env.DefineTypeStruct(&TextDocument{})
 	env.DefineTypeStruct(&File{})
+	env.DefineTypeStruct(&VMMemoryStream{})
+	env.DefineTypeStruct(&VMFileStream{})
+	env.DefineTypeStruct(&VMZipWriter{})
+	env.DefineTypeStruct(&VMZipReader{})
+	env.DefineTypeStruct(&VMTarGzWriter{})
+	env.DefineTypeStruct(&VMTarGzReader{})
 
 	env.DefineTypeStruct(&QrCode{})
 	env.DefineTypeStruct(&DataMatrix{})
@@ -551,6 +697,20 @@ func Import(env *Env) *Env {
 	env.DefineTypeStruct(&VMTableColumns{})
 	env.DefineTypeStruct(&VMTableLine{})
 
+	env.DefineTypeStruct(&VMSet{})
+	env.DefineTypeStruct(&VMQueue{})
+	env.DefineTypeStruct(&VMDeque{})
+	env.DefineTypeStruct(&VMPriorityQueue{})
+
+	env.DefineTypeStruct(&VMCalendar{})
+	env.DefineTypeStruct(&VMScheduler{})
+
+	env.DefineTypeStruct(&VMCSVReader{})
+	env.DefineTypeStruct(&VMCSVWriter{})
+
+	env.DefineTypeStruct(&VMPDFDocument{})
+	env.DefineTypeStruct(&VMTemplate{})
+
 	env.DefineTypeStruct(&EmailProfile{})
 	env.DefineTypeStruct(&EmailData{})
 
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/shinanca/gonec/names"
+)
+
+// Необязательные аннотации типов в коде на языке Гонец:
+//
+//	Перем Итого: Число = 0
+//
+//	Функция Повторить(Текст: Строка, Раз: ЦелоеЧисло = 2): Строка
+//		...
+//	КонецФункции
+//
+// Типы параметров и возвращаемого значения проверяются при вызове функции,
+// остальное проверяет статический анализ командой gonec -check.
+// В аннотации можно указать имя системного или пользовательского типа, интерфейс,
+// Число (ЧислоЦелое или ЧислоСТочкой) и Произвольный (значение любого типа).
+
+const (
+	TypeAnyName     = "Произвольный"
+	TypeNumberName  = "Число"
+	TypeIntName     = "ЧислоЦелое"
+	TypeDecNumName  = "ЧислоСТочкой"
+	TypeNilName     = "Неопределено"
+	TypeBoolName    = "Булево"
+	TypeStringName  = "Строка"
+	TypeFuncName    = "Функция"
+	typeIntKeyword  = "целоечисло" // ключевое слово приведения типа ЦелоеЧисло(...)
+	typeNumberLower = "число"
+)
+
+// canonicalTypeName приводит имя типа к нижнему регистру и раскрывает синонимы
+func canonicalTypeName(s string) string {
+	s = names.FastToLower(s)
+	if s == typeIntKeyword {
+		return names.FastToLower(TypeIntName)
+	}
+	return s
+}
+
+// TypeAssignable сообщает, может ли значение типа got использоваться там, где объявлен тип want.
+// Число совместимо с ЧислоЦелое и ЧислоСТочкой в обе стороны, т.к. при статическом анализе
+// о значении типа Число неизвестно, целое оно или нет. Пустое имя означает неизвестный тип.
+func TypeAssignable(want, got string) bool {
+	w, g := canonicalTypeName(want), canonicalTypeName(got)
+	anyt := names.FastToLower(TypeAnyName)
+	if w == "" || g == "" || w == anyt || g == anyt || w == g {
+		return true
+	}
+	isnum := func(s string) bool {
+		return s == names.FastToLower(TypeIntName) || s == names.FastToLower(TypeDecNumName)
+	}
+	return (w == typeNumberLower && isnum(g)) || (g == typeNumberLower && isnum(w))
+}
+
+// SameTypeName сравнивает имена типов без учета регистра и синонимов
+func SameTypeName(a, b string) bool {
+	return canonicalTypeName(a) == canonicalTypeName(b)
+}
+
+// ValueTypeName возвращает имя типа значения, как его выдает ТипЗнч
+func (e *Env) ValueTypeName(v VMValue) string {
+	if v == nil || v == VMNil {
+		return TypeNilName
+	}
+	if o, ok := v.(*VMObject); ok {
+		// пользовательский тип
+		return o.VMTypeString()
+	}
+	id := e.TypeName(reflect.TypeOf(v))
+	if _, err := e.Type(id); err != nil {
+		// значения, которые не создаются через Новый, например, генераторы и итераторы
+		return v.VMTypeString()
+	}
+	return names.UniqueNames.Get(id)
+}
+
+// CheckValueType проверяет значение на соответствие аннотации типа typ (идентификатор имени).
+// Если это имя интерфейса, проверяется наличие у значения его методов.
+func (e *Env) CheckValueType(v VMValue, typ int) error {
+	want := names.UniqueNames.Get(typ)
+	got := e.ValueTypeName(v)
+	if TypeAssignable(want, got) {
+		return nil
+	}
+	if x, err := e.Get(typ); err == nil {
+		if i, ok := x.(*VMInterface); ok && i.ImplementedBy(v) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Требуется значение типа %s, получено %s", want, got)
+}
+
+// IsTypeName сообщает, является ли имя типом значения: системным, пользовательским, интерфейсом
+// или одним из имен, допустимых только в аннотациях (Число, Произвольный)
+func (e *Env) IsTypeName(typ int) bool {
+	switch canonicalTypeName(names.UniqueNames.Get(typ)) {
+	case names.FastToLower(TypeAnyName), typeNumberLower, names.FastToLower(TypeIntName),
+		names.FastToLower(TypeDecNumName), names.FastToLower(TypeNilName):
+		return true
+	}
+	if _, err := e.Type(typ); err == nil {
+		return true
+	}
+	x, err := e.Get(typ)
+	if err != nil {
+		return false
+	}
+	_, ok := x.(*VMInterface)
+	return ok
+}
+
+// VMSignature описывает параметры и результат встроенной функции для статической проверки типов.
+// Пустое имя типа означает значение любого типа.
+type VMSignature struct {
+	Params   []string
+	Required int  // количество обязательных параметров, остальные необязательные
+	VarArgs  bool // количество параметров не ограничено, для лишних тип не проверяется
+	Result   string
+}
+
+var (
+	vmSignaturesMu sync.RWMutex
+	vmSignatures   = make(map[int]*VMSignature)
+)
+
+// DefineSignature регистрирует сигнатуру встроенной функции с именем name
+func DefineSignature(name string, sig *VMSignature) {
+	vmSignaturesMu.Lock()
+	vmSignatures[names.UniqueNames.Set(name)] = sig
+	vmSignaturesMu.Unlock()
+}
+
+// SignatureById возвращает сигнатуру встроенной функции по идентификатору имени
+func SignatureById(id int) (*VMSignature, bool) {
+	vmSignaturesMu.RLock()
+	sig, ok := vmSignatures[id]
+	vmSignaturesMu.RUnlock()
+	return sig, ok
+}
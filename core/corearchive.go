@@ -0,0 +1,881 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// archiveAdder общая часть записи zip и tar.gz, данные передаются потоком
+type archiveAdder interface {
+	addDir(name string, mt time.Time) error
+	addReader(name string, r io.Reader, size int64, mt time.Time, mode fs.FileMode) error
+}
+
+// archiveName приводит имя элемента к виду, принятому в архивах: прямые слэши, без ведущего слэша
+func archiveName(name string) string {
+	return strings.TrimLeft(filepath.ToSlash(name), "/")
+}
+
+func addFileToArchive(a archiveAdder, fpath, name string) error {
+	f, err := os.Open(fpath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return addDirToArchive(a, fpath, name)
+	}
+	return a.addReader(archiveName(name), f, fi.Size(), fi.ModTime(), fi.Mode())
+}
+
+// addDirToArchive рекурсивно добавляет каталог, имена элементов строятся от name
+func addDirToArchive(a archiveAdder, dir, name string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		an := archiveName(filepath.Join(name, rel))
+		if d.IsDir() {
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if an == "" {
+				return nil
+			}
+			return a.addDir(an+"/", fi.ModTime())
+		}
+		if !d.Type().IsRegular() {
+			// ссылки и специальные файлы не архивируем
+			return nil
+		}
+		return addFileToArchive(a, p, an)
+	})
+}
+
+// archiveTarget открывает файл для записи архива или использует переданный поток
+func archiveTarget(v VMValue) (io.Writer, io.Closer, error) {
+	switch vv := v.(type) {
+	case VMString:
+		f, err := os.Create(string(vv))
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, f, nil
+	case io.Writer:
+		return vv, nil, nil
+	}
+	return nil, nil, VMErrorNeedArchiveSource
+}
+
+// extractPath возвращает путь для извлечения элемента, не выходящий за пределы каталога
+func extractPath(dir, name string) (string, error) {
+	base := filepath.Clean(dir)
+	p := filepath.Join(base, filepath.FromSlash(name))
+	if p != base && !strings.HasPrefix(p, base+string(os.PathSeparator)) {
+		return "", VMErrorArchiveUnsafePath
+	}
+	return p, nil
+}
+
+func extractToFile(p string, r io.Reader, mode fs.FileMode, mt time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	if mode&0o777 == 0 {
+		mode = 0o644
+	}
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode&0o777)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(p, mt, mt)
+}
+
+// matchArchiveName проверяет имя элемента по маске, маска сравнивается с полным именем и с именем файла
+func matchArchiveName(mask, name string) bool {
+	if mask == "" {
+		return true
+	}
+	name = strings.TrimSuffix(name, "/")
+	if ok, _ := path.Match(mask, name); ok {
+		return true
+	}
+	ok, _ := path.Match(mask, path.Base(name))
+	return ok
+}
+
+func optionalMask(rest VMSlice) (string, error) {
+	if len(rest) == 0 {
+		return "", nil
+	}
+	s, ok := rest[0].(VMString)
+	if !ok {
+		return "", VMErrorNeedString
+	}
+	return string(s), nil
+}
+
+func archiveEntry(name string, size, csize int64, isdir, encrypted bool, mt time.Time) VMStringMap {
+	rv := make(VMStringMap)
+	rv["Имя"] = VMString(name)
+	rv["Размер"] = VMInt(size)
+	rv["СжатыйРазмер"] = VMInt(csize)
+	rv["ЭтоКаталог"] = VMBool(isdir)
+	rv["Зашифрован"] = VMBool(encrypted)
+	rv["ВремяИзменения"] = VMTime(mt)
+	return rv
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// crcReader сверяет контрольную сумму по окончании чтения
+type crcReader struct {
+	r   io.Reader
+	h   hash.Hash32
+	crc uint32
+}
+
+func (c *crcReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.h.Write(p[:n])
+	if err == io.EOF && c.h.Sum32() != c.crc {
+		return n, zip.ErrChecksum
+	}
+	return n, err
+}
+
+func msDosTime(t time.Time) (date, tm uint16) {
+	date = uint16(t.Day() + int(t.Month())<<5 + (t.Year()-1980)<<9)
+	tm = uint16(t.Second()/2 + t.Minute()<<5 + t.Hour()<<11)
+	return
+}
+
+// VMZipWriter ЗаписьZipФайла(ИмяФайлаИлиПоток, Пароль) - при указании пароля
+// элементы шифруются традиционным методом ZipCrypto
+type VMZipWriter struct {
+	VMMetaObj
+
+	zw       *zip.Writer
+	closer   io.Closer
+	password string
+}
+
+func (x *VMZipWriter) VMTypeString() string {
+	return "ЗаписьZipФайла"
+}
+
+func (x *VMZipWriter) VMRegister() {
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) < 1 || len(args) > 2 {
+			return VMErrorNeedArgs(1)
+		}
+		pwd := ""
+		if len(args) > 1 {
+			s, ok := args[1].(VMString)
+			if !ok {
+				return VMErrorNeedString
+			}
+			pwd = string(s)
+		}
+		w, c, err := archiveTarget(args[0])
+		if err != nil {
+			return err
+		}
+		x.Create(w, c, pwd)
+		return nil
+	})
+
+	x.VMRegisterMethod("ДобавитьФайл", VMFuncOneParamOptionals(1, x.ДобавитьФайл))
+	x.VMRegisterMethod("ДобавитьКаталог", VMFuncOneParamOptionals(1, x.ДобавитьКаталог))
+	x.VMRegisterMethod("ДобавитьДанные", VMFuncTwoParams(x.ДобавитьДанные))
+	x.VMRegisterMethod("Записать", VMFuncZeroParams(x.Записать))
+	x.VMRegisterMethod("Закрыть", VMFuncZeroParams(x.Записать))
+}
+
+func (x *VMZipWriter) Create(w io.Writer, c io.Closer, password string) {
+	x.zw = zip.NewWriter(w)
+	x.closer = c
+	x.password = password
+}
+
+func (x *VMZipWriter) addDir(name string, mt time.Time) error {
+	if x.zw == nil {
+		return VMErrorArchiveClosed
+	}
+	fh := &zip.FileHeader{Name: name, Method: zip.Store, Modified: mt}
+	fh.SetMode(fs.ModeDir | 0o755)
+	_, err := x.zw.CreateHeader(fh)
+	return err
+}
+
+func (x *VMZipWriter) addReader(name string, r io.Reader, size int64, mt time.Time, mode fs.FileMode) error {
+	if x.zw == nil {
+		return VMErrorArchiveClosed
+	}
+	fh := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: mt}
+	fh.SetMode(mode)
+	if x.password == "" {
+		w, err := x.zw.CreateHeader(fh)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, r)
+		return err
+	}
+
+	// зашифрованный элемент пишется в сыром виде, размеры и контрольная сумма
+	// попадают в дескриптор данных после содержимого
+	fh.Flags |= 0x1 | 0x8
+	if !isASCII(name) && utf8.ValidString(name) {
+		fh.Flags |= 0x800
+	}
+	fh.ModifiedDate, fh.ModifiedTime = msDosTime(mt)
+	w, err := x.zw.CreateRaw(fh)
+	if err != nil {
+		return err
+	}
+	cw := &countWriter{w: w}
+	ew, err := newZipCryptoWriter(cw, x.password, byte(fh.ModifiedTime>>8))
+	if err != nil {
+		return err
+	}
+	fw, err := flate.NewWriter(ew, flate.DefaultCompression)
+	if err != nil {
+		return err
+	}
+	h := crc32.NewIEEE()
+	n, err := io.Copy(io.MultiWriter(fw, h), r)
+	if err != nil {
+		return err
+	}
+	if err = fw.Close(); err != nil {
+		return err
+	}
+	fh.CRC32 = h.Sum32()
+	fh.CompressedSize64 = uint64(cw.n)
+	fh.UncompressedSize64 = uint64(n)
+	if fh.CompressedSize64 >= 1<<32-1 || fh.UncompressedSize64 >= 1<<32-1 {
+		fh.CompressedSize, fh.UncompressedSize = 1<<32-1, 1<<32-1
+	} else {
+		fh.CompressedSize, fh.UncompressedSize = uint32(fh.CompressedSize64), uint32(fh.UncompressedSize64)
+	}
+	return nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// ДобавитьФайл(ПутьКФайлу, ИмяВАрхиве) - по умолчанию в корень архива под своим именем
+func (x *VMZipWriter) ДобавитьФайл(p VMString, rest VMSlice, rets *VMSlice) error {
+	name, err := optionalArchiveName(p, rest)
+	if err != nil {
+		return err
+	}
+	return addFileToArchive(x, string(p), name)
+}
+
+// ДобавитьКаталог(ПутьККаталогу, ИмяВАрхиве) - добавляет каталог со всем содержимым
+func (x *VMZipWriter) ДобавитьКаталог(p VMString, rest VMSlice, rets *VMSlice) error {
+	name, err := optionalArchiveName(p, rest)
+	if err != nil {
+		return err
+	}
+	return addDirToArchive(x, string(p), name)
+}
+
+func (x *VMZipWriter) ДобавитьДанные(name VMString, v VMValue, rets *VMSlice) error {
+	b, err := binaryArg(v)
+	if err != nil {
+		return err
+	}
+	return x.addReader(archiveName(string(name)), bytes.NewReader(b), int64(len(b)), time.Now(), 0o644)
+}
+
+func (x *VMZipWriter) Записать(rets *VMSlice) error {
+	return x.Close()
+}
+
+func (x *VMZipWriter) Close() error {
+	if x.zw == nil {
+		return nil
+	}
+	err := x.zw.Close()
+	x.zw = nil
+	if x.closer != nil {
+		if cerr := x.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func optionalArchiveName(p VMString, rest VMSlice) (string, error) {
+	if len(rest) == 0 {
+		return filepath.Base(string(p)), nil
+	}
+	s, ok := rest[0].(VMString)
+	if !ok {
+		return "", VMErrorNeedString
+	}
+	return string(s), nil
+}
+
+// readSeekerAt позволяет читать zip из потока с произвольным доступом
+type readSeekerAt struct {
+	rs io.ReadSeeker
+}
+
+func (r readSeekerAt) ReadAt(p []byte, off int64) (int, error) {
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(r.rs, p)
+}
+
+// VMZipReader ЧтениеZipФайла(ИмяФайлаИлиПотокИлиДвоичныеДанные, Пароль)
+type VMZipReader struct {
+	VMMetaObj
+
+	zr       *zip.Reader
+	closer   io.Closer
+	password string
+}
+
+func (x *VMZipReader) VMTypeString() string {
+	return "ЧтениеZipФайла"
+}
+
+func (x *VMZipReader) VMRegister() {
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) < 1 || len(args) > 2 {
+			return VMErrorNeedArgs(1)
+		}
+		if len(args) > 1 {
+			s, ok := args[1].(VMString)
+			if !ok {
+				return VMErrorNeedString
+			}
+			x.password = string(s)
+		}
+		switch vv := args[0].(type) {
+		case VMString:
+			return x.Open(string(vv))
+		case VMBinaryData:
+			return x.openReaderAt(bytes.NewReader(vv), int64(len(vv)), nil)
+		case *VMMemoryStream:
+			return x.openReaderAt(bytes.NewReader(vv.Bytes()), int64(len(vv.Bytes())), nil)
+		case io.ReadSeeker:
+			size, err := vv.Seek(0, io.SeekEnd)
+			if err != nil {
+				return err
+			}
+			return x.openReaderAt(readSeekerAt{vv}, size, nil)
+		}
+		return VMErrorNeedArchiveSource
+	})
+
+	x.VMRegisterMethod("Элементы", VMFuncZeroParams(x.Элементы))
+	x.VMRegisterMethod("Извлечь", VMFuncTwoParams(x.Извлечь))
+	x.VMRegisterMethod("ИзвлечьВсе", VMFuncOneParamOptionals(1, x.ИзвлечьВсе))
+	x.VMRegisterMethod("ПолучитьДанные", VMFuncOneParam(x.ПолучитьДанные))
+	x.VMRegisterMethod("Закрыть", VMFuncZeroParams(x.Закрыть))
+}
+
+func (x *VMZipReader) Open(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return x.openReaderAt(f, fi.Size(), f)
+}
+
+func (x *VMZipReader) openReaderAt(r io.ReaderAt, size int64, c io.Closer) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		if c != nil {
+			c.Close()
+		}
+		return err
+	}
+	x.zr, x.closer = zr, c
+	return nil
+}
+
+func (x *VMZipReader) find(name string) (*zip.File, error) {
+	if x.zr == nil {
+		return nil, VMErrorArchiveClosed
+	}
+	for _, f := range x.zr.File {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return nil, VMErrorArchiveEntryNotFound
+}
+
+// openFile возвращает распакованное содержимое элемента, при необходимости расшифровывая его
+func (x *VMZipReader) openFile(f *zip.File) (io.ReadCloser, error) {
+	if f.Flags&0x1 == 0 {
+		return f.Open()
+	}
+	if x.password == "" {
+		return nil, VMErrorZipPassword
+	}
+	raw, err := f.OpenRaw()
+	if err != nil {
+		return nil, err
+	}
+	check := byte(f.CRC32 >> 24)
+	if f.Flags&0x8 != 0 {
+		check = byte(f.ModifiedTime >> 8)
+	}
+	dr, err := newZipCryptoReader(raw, x.password, check)
+	if err != nil {
+		return nil, err
+	}
+	var rc io.ReadCloser
+	switch f.Method {
+	case zip.Store:
+		rc = io.NopCloser(dr)
+	case zip.Deflate:
+		rc = flate.NewReader(dr)
+	default:
+		return nil, VMErrorZipMethod
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{&crcReader{r: rc, h: crc32.NewIEEE(), crc: f.CRC32}, rc}, nil
+}
+
+func (x *VMZipReader) extract(f *zip.File, dir string) error {
+	p, err := extractPath(dir, f.Name)
+	if err != nil {
+		return err
+	}
+	if f.FileInfo().IsDir() {
+		return os.MkdirAll(p, 0o755)
+	}
+	rc, err := x.openFile(f)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return extractToFile(p, rc, f.Mode(), f.Modified)
+}
+
+// Элементы() возвращает массив структур с полями Имя, Размер, СжатыйРазмер, ЭтоКаталог, Зашифрован, ВремяИзменения
+func (x *VMZipReader) Элементы(rets *VMSlice) error {
+	if x.zr == nil {
+		return VMErrorArchiveClosed
+	}
+	rv := make(VMSlice, 0, len(x.zr.File))
+	for _, f := range x.zr.File {
+		rv = append(rv, archiveEntry(f.Name, int64(f.UncompressedSize64), int64(f.CompressedSize64),
+			f.FileInfo().IsDir(), f.Flags&0x1 != 0, f.Modified))
+	}
+	rets.Append(rv)
+	return nil
+}
+
+func (x *VMZipReader) Извлечь(name, dir VMString, rets *VMSlice) error {
+	f, err := x.find(string(name))
+	if err != nil {
+		return err
+	}
+	return x.extract(f, string(dir))
+}
+
+// ИзвлечьВсе(Каталог, Маска) - маска вида "*.xml" сравнивается с полным именем и с именем файла
+func (x *VMZipReader) ИзвлечьВсе(dir VMString, rest VMSlice, rets *VMSlice) error {
+	if x.zr == nil {
+		return VMErrorArchiveClosed
+	}
+	mask, err := optionalMask(rest)
+	if err != nil {
+		return err
+	}
+	for _, f := range x.zr.File {
+		if !matchArchiveName(mask, f.Name) {
+			continue
+		}
+		if err := x.extract(f, string(dir)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *VMZipReader) ПолучитьДанные(name VMString, rets *VMSlice) error {
+	f, err := x.find(string(name))
+	if err != nil {
+		return err
+	}
+	rc, err := x.openFile(f)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBinaryData(b))
+	return nil
+}
+
+func (x *VMZipReader) Закрыть(rets *VMSlice) error {
+	x.zr = nil
+	if x.closer != nil {
+		c := x.closer
+		x.closer = nil
+		return c.Close()
+	}
+	return nil
+}
+
+// VMTarGzWriter ЗаписьTarGzФайла(ИмяФайлаИлиПоток)
+type VMTarGzWriter struct {
+	VMMetaObj
+
+	gw     *gzip.Writer
+	tw     *tar.Writer
+	closer io.Closer
+}
+
+func (x *VMTarGzWriter) VMTypeString() string {
+	return "ЗаписьTarGzФайла"
+}
+
+func (x *VMTarGzWriter) VMRegister() {
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) != 1 {
+			return VMErrorNeedArgs(1)
+		}
+		w, c, err := archiveTarget(args[0])
+		if err != nil {
+			return err
+		}
+		x.Create(w, c)
+		return nil
+	})
+
+	x.VMRegisterMethod("ДобавитьФайл", VMFuncOneParamOptionals(1, x.ДобавитьФайл))
+	x.VMRegisterMethod("ДобавитьКаталог", VMFuncOneParamOptionals(1, x.ДобавитьКаталог))
+	x.VMRegisterMethod("ДобавитьДанные", VMFuncTwoParams(x.ДобавитьДанные))
+	x.VMRegisterMethod("Записать", VMFuncZeroParams(x.Записать))
+	x.VMRegisterMethod("Закрыть", VMFuncZeroParams(x.Записать))
+}
+
+func (x *VMTarGzWriter) Create(w io.Writer, c io.Closer) {
+	x.gw = gzip.NewWriter(w)
+	x.tw = tar.NewWriter(x.gw)
+	x.closer = c
+}
+
+func (x *VMTarGzWriter) addDir(name string, mt time.Time) error {
+	if x.tw == nil {
+		return VMErrorArchiveClosed
+	}
+	return x.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     name,
+		Mode:     0o755,
+		ModTime:  mt,
+		Format:   tar.FormatPAX,
+	})
+}
+
+func (x *VMTarGzWriter) addReader(name string, r io.Reader, size int64, mt time.Time, mode fs.FileMode) error {
+	if x.tw == nil {
+		return VMErrorArchiveClosed
+	}
+	err := x.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     int64(mode.Perm()),
+		ModTime:  mt,
+		Format:   tar.FormatPAX,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(x.tw, r)
+	return err
+}
+
+func (x *VMTarGzWriter) ДобавитьФайл(p VMString, rest VMSlice, rets *VMSlice) error {
+	name, err := optionalArchiveName(p, rest)
+	if err != nil {
+		return err
+	}
+	return addFileToArchive(x, string(p), name)
+}
+
+func (x *VMTarGzWriter) ДобавитьКаталог(p VMString, rest VMSlice, rets *VMSlice) error {
+	name, err := optionalArchiveName(p, rest)
+	if err != nil {
+		return err
+	}
+	return addDirToArchive(x, string(p), name)
+}
+
+func (x *VMTarGzWriter) ДобавитьДанные(name VMString, v VMValue, rets *VMSlice) error {
+	b, err := binaryArg(v)
+	if err != nil {
+		return err
+	}
+	return x.addReader(archiveName(string(name)), bytes.NewReader(b), int64(len(b)), time.Now(), 0o644)
+}
+
+func (x *VMTarGzWriter) Записать(rets *VMSlice) error {
+	return x.Close()
+}
+
+func (x *VMTarGzWriter) Close() error {
+	if x.tw == nil {
+		return nil
+	}
+	err := x.tw.Close()
+	if gerr := x.gw.Close(); err == nil {
+		err = gerr
+	}
+	x.tw, x.gw = nil, nil
+	if x.closer != nil {
+		if cerr := x.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// VMTarGzReader ЧтениеTarGzФайла(ИмяФайлаИлиПотокИлиДвоичныеДанные) - архив читается
+// последовательно, элементы перебираются методом Следующий
+type VMTarGzReader struct {
+	VMMetaObj
+
+	gr     *gzip.Reader
+	tr     *tar.Reader
+	cur    *tar.Header
+	closer io.Closer
+}
+
+func (x *VMTarGzReader) VMTypeString() string {
+	return "ЧтениеTarGzФайла"
+}
+
+func (x *VMTarGzReader) VMRegister() {
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) != 1 {
+			return VMErrorNeedArgs(1)
+		}
+		switch vv := args[0].(type) {
+		case VMString:
+			f, err := os.Open(string(vv))
+			if err != nil {
+				return err
+			}
+			return x.Open(f, f)
+		case VMBinaryData:
+			return x.Open(bytes.NewReader(vv), nil)
+		case io.Reader:
+			return x.Open(vv, nil)
+		}
+		return VMErrorNeedArchiveSource
+	})
+
+	x.VMRegisterMethod("Следующий", VMFuncZeroParams(x.Следующий))
+	x.VMRegisterMethod("ПолучитьДанные", VMFuncZeroParams(x.ПолучитьДанные))
+	x.VMRegisterMethod("Извлечь", VMFuncOneParam(x.Извлечь))
+	x.VMRegisterMethod("Элементы", VMFuncZeroParams(x.Элементы))
+	x.VMRegisterMethod("ИзвлечьВсе", VMFuncOneParamOptionals(1, x.ИзвлечьВсе))
+	x.VMRegisterMethod("Закрыть", VMFuncZeroParams(x.Закрыть))
+}
+
+func (x *VMTarGzReader) Open(r io.Reader, c io.Closer) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		if c != nil {
+			c.Close()
+		}
+		return err
+	}
+	x.gr, x.tr, x.closer = gr, tar.NewReader(gr), c
+	return nil
+}
+
+// Next переходит к следующему элементу, в конце архива возвращает nil
+func (x *VMTarGzReader) Next() (*tar.Header, error) {
+	if x.tr == nil {
+		return nil, VMErrorArchiveClosed
+	}
+	h, err := x.tr.Next()
+	if err == io.EOF {
+		x.cur = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	x.cur = h
+	return h, nil
+}
+
+func (x *VMTarGzReader) extract(h *tar.Header, dir string) error {
+	p, err := extractPath(dir, h.Name)
+	if err != nil {
+		return err
+	}
+	switch h.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(p, 0o755)
+	case tar.TypeReg:
+		return extractToFile(p, x.tr, fs.FileMode(h.Mode).Perm(), h.ModTime)
+	}
+	// ссылки и специальные файлы не извлекаем
+	return nil
+}
+
+func tarEntry(h *tar.Header) VMStringMap {
+	return archiveEntry(h.Name, h.Size, h.Size, h.Typeflag == tar.TypeDir, false, h.ModTime)
+}
+
+// Следующий() возвращает структуру очередного элемента и признак его наличия
+func (x *VMTarGzReader) Следующий(rets *VMSlice) error {
+	h, err := x.Next()
+	if err != nil {
+		return err
+	}
+	if h == nil {
+		rets.Append(VMNil)
+		rets.Append(VMBool(false))
+		return nil
+	}
+	rets.Append(tarEntry(h))
+	rets.Append(VMBool(true))
+	return nil
+}
+
+// ПолучитьДанные() читает содержимое текущего элемента
+func (x *VMTarGzReader) ПолучитьДанные(rets *VMSlice) error {
+	if x.cur == nil {
+		return VMErrorArchiveEntryNotFound
+	}
+	b, err := io.ReadAll(x.tr)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBinaryData(b))
+	return nil
+}
+
+// Извлечь(Каталог) извлекает текущий элемент
+func (x *VMTarGzReader) Извлечь(dir VMString, rets *VMSlice) error {
+	if x.cur == nil {
+		return VMErrorArchiveEntryNotFound
+	}
+	return x.extract(x.cur, string(dir))
+}
+
+// Элементы() перебирает оставшиеся элементы до конца архива без извлечения
+func (x *VMTarGzReader) Элементы(rets *VMSlice) error {
+	rv := VMSlice{}
+	for {
+		h, err := x.Next()
+		if err != nil {
+			return err
+		}
+		if h == nil {
+			break
+		}
+		rv = append(rv, tarEntry(h))
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// ИзвлечьВсе(Каталог, Маска) извлекает оставшиеся элементы, подходящие под маску
+func (x *VMTarGzReader) ИзвлечьВсе(dir VMString, rest VMSlice, rets *VMSlice) error {
+	mask, err := optionalMask(rest)
+	if err != nil {
+		return err
+	}
+	for {
+		h, err := x.Next()
+		if err != nil {
+			return err
+		}
+		if h == nil {
+			return nil
+		}
+		if !matchArchiveName(mask, h.Name) {
+			continue
+		}
+		if err := x.extract(h, string(dir)); err != nil {
+			return err
+		}
+	}
+}
+
+func (x *VMTarGzReader) Закрыть(rets *VMSlice) error {
+	if x.gr != nil {
+		x.gr.Close()
+	}
+	x.gr, x.tr, x.cur = nil, nil, nil
+	if x.closer != nil {
+		c := x.closer
+		x.closer = nil
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+package core
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"hash/crc32"
+	"os"
+	"reflect"
+	"strings"
+
+	iconv "github.com/djimenez/iconv-go"
+	"github.com/shinanca/gonec/names"
+)
+
+// VMBinaryData ДвоичныеДанные - неизменяемая последовательность байт.
+// В строковом представлении и в JSON выводится в Base64.
+type VMBinaryData []byte
+
+var ReflectVMBinaryData = reflect.TypeOf(VMBinaryData(nil))
+
+func (x VMBinaryData) VMTypeString() string { return "ДвоичныеДанные" }
+
+func (x VMBinaryData) Interface() interface{} {
+	return []byte(x)
+}
+
+func (x *VMBinaryData) ParseGoType(v interface{}) {
+	switch vv := v.(type) {
+	case []byte:
+		*x = VMBinaryData(vv)
+	case string:
+		*x = VMBinaryData(vv)
+	default:
+		rv := reflect.Indirect(reflect.ValueOf(v))
+		if rv.Kind() == reflect.Interface {
+			rv = rv.Elem()
+		}
+		*x = VMBinaryData(rv.Bytes()) // выдаст панику, если это не байты
+	}
+}
+
+func (x VMBinaryData) String() string {
+	return base64.StdEncoding.EncodeToString(x)
+}
+
+func (x VMBinaryData) Length() VMInt {
+	return VMInt(len(x))
+}
+
+// IndexVal возвращает байт по индексу, отрицательный индекс отсчитывается от конца, как у Массива
+func (x VMBinaryData) IndexVal(i VMValue) VMValue {
+	if ii, ok := i.(VMInt); ok {
+		n := int(ii)
+		if n < 0 {
+			n += len(x)
+		}
+		if n < 0 || n >= len(x) {
+			panic(VMErrorIndexOutOfBoundary)
+		}
+		return VMInt(x[n])
+	}
+	panic("Индекс должен быть числом")
+}
+
+func (x VMBinaryData) BinaryType() VMBinaryType {
+	return VMBINARYDATA
+}
+
+func (x VMBinaryData) Hash() VMString {
+	h := make([]byte, 8)
+	binary.LittleEndian.PutUint64(h, HashBytes(x))
+	return VMString(hex.EncodeToString(h))
+}
+
+func (x VMBinaryData) EvalBinOp(op VMOperation, y VMOperationer) (VMValue, error) {
+	switch op {
+	case ADD:
+		switch yy := y.(type) {
+		case VMBinaryData:
+			rv := make(VMBinaryData, 0, len(x)+len(yy))
+			rv = append(rv, x...)
+			return append(rv, yy...), nil
+		}
+		return VMNil, VMErrorIncorrectOperation
+	case EQL:
+		switch yy := y.(type) {
+		case VMBinaryData:
+			return VMBool(bytes.Equal(x, yy)), nil
+		}
+		return VMNil, VMErrorIncorrectOperation
+	case NEQ:
+		switch yy := y.(type) {
+		case VMBinaryData:
+			return VMBool(!bytes.Equal(x, yy)), nil
+		}
+		return VMNil, VMErrorIncorrectOperation
+	}
+	return VMNil, VMErrorIncorrectOperation
+}
+
+func (x VMBinaryData) ConvertToType(nt reflect.Type) (VMValue, error) {
+	switch nt {
+	case ReflectVMBinaryData:
+		return x, nil
+	case ReflectVMString:
+		return VMString(x.String()), nil
+	case ReflectVMSlice:
+		rv := make(VMSlice, len(x))
+		for i, b := range x {
+			rv[i] = VMInt(b)
+		}
+		return rv, nil
+	}
+	return VMNil, VMErrorNotConverted
+}
+
+func (x VMBinaryData) MethodMember(name int) (VMFunc, bool) {
+	// только эти методы будут доступны из кода на языке Гонец!
+	switch names.UniqueNames.GetLowerCase(name) {
+	case "размер":
+		return VMFuncZeroParams(x.Размер), true
+	case "вbase64":
+		return VMFuncZeroParams(x.ВBase64), true
+	case "вhex":
+		return VMFuncZeroParams(x.ВHex), true
+	case "встроку":
+		return VMFuncNParamsOptionals(0, 1, x.ВСтроку), true
+	case "хеш":
+		return VMFuncOneParam(x.Хеш), true
+	case "срез":
+		return VMFuncOneParamOptionals(1, x.Срез), true
+	case "сжать":
+		return VMFuncZeroParams(x.Сжать), true
+	case "распаковать":
+		return VMFuncZeroParams(x.Распаковать), true
+	case "зашифровать":
+		return VMFuncZeroParams(x.Зашифровать), true
+	case "расшифровать":
+		return VMFuncZeroParams(x.Расшифровать), true
+	case "записать":
+		return VMFuncOneParam(x.Записать), true
+	}
+	return nil, false
+}
+
+func (x VMBinaryData) Размер(rets *VMSlice) error {
+	rets.Append(x.Length())
+	return nil
+}
+
+func (x VMBinaryData) ВBase64(rets *VMSlice) error {
+	rets.Append(VMString(x.String()))
+	return nil
+}
+
+func (x VMBinaryData) ВHex(rets *VMSlice) error {
+	rets.Append(VMString(hex.EncodeToString(x)))
+	return nil
+}
+
+// ВСтроку(Кодировка) интерпретирует байты как текст, по умолчанию в UTF-8
+func (x VMBinaryData) ВСтроку(args VMSlice, rets *VMSlice) error {
+	if len(args) == 0 {
+		rets.Append(VMString(x))
+		return nil
+	}
+	enc, ok := args[0].(VMString)
+	if !ok {
+		return VMErrorNeedString
+	}
+	if isUTF8(string(enc)) {
+		rets.Append(VMString(x))
+		return nil
+	}
+	s, err := iconv.ConvertString(string(x), string(enc), "utf-8")
+	if err != nil {
+		return VMErrorEncoding
+	}
+	rets.Append(VMString(s))
+	return nil
+}
+
+// Хеш(Алгоритм) возвращает хеш в шестнадцатеричном виде: MD5, SHA1, SHA256, SHA512, CRC32
+func (x VMBinaryData) Хеш(alg VMString, rets *VMSlice) error {
+	var h hash.Hash
+	switch strings.ToUpper(strings.ReplaceAll(string(alg), "-", "")) {
+	case "MD5":
+		h = md5.New()
+	case "SHA1":
+		h = sha1.New()
+	case "SHA256":
+		h = sha256.New()
+	case "SHA512":
+		h = sha512.New()
+	case "CRC32":
+		h = crc32.NewIEEE()
+	default:
+		return VMErrorUnknownHashAlgorithm
+	}
+	h.Write(x)
+	rets.Append(VMString(hex.EncodeToString(h.Sum(nil))))
+	return nil
+}
+
+// Срез(Начало, Конец) возвращает копию байт с позиции Начало до Конец (не включая)
+func (x VMBinaryData) Срез(from VMInt, rest VMSlice, rets *VMSlice) error {
+	to := VMInt(len(x))
+	if len(rest) > 0 {
+		var ok bool
+		if to, ok = rest[0].(VMInt); !ok {
+			return VMErrorNeedInt
+		}
+	}
+	if from < 0 || to > VMInt(len(x)) || from > to {
+		return VMErrorIndexOutOfBoundary
+	}
+	rets.Append(append(VMBinaryData(nil), x[from:to]...))
+	return nil
+}
+
+func (x VMBinaryData) Сжать(rets *VMSlice) error {
+	b, err := GZip(x)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBinaryData(b))
+	return nil
+}
+
+func (x VMBinaryData) Распаковать(rets *VMSlice) error {
+	b, err := UnGZip(x)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBinaryData(b))
+	return nil
+}
+
+func (x VMBinaryData) Зашифровать(rets *VMSlice) error {
+	b, err := EncryptAES128(x)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBinaryData(b))
+	return nil
+}
+
+func (x VMBinaryData) Расшифровать(rets *VMSlice) error {
+	b, err := DecryptAES128(x)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBinaryData(b))
+	return nil
+}
+
+func (x VMBinaryData) Записать(name VMString, rets *VMSlice) error {
+	return os.WriteFile(string(name), x, 0o644)
+}
+
+func (x VMBinaryData) MarshalBinary() ([]byte, error) {
+	return []byte(x), nil
+}
+
+func (x *VMBinaryData) UnmarshalBinary(data []byte) error {
+	*x = append(VMBinaryData(nil), data...)
+	return nil
+}
+
+func (x VMBinaryData) GobEncode() ([]byte, error) {
+	return x.MarshalBinary()
+}
+
+func (x *VMBinaryData) GobDecode(data []byte) error {
+	return x.UnmarshalBinary(data)
+}
+
+func (x VMBinaryData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.String())
+}
+
+func (x *VMBinaryData) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var us string
+	if err := json.Unmarshal(data, &us); err != nil {
+		return err
+	}
+	b, err := base64.StdEncoding.DecodeString(us)
+	if err != nil {
+		return err
+	}
+	*x = VMBinaryData(b)
+	return nil
+}
+
+func (x VMBinaryData) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+func (x *VMBinaryData) UnmarshalText(data []byte) error {
+	b, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return err
+	}
+	*x = VMBinaryData(b)
+	return nil
+}
+
+// binaryArg принимает ДвоичныеДанные или Строку (байты строки в UTF-8)
+func binaryArg(v VMValue) ([]byte, error) {
+	switch vv := v.(type) {
+	case VMBinaryData:
+		return vv, nil
+	case VMString:
+		return []byte(vv), nil
+	}
+	return nil, VMErrorNeedBinaryData
+}
@@ -2,7 +2,11 @@ package core
 
 import (
 	"bytes"
+	"encoding/binary"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,11 +14,17 @@ import (
 	"github.com/shinanca/gonec/names"
 )
 
-// VMBoltDB - файловая база данных BoltDB
+// VMBoltDB - файловая база данных BoltDB.
+// Открытые транзакции отслеживаются: по истечении таймаута они отменяются,
+// а незавершенные на момент уничтожения окружения - отменяются с предупреждением.
 type VMBoltDB struct {
 	sync.Mutex
-	name string
-	db   *bolt.DB
+	name    string
+	db      *bolt.DB
+	timeout time.Duration // таймаут транзакций по умолчанию, 0 - без ограничения
+
+	txmu sync.Mutex
+	txs  map[*VMBoltTransaction]struct{}
 }
 
 var ReflectVMBoltDB = reflect.TypeOf(VMBoltDB{})
@@ -40,7 +50,9 @@ func (x *VMBoltDB) Open(filename string) (err error) {
 	return nil
 }
 
+// Close отменяет незавершенные транзакции, иначе bolt будет ждать их завершения
 func (x *VMBoltDB) Close() {
+	x.rollbackAll()
 	x.Lock()
 	defer x.Unlock()
 	if x.db != nil {
@@ -49,18 +61,102 @@ func (x *VMBoltDB) Close() {
 	}
 }
 
-func (x *VMBoltDB) Begin(writable bool) (tr *VMBoltTransaction, err error) {
+// Begin начинает транзакцию, при timeout > 0 она будет отменена по его истечении
+func (x *VMBoltDB) Begin(writable bool, timeout time.Duration) (tr *VMBoltTransaction, err error) {
 	x.Lock()
-	defer x.Unlock()
+	db := x.db
+	x.Unlock()
+	if db == nil {
+		return nil, VMErrorDBNotOpened
+	}
+	// ожидание записывающей транзакции выполняется без блокировки объекта
 	var tx *bolt.Tx
-	tx, err = x.db.Begin(writable)
+	tx, err = db.Begin(writable)
 	if err != nil {
 		return tr, err
 	}
-	tr = &VMBoltTransaction{tx: tx, writable: writable}
+	tr = &VMBoltTransaction{tx: tx, writable: writable, db: x}
+	x.txmu.Lock()
+	if x.txs == nil {
+		x.txs = make(map[*VMBoltTransaction]struct{})
+	}
+	x.txs[tr] = struct{}{}
+	x.txmu.Unlock()
+	if timeout > 0 {
+		tr.timer = time.AfterFunc(timeout, tr.expire)
+	}
 	return
 }
 
+func (x *VMBoltDB) forget(tr *VMBoltTransaction) {
+	x.txmu.Lock()
+	delete(x.txs, tr)
+	x.txmu.Unlock()
+}
+
+// rollbackAll отменяет все открытые транзакции и возвращает их количество
+func (x *VMBoltDB) rollbackAll() int {
+	x.txmu.Lock()
+	trs := make([]*VMBoltTransaction, 0, len(x.txs))
+	for tr := range x.txs {
+		trs = append(trs, tr)
+	}
+	x.txmu.Unlock()
+	for _, tr := range trs {
+		tr.Rollback()
+	}
+	return len(trs)
+}
+
+func (x *VMBoltDB) BindEnv(env *Env) {
+	env.AddResource(x)
+}
+
+// ReleaseLeaked отменяет транзакции, которые скрипт не зафиксировал и не отменил
+func (x *VMBoltDB) ReleaseLeaked() error {
+	if n := x.rollbackAll(); n > 0 {
+		return fmt.Errorf("Файловая база данных %s: отменено незавершенных транзакций: %d", x.name, n)
+	}
+	return nil
+}
+
+// inTransaction выполняет функцию, передавая ей транзакцию, и фиксирует изменения при успешном завершении.
+// При ошибке, а также для транзакции на чтение, транзакция отменяется.
+func (x *VMBoltDB) inTransaction(writable bool, f VMFunc, rest VMSlice, rets *VMSlice) error {
+	timeout := x.timeout
+	if len(rest) > 0 {
+		d, ok := rest[0].(VMDurationer)
+		if !ok {
+			return VMErrorNeedDuration
+		}
+		timeout = time.Duration(d.Duration())
+	}
+	tr, err := x.Begin(writable, timeout)
+	if err != nil {
+		return err
+	}
+	// отмена и при панике внутри функции
+	defer func() {
+		if tr.isOpen() {
+			tr.Rollback()
+		}
+	}()
+	if err = f(VMSlice{tr}, rets); err != nil {
+		return err
+	}
+	if !writable {
+		return nil
+	}
+	if !tr.isOpen() {
+		if tr.isExpired() {
+			return VMErrorTransactionTimeout
+		}
+		// транзакция завершена внутри функции явно
+		return nil
+	}
+	return tr.Commit()
+}
+
 func (x *VMBoltDB) MethodMember(name int) (VMFunc, bool) {
 	// только эти методы будут доступны из кода на языке Гонец!
 	switch names.UniqueNames.GetLowerCase(name) {
@@ -69,7 +165,27 @@ func (x *VMBoltDB) MethodMember(name int) (VMFunc, bool) {
 	case "закрыть":
 		return VMFuncZeroParams(x.Закрыть), true
 	case "начатьтранзакцию":
-		return VMFuncOneParam(x.НачатьТранзакцию), true
+		return VMFuncOneParamOptionals(1, x.НачатьТранзакцию), true
+	case "изменить":
+		return VMFuncOneParamOptionals(1, x.Изменить), true
+	case "прочитать":
+		return VMFuncOneParamOptionals(1, x.Прочитать), true
+	case "установитьтаймауттранзакций":
+		return VMFuncOneParam(x.УстановитьТаймаутТранзакций), true
+	case "резервнаякопия":
+		return VMFuncOneParam(x.РезервнаяКопия), true
+	case "восстановить":
+		return VMFuncOneParam(x.Восстановить), true
+	case "сжать":
+		return VMFuncOneParam(x.Сжать), true
+	case "экспортировать":
+		return VMFuncOneParam(x.Экспортировать), true
+	case "импортировать":
+		return VMFuncOneParam(x.Импортировать), true
+	case "проверить":
+		return VMFuncZeroParams(x.Проверить), true
+	case "статистика":
+		return VMFuncZeroParams(x.Статистика), true
 	}
 	return nil, false
 }
@@ -78,8 +194,17 @@ func (x *VMBoltDB) Открыть(s VMString, rets *VMSlice) error {
 	return x.Open(string(s))
 }
 
-func (x *VMBoltDB) НачатьТранзакцию(writable VMBool, rets *VMSlice) error {
-	tr, err := x.Begin(writable.Bool())
+// НачатьТранзакцию(НаЗапись, Таймаут) - транзакцию нужно явно зафиксировать или отменить
+func (x *VMBoltDB) НачатьТранзакцию(writable VMBool, rest VMSlice, rets *VMSlice) error {
+	timeout := x.timeout
+	if len(rest) > 0 {
+		d, ok := rest[0].(VMDurationer)
+		if !ok {
+			return VMErrorNeedDuration
+		}
+		timeout = time.Duration(d.Duration())
+	}
+	tr, err := x.Begin(writable.Bool(), timeout)
 	if err != nil {
 		return err
 	}
@@ -92,10 +217,34 @@ func (x *VMBoltDB) Закрыть(rets *VMSlice) error {
 	return nil
 }
 
+// Изменить(Функция, Таймаут) вызывает функцию с транзакцией на запись в качестве параметра,
+// фиксирует транзакцию при успешном завершении и отменяет при исключении.
+// Возвращает результат функции.
+func (x *VMBoltDB) Изменить(f VMFunc, rest VMSlice, rets *VMSlice) error {
+	return x.inTransaction(true, f, rest, rets)
+}
+
+// Прочитать(Функция, Таймаут) вызывает функцию с транзакцией на чтение, которая затем всегда отменяется
+func (x *VMBoltDB) Прочитать(f VMFunc, rest VMSlice, rets *VMSlice) error {
+	return x.inTransaction(false, f, rest, rets)
+}
+
+// УстановитьТаймаутТранзакций(Длительность) задает таймаут для последующих транзакций, 0 - без ограничения
+func (x *VMBoltDB) УстановитьТаймаутТранзакций(d VMDurationer, rets *VMSlice) error {
+	x.Lock()
+	x.timeout = time.Duration(d.Duration())
+	x.Unlock()
+	return nil
+}
+
 // VMBoltTransaction реализует функционал Transaction для BoltDB
 type VMBoltTransaction struct {
+	mu       sync.Mutex
 	tx       *bolt.Tx
 	writable bool
+	db       *VMBoltDB
+	timer    *time.Timer
+	expired  bool
 }
 
 func (x *VMBoltTransaction) VMTypeString() string {
@@ -111,30 +260,76 @@ func (x *VMBoltTransaction) String() string {
 }
 
 func (x *VMBoltTransaction) Commit() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
 	if x.tx == nil {
-		return VMErrorTransactionNotOpened
+		return x.closedError()
 	}
 	err := x.tx.Commit()
-	x.tx = nil
+	x.finish()
 	return err
 }
 
 func (x *VMBoltTransaction) Rollback() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
 	if x.tx == nil {
-		return VMErrorTransactionNotOpened
+		return x.closedError()
 	}
 	x.tx.Rollback()
-	x.tx = nil
+	x.finish()
 	return nil
 }
 
+// expire отменяет транзакцию по таймауту, чтобы она не блокировала файл базы
+func (x *VMBoltTransaction) expire() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.tx == nil {
+		return
+	}
+	x.tx.Rollback()
+	x.expired = true
+	x.finish()
+}
+
+// finish вызывается под блокировкой после завершения транзакции bolt
+func (x *VMBoltTransaction) finish() {
+	x.tx = nil
+	if x.timer != nil {
+		x.timer.Stop()
+	}
+	if x.db != nil {
+		x.db.forget(x)
+	}
+}
+
+func (x *VMBoltTransaction) closedError() error {
+	if x.expired {
+		return VMErrorTransactionTimeout
+	}
+	return VMErrorTransactionNotOpened
+}
+
+func (x *VMBoltTransaction) isOpen() bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.tx != nil
+}
+
+func (x *VMBoltTransaction) isExpired() bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.expired
+}
+
 func (x *VMBoltTransaction) CreateTableIfNotExists(name string) (*VMBoltTable, error) {
 	if x.tx == nil {
-		return nil, VMErrorTransactionNotOpened
+		return nil, x.closedError()
 	}
 	if x.writable {
 		b, err := x.tx.CreateBucketIfNotExists([]byte(name))
-		t := &VMBoltTable{name: name, b: b}
+		t := &VMBoltTable{name: name, b: b, writable: true, tr: x}
 		return t, err
 	} else {
 		return x.OpenTable(name)
@@ -143,26 +338,26 @@ func (x *VMBoltTransaction) CreateTableIfNotExists(name string) (*VMBoltTable, e
 
 func (x *VMBoltTransaction) OpenTable(name string) (*VMBoltTable, error) {
 	if x.tx == nil {
-		return nil, VMErrorTransactionNotOpened
+		return nil, x.closedError()
 	}
 	b := x.tx.Bucket([]byte(name))
 	if b == nil {
 		return nil, VMErrorTableNotExists
 	}
-	t := &VMBoltTable{name: name, b: b}
+	t := &VMBoltTable{name: name, b: b, tr: x}
 	return t, nil
 }
 
 func (x *VMBoltTransaction) DeleteTable(name string) error {
 	if x.tx == nil {
-		return VMErrorTransactionNotOpened
+		return x.closedError()
 	}
 	return x.tx.DeleteBucket([]byte(name))
 }
 
 func (x *VMBoltTransaction) BackupDBToFile(name string) error {
 	if x.tx == nil {
-		return VMErrorTransactionNotOpened
+		return x.closedError()
 	}
 	return x.tx.CopyFile(name, 0o644)
 }
@@ -209,10 +404,12 @@ func (x *VMBoltTransaction) ПолныйБэкап(table VMString, rets *VMSlice
 	return x.BackupDBToFile(string(table))
 }
 
-// VMBoltTable реализует функционал Bucket для BoltDB
+// VMBoltTable реализует функционал Bucket для BoltDB, может содержать вложенные таблицы
 type VMBoltTable struct {
-	name string
-	b    *bolt.Bucket
+	name     string
+	b        *bolt.Bucket
+	writable bool
+	tr       *VMBoltTransaction
 }
 
 func (x *VMBoltTable) VMTypeString() string {
@@ -228,12 +425,24 @@ func (x *VMBoltTable) String() string {
 }
 
 func (x *VMBoltTable) Set(k string, v VMBinaryTyper) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
 	i := []byte{byte(v.BinaryType())}
 	ii, err := v.MarshalBinary()
 	if err != nil {
 		return err
 	}
-	return x.b.Put([]byte(k), append(i, ii...))
+	var old VMValue
+	if x.indexes() != nil {
+		if old, _, err = x.Get(k); err != nil {
+			return err
+		}
+	}
+	if err = x.b.Put([]byte(k), append(i, ii...)); err != nil {
+		return err
+	}
+	return x.updateIndexes([]byte(k), old, v)
 }
 
 func parseBoltValue(sl []byte) (VMValue, error) {
@@ -253,8 +462,11 @@ func parseBoltValue(sl []byte) (VMValue, error) {
 }
 
 func (x *VMBoltTable) Get(k string) (VMValue, bool, error) {
+	if err := x.checkTx(); err != nil {
+		return VMNil, false, err
+	}
 	sl := x.b.Get([]byte(k))
-	if sl == nil {
+	if sl == nil || k == boltIndexBucket {
 		return VMNil, false, nil
 	}
 	vv, err := parseBoltValue(sl)
@@ -262,18 +474,40 @@ func (x *VMBoltTable) Get(k string) (VMValue, bool, error) {
 }
 
 func (x *VMBoltTable) Delete(k string) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	if x.indexes() != nil {
+		old, _, err := x.Get(k)
+		if err != nil {
+			return err
+		}
+		if err = x.updateIndexes([]byte(k), old, nil); err != nil {
+			return err
+		}
+	}
 	return x.b.Delete([]byte(k))
 }
 
 func (x *VMBoltTable) NextId() (VMInt, error) {
+	if err := x.checkTx(); err != nil {
+		return 0, err
+	}
 	id, err := x.b.NextSequence()
 	return VMInt(id), err
 }
 
 func (x *VMBoltTable) GetPrefix(pref string) (VMStringMap, error) {
+	if err := x.checkTx(); err != nil {
+		return nil, err
+	}
 	c := x.b.Cursor()
 	vsm := make(VMStringMap)
 	for k, v := c.Seek([]byte(pref)); k != nil && bytes.HasPrefix(k, []byte(pref)); k, v = c.Next() {
+		if v == nil {
+			// вложенная таблица
+			continue
+		}
 		vx, err := parseBoltValue(v)
 		if err != nil {
 			return vsm, err
@@ -284,9 +518,16 @@ func (x *VMBoltTable) GetPrefix(pref string) (VMStringMap, error) {
 }
 
 func (x *VMBoltTable) GetRange(kmin, kmax string) (VMStringMap, error) {
+	if err := x.checkTx(); err != nil {
+		return nil, err
+	}
 	c := x.b.Cursor()
 	vsm := make(VMStringMap)
 	for k, v := c.Seek([]byte(kmin)); k != nil && bytes.Compare(k, []byte(kmax)) <= 0; k, v = c.Next() {
+		if v == nil {
+			// вложенная таблица
+			continue
+		}
 		vx, err := parseBoltValue(v)
 		if err != nil {
 			return vsm, err
@@ -297,9 +538,16 @@ func (x *VMBoltTable) GetRange(kmin, kmax string) (VMStringMap, error) {
 }
 
 func (x *VMBoltTable) GetAll() (VMStringMap, error) {
+	if err := x.checkTx(); err != nil {
+		return nil, err
+	}
 	c := x.b.Cursor()
 	vsm := make(VMStringMap)
 	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if v == nil {
+			// вложенная таблица
+			continue
+		}
 		vx, err := parseBoltValue(v)
 		if err != nil {
 			return vsm, err
@@ -320,14 +568,7 @@ func (x *VMBoltTable) SetByMap(m VMStringMap) error {
 	}
 
 	for ks, vs := range mm {
-
-		i := []byte{byte(vs.BinaryType())}
-		ii, err := vs.MarshalBinary()
-		if err != nil {
-			return err
-		}
-		err = x.b.Put([]byte(ks), append(i, ii...))
-		if err != nil {
+		if err := x.Set(ks, vs); err != nil {
 			return err
 		}
 	}
@@ -354,6 +595,30 @@ func (x *VMBoltTable) MethodMember(name int) (VMFunc, bool) {
 		return VMFuncZeroParams(x.ПолучитьВсе), true
 	case "установитьструктуру":
 		return VMFuncOneParam(x.УстановитьСтруктуру), true
+	case "курсор":
+		return VMFuncZeroParams(x.Курсор), true
+	case "диапазон":
+		return VMFuncNParamsOptionals(2, 1, x.Диапазон), true
+	case "диапазонпрефикса":
+		return VMFuncNParamsOptionals(1, 1, x.ДиапазонПрефикса), true
+	case "таблица":
+		return VMFuncOneParam(x.Таблица), true
+	case "удалитьтаблицу":
+		return VMFuncOneParam(x.УдалитьТаблицу), true
+	case "таблицы":
+		return VMFuncZeroParams(x.Таблицы), true
+	case "создатьиндекс":
+		return VMFuncOneParam(x.СоздатьИндекс), true
+	case "удалитьиндекс":
+		return VMFuncOneParam(x.УдалитьИндекс), true
+	case "индексы":
+		return VMFuncZeroParams(x.Индексы), true
+	case "найтипоиндексу":
+		return VMFuncNParamsOptionals(2, 1, x.НайтиПоИндексу), true
+	case "диапазонпоиндексу":
+		return VMFuncNParamsOptionals(3, 1, x.ДиапазонПоИндексу), true
+	case "запрос":
+		return VMFuncZeroParams(x.Запрос), true
 	}
 	return nil, false
 }
@@ -412,3 +677,796 @@ func (x *VMBoltTable) ПолучитьВсе(rets *VMSlice) error {
 func (x *VMBoltTable) УстановитьСтруктуру(m VMStringMap, rets *VMSlice) error {
 	return x.SetByMap(m)
 }
+
+// Iterator позволяет перебирать все записи таблицы в цикле Для каждого в порядке ключей
+func (x *VMBoltTable) Iterator() (VMIterator, error) {
+	return (&VMBoltRange{t: x}).Iterator()
+}
+
+func (x *VMBoltTable) Курсор(rets *VMSlice) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	rets.Append(&VMBoltCursor{t: x, c: x.b.Cursor()})
+	return nil
+}
+
+// Диапазон(Начало, Конец, Параметры) - записи с ключами от Начало до Конец включительно,
+// пустая строка означает отсутствие границы
+func (x *VMBoltTable) Диапазон(args VMSlice, rets *VMSlice) error {
+	from, ok := args[0].(VMString)
+	if !ok {
+		return VMErrorNeedString
+	}
+	to, ok := args[1].(VMString)
+	if !ok {
+		return VMErrorNeedString
+	}
+	r := &VMBoltRange{t: x, from: []byte(from), to: []byte(to)}
+	if err := r.parseParams(args[2:]); err != nil {
+		return err
+	}
+	rets.Append(r)
+	return nil
+}
+
+// ДиапазонПрефикса(Префикс, Параметры) - записи с ключами, начинающимися с префикса
+func (x *VMBoltTable) ДиапазонПрефикса(args VMSlice, rets *VMSlice) error {
+	pref, ok := args[0].(VMString)
+	if !ok {
+		return VMErrorNeedString
+	}
+	r := &VMBoltRange{t: x, prefix: []byte(pref)}
+	if err := r.parseParams(args[1:]); err != nil {
+		return err
+	}
+	rets.Append(r)
+	return nil
+}
+
+// boltIndexBucket служебная вложенная таблица с индексами, в ней для каждого индексируемого поля
+// хранится таблица с ключами вида <значение поля><ключ записи>
+const boltIndexBucket = "\x00индексы"
+
+func (x *VMBoltTable) sub(name string, b *bolt.Bucket) *VMBoltTable {
+	return &VMBoltTable{name: x.name + "/" + name, b: b, writable: x.writable, tr: x.tr}
+}
+
+// Таблица(Имя) возвращает вложенную таблицу, в транзакции на запись создает ее при отсутствии
+func (x *VMBoltTable) Таблица(name VMString, rets *VMSlice) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	var b *bolt.Bucket
+	if x.writable {
+		var err error
+		if b, err = x.b.CreateBucketIfNotExists([]byte(name)); err != nil {
+			return err
+		}
+	} else if b = x.b.Bucket([]byte(name)); b == nil {
+		return VMErrorTableNotExists
+	}
+	rets.Append(x.sub(string(name), b))
+	return nil
+}
+
+func (x *VMBoltTable) УдалитьТаблицу(name VMString, rets *VMSlice) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	return x.b.DeleteBucket([]byte(name))
+}
+
+// Таблицы() возвращает массив имен вложенных таблиц
+func (x *VMBoltTable) Таблицы(rets *VMSlice) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	rv := VMSlice{}
+	err := x.b.ForEach(func(k, v []byte) error {
+		if v == nil && string(k) != boltIndexBucket {
+			rv = append(rv, VMString(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	rets.Append(rv)
+	return nil
+}
+
+func (x *VMBoltTable) indexes() *bolt.Bucket {
+	return x.b.Bucket([]byte(boltIndexBucket))
+}
+
+func (x *VMBoltTable) index(field string) (*bolt.Bucket, error) {
+	ib := x.indexes()
+	if ib == nil {
+		return nil, VMErrorIndexNotExists
+	}
+	fb := ib.Bucket([]byte(field))
+	if fb == nil {
+		return nil, VMErrorIndexNotExists
+	}
+	return fb, nil
+}
+
+// boltIndexValue кодирует значение поля так, чтобы побайтовый порядок совпадал с порядком значений.
+// Индексируются строки, числа, даты и булевы значения.
+func boltIndexValue(v VMValue) ([]byte, bool) {
+	switch vv := v.(type) {
+	case VMString:
+		// нулевой байт экранируется, чтобы строка-префикс не совпадала с началом более длинной строки
+		rv := make([]byte, 0, len(vv)+3)
+		rv = append(rv, 's')
+		for i := 0; i < len(vv); i++ {
+			if vv[i] == 0 {
+				rv = append(rv, 0, 0xff)
+			} else {
+				rv = append(rv, vv[i])
+			}
+		}
+		return append(rv, 0, 1), true
+	case VMInt:
+		return boltIndexNumber(NewVMDecNumFromInt64(int64(vv)))
+	case VMDecNum:
+		return boltIndexNumber(vv)
+	case VMTime:
+		rv := make([]byte, 9)
+		rv[0] = 't'
+		binary.BigEndian.PutUint64(rv[1:], uint64(time.Time(vv).UnixNano())^(1<<63))
+		return rv, true
+	case VMBool:
+		if vv {
+			return []byte{'b', 1}, true
+		}
+		return []byte{'b', 0}, true
+	}
+	return nil, false
+}
+
+// boltIndexNumber кодирует число без потери точности: знак, порядок и значащие цифры.
+// Целые и дробные числа с одинаковым значением дают одинаковый ключ.
+// У отрицательных чисел байты инвертируются, чтобы больший модуль шел раньше.
+func boltIndexNumber(d VMDecNum) ([]byte, bool) {
+	if !d.num.IsFinite() {
+		return nil, false
+	}
+	if d.num.IsZero() {
+		return []byte{'n', 2}, true
+	}
+	// в научной записи цифры мантиссы и порядок, например -1.2300E+5
+	s := d.num.QuadToString()
+	neg := s[0] == '-'
+	if neg {
+		s = s[1:]
+	}
+	mant, exp := s, 0
+	if i := strings.IndexAny(s, "Ee"); i >= 0 {
+		mant = s[:i]
+		var err error
+		if exp, err = strconv.Atoi(s[i+1:]); err != nil {
+			return nil, false
+		}
+	}
+	// значение = 0.цифры * 10^exp
+	if i := strings.IndexByte(mant, '.'); i >= 0 {
+		exp += i
+		mant = mant[:i] + mant[i+1:]
+	} else {
+		exp += len(mant)
+	}
+	n := len(mant)
+	mant = strings.TrimLeft(mant, "0")
+	exp -= n - len(mant)
+	mant = strings.TrimRight(mant, "0")
+
+	rv := make([]byte, 6, len(mant)+7)
+	rv[0], rv[1] = 'n', 3
+	binary.BigEndian.PutUint32(rv[2:], uint32(int32(exp))^(1<<31))
+	rv = append(rv, mant...)
+	rv = append(rv, 0) // короткая мантисса меньше длинной с тем же началом
+	if neg {
+		rv[1] = 1
+		for i := 2; i < len(rv); i++ {
+			rv[i] = ^rv[i]
+		}
+	}
+	return rv, true
+}
+
+func indexFieldValue(doc VMValue, field string) ([]byte, bool) {
+	m, ok := doc.(VMStringMap)
+	if !ok {
+		return nil, false
+	}
+	fv, ok := structParam(m, field)
+	if !ok {
+		return nil, false
+	}
+	return boltIndexValue(fv)
+}
+
+// updateIndexes удаляет из индексов старое значение записи и добавляет новое, nil означает отсутствие значения
+func (x *VMBoltTable) updateIndexes(k []byte, old, new VMValue) error {
+	ib := x.indexes()
+	if ib == nil {
+		return nil
+	}
+	// вложенные таблицы нельзя изменять внутри ForEach
+	var fields []string
+	ib.ForEach(func(f, _ []byte) error {
+		fields = append(fields, string(f))
+		return nil
+	})
+	for _, f := range fields {
+		fb := ib.Bucket([]byte(f))
+		if e, ok := indexFieldValue(old, f); ok {
+			if err := fb.Delete(append(e, k...)); err != nil {
+				return err
+			}
+		}
+		if e, ok := indexFieldValue(new, f); ok {
+			if err := fb.Put(append(e, k...), k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// СоздатьИндекс(Поле) создает индекс по полю записей-структур и заполняет его по имеющимся записям.
+// Далее индекс поддерживается автоматически при установке и удалении значений.
+func (x *VMBoltTable) СоздатьИндекс(field VMString, rets *VMSlice) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	ib, err := x.b.CreateBucketIfNotExists([]byte(boltIndexBucket))
+	if err != nil {
+		return err
+	}
+	if ib.Bucket([]byte(field)) != nil {
+		return nil
+	}
+	fb, err := ib.CreateBucket([]byte(field))
+	if err != nil {
+		return err
+	}
+	// сначала собираем ключи индекса, чтобы не изменять таблицы во время обхода курсором
+	var keys [][]byte
+	c := x.b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if v == nil {
+			continue
+		}
+		vv, err := parseBoltValue(v)
+		if err != nil {
+			return err
+		}
+		if e, ok := indexFieldValue(vv, string(field)); ok {
+			keys = append(keys, append(e, k...), k)
+		}
+	}
+	for i := 0; i < len(keys); i += 2 {
+		if err := fb.Put(keys[i], keys[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *VMBoltTable) УдалитьИндекс(field VMString, rets *VMSlice) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	ib := x.indexes()
+	if ib == nil || ib.Bucket([]byte(field)) == nil {
+		return VMErrorIndexNotExists
+	}
+	if err := ib.DeleteBucket([]byte(field)); err != nil {
+		return err
+	}
+	if k, _ := ib.Cursor().First(); k == nil {
+		return x.b.DeleteBucket([]byte(boltIndexBucket))
+	}
+	return nil
+}
+
+// Индексы() возвращает массив имен индексированных полей
+func (x *VMBoltTable) Индексы(rets *VMSlice) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	rv := VMSlice{}
+	if ib := x.indexes(); ib != nil {
+		ib.ForEach(func(k, _ []byte) error {
+			rv = append(rv, VMString(k))
+			return nil
+		})
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// НайтиПоИндексу(Поле, Значение, Параметры) - записи, у которых поле равно значению, в порядке ключей
+func (x *VMBoltTable) НайтиПоИндексу(args VMSlice, rets *VMSlice) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	field, ok := args[0].(VMString)
+	if !ok {
+		return VMErrorNeedString
+	}
+	fb, err := x.index(string(field))
+	if err != nil {
+		return err
+	}
+	e, ok := boltIndexValue(args[1])
+	if !ok {
+		return VMErrorIndexValue
+	}
+	r := &VMBoltRange{t: x, idx: fb, prefix: e}
+	if err := r.parseParams(args[2:]); err != nil {
+		return err
+	}
+	if r.after != nil {
+		// ключи индекса имеют вид <значение><ключ записи>
+		r.after = append(append([]byte(nil), e...), r.after...)
+	}
+	rets.Append(r)
+	return nil
+}
+
+// ДиапазонПоИндексу(Поле, Начало, Конец, Параметры) - записи со значением поля от Начало до Конец включительно
+// в порядке значений поля, Неопределено означает отсутствие границы.
+// ПослеКлюча должен указывать на имеющуюся запись, выборка продолжается с места этой записи в индексе
+func (x *VMBoltTable) ДиапазонПоИндексу(args VMSlice, rets *VMSlice) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	field, ok := args[0].(VMString)
+	if !ok {
+		return VMErrorNeedString
+	}
+	fb, err := x.index(string(field))
+	if err != nil {
+		return err
+	}
+	r := &VMBoltRange{t: x, idx: fb, toExcl: true}
+	var from, to []byte
+	if args[1] != VMNil {
+		if from, ok = boltIndexValue(args[1]); !ok {
+			return VMErrorIndexValue
+		}
+	}
+	if args[2] != VMNil {
+		if to, ok = boltIndexValue(args[2]); !ok {
+			return VMErrorIndexValue
+		}
+	}
+	// без одной из границ диапазон ограничивается значениями того же типа
+	switch {
+	case from != nil && to != nil:
+		r.from, r.to = from, prefixEnd(to)
+	case from != nil:
+		r.from, r.to = from, prefixEnd(from[:1])
+	case to != nil:
+		r.from, r.to = to[:1], prefixEnd(to)
+	}
+	if err := r.parseParams(args[3:]); err != nil {
+		return err
+	}
+	if r.after != nil {
+		// ключи индекса имеют вид <значение><ключ записи>, значение берется из самой записи
+		v := x.b.Get(r.after)
+		if v == nil {
+			return VMErrorIndexAfterKey
+		}
+		vv, err := parseBoltValue(v)
+		if err != nil {
+			return err
+		}
+		e, ok := indexFieldValue(vv, string(field))
+		if !ok {
+			return VMErrorIndexAfterKey
+		}
+		r.after = append(e, r.after...)
+	}
+	rets.Append(r)
+	return nil
+}
+
+// checkTx проверяет, что транзакция таблицы еще не завершена, иначе bolt паникует
+func (x *VMBoltTable) checkTx() error {
+	if x.b.Tx().DB() == nil {
+		if x.tr != nil && x.tr.isExpired() {
+			return VMErrorTransactionTimeout
+		}
+		return VMErrorTransactionNotOpened
+	}
+	return nil
+}
+
+// VMBoltCursor курсор по таблице, ключи перебираются в порядке возрастания байт
+type VMBoltCursor struct {
+	t   *VMBoltTable
+	c   *bolt.Cursor
+	key []byte // текущий ключ, нужен для обновления индексов при удалении
+
+	deleted bool // после удаления bolt при переходе к следующему ключу пропускает один
+}
+
+func (x *VMBoltCursor) VMTypeString() string {
+	return "КурсорФайловойБазыДанных"
+}
+
+func (x *VMBoltCursor) Interface() interface{} {
+	return x
+}
+
+func (x *VMBoltCursor) String() string {
+	return "Курсор таблицы '" + x.t.name + "' файловой базы данных BoltDB"
+}
+
+func (x *VMBoltCursor) MethodMember(name int) (VMFunc, bool) {
+	// только эти методы будут доступны из кода на языке Гонец!
+	switch names.UniqueNames.GetLowerCase(name) {
+	case "первый":
+		return VMFuncZeroParams(x.Первый), true
+	case "последний":
+		return VMFuncZeroParams(x.Последний), true
+	case "следующий":
+		return VMFuncZeroParams(x.Следующий), true
+	case "предыдущий":
+		return VMFuncZeroParams(x.Предыдущий), true
+	case "найти":
+		return VMFuncOneParam(x.Найти), true
+	case "удалить":
+		return VMFuncZeroParams(x.Удалить), true
+	}
+	return nil, false
+}
+
+// move выполняет перемещение курсора и возвращает ключ, значение и признак наличия записи
+// вложенная таблица возвращается как значение типа ТаблицаФайловойБазыДанных
+func (x *VMBoltCursor) move(f func() ([]byte, []byte), back bool, rets *VMSlice) error {
+	if err := x.t.checkTx(); err != nil {
+		return err
+	}
+	k, v := f()
+	x.deleted = false
+	if k != nil && string(k) == boltIndexBucket {
+		// служебную таблицу индексов пропускаем
+		if back {
+			k, v = x.c.Prev()
+		} else {
+			k, v = x.c.Next()
+		}
+	}
+	x.key = k
+	if k == nil {
+		rets.Append(VMNil)
+		rets.Append(VMNil)
+		rets.Append(VMBool(false))
+		return nil
+	}
+	var vv VMValue
+	if v == nil {
+		vv = x.t.sub(string(k), x.t.b.Bucket(k))
+	} else {
+		var err error
+		if vv, err = parseBoltValue(v); err != nil {
+			return err
+		}
+	}
+	rets.Append(VMString(k))
+	rets.Append(vv)
+	rets.Append(VMBool(true))
+	return nil
+}
+
+func (x *VMBoltCursor) Первый(rets *VMSlice) error {
+	return x.move(x.c.First, false, rets)
+}
+
+func (x *VMBoltCursor) Последний(rets *VMSlice) error {
+	return x.move(x.c.Last, true, rets)
+}
+
+func (x *VMBoltCursor) Следующий(rets *VMSlice) error {
+	if x.deleted {
+		// удаленного ключа уже нет, поиск по нему установит курсор на следующий
+		key := x.key
+		return x.move(func() ([]byte, []byte) { return x.c.Seek(key) }, false, rets)
+	}
+	return x.move(x.c.Next, false, rets)
+}
+
+func (x *VMBoltCursor) Предыдущий(rets *VMSlice) error {
+	if x.deleted {
+		key := x.key
+		return x.move(func() ([]byte, []byte) {
+			if k, _ := x.c.Seek(key); k == nil {
+				return x.c.Last()
+			}
+			return x.c.Prev()
+		}, true, rets)
+	}
+	return x.move(x.c.Prev, true, rets)
+}
+
+// Найти(Ключ) устанавливает курсор на указанный ключ, а если его нет - на следующий за ним
+func (x *VMBoltCursor) Найти(key VMString, rets *VMSlice) error {
+	return x.move(func() ([]byte, []byte) { return x.c.Seek([]byte(key)) }, false, rets)
+}
+
+// Удалить() удаляет запись, на которой стоит курсор
+func (x *VMBoltCursor) Удалить(rets *VMSlice) error {
+	if err := x.t.checkTx(); err != nil {
+		return err
+	}
+	if x.key != nil && x.t.indexes() != nil {
+		old, _, err := x.t.Get(string(x.key))
+		if err != nil {
+			return err
+		}
+		if err = x.t.updateIndexes(x.key, old, nil); err != nil {
+			return err
+		}
+	}
+	if err := x.c.Delete(); err != nil {
+		return err
+	}
+	x.deleted = x.key != nil
+	return nil
+}
+
+// VMBoltRange диапазон записей таблицы, перебирается в цикле Для каждого без загрузки в память,
+// элементами являются структуры с полями Ключ и Значение.
+// Параметры: Обратный - перебор от большего ключа к меньшему, Ограничение - максимум записей,
+// Смещение - сколько записей пропустить, ПослеКлюча - начать со следующего за ним ключа (для постраничной выборки)
+type VMBoltRange struct {
+	t        *VMBoltTable
+	idx      *bolt.Bucket // при выборке по индексу перебираются его ключи, а значения берутся из таблицы
+	from, to []byte
+	toExcl   bool // верхняя граница не включается
+	prefix   []byte
+	reverse  bool
+	limit    int
+	offset   int
+	after    []byte
+}
+
+func (x *VMBoltRange) VMTypeString() string {
+	return "ДиапазонФайловойБазыДанных"
+}
+
+func (x *VMBoltRange) Interface() interface{} {
+	return x
+}
+
+func (x *VMBoltRange) String() string {
+	return "Диапазон таблицы '" + x.t.name + "' файловой базы данных BoltDB"
+}
+
+func (x *VMBoltRange) parseParams(args VMSlice) error {
+	if len(args) == 0 {
+		return nil
+	}
+	params, ok := args[0].(VMStringMap)
+	if !ok {
+		return VMErrorNeedMap
+	}
+	if v, ok := structParam(params, "Обратный"); ok {
+		b, ok := v.(VMBool)
+		if !ok {
+			return VMErrorNeedBool
+		}
+		x.reverse = bool(b)
+	}
+	if v, ok := structParam(params, "Ограничение"); ok {
+		n, ok := v.(VMInt)
+		if !ok {
+			return VMErrorNeedInt
+		}
+		x.limit = int(n)
+	}
+	if v, ok := structParam(params, "Смещение"); ok {
+		n, ok := v.(VMInt)
+		if !ok {
+			return VMErrorNeedInt
+		}
+		x.offset = int(n)
+	}
+	if v, ok := structParam(params, "ПослеКлюча"); ok {
+		s, ok := v.(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		x.after = []byte(s)
+	}
+	return nil
+}
+
+func (x *VMBoltRange) MethodMember(name int) (VMFunc, bool) {
+	// только эти методы будут доступны из кода на языке Гонец!
+	switch names.UniqueNames.GetLowerCase(name) {
+	case "вмассив":
+		return VMFuncZeroParams(x.ВМассив), true
+	}
+	return nil, false
+}
+
+// ВМассив() возвращает записи диапазона в виде массива структур с сохранением порядка ключей
+func (x *VMBoltRange) ВМассив(rets *VMSlice) error {
+	it, err := x.Iterator()
+	if err != nil {
+		return err
+	}
+	rv := VMSlice{}
+	for {
+		v, ok, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		rv = append(rv, v)
+	}
+	rets.Append(rv)
+	return nil
+}
+
+func (x *VMBoltRange) Iterator() (VMIterator, error) {
+	if err := x.t.checkTx(); err != nil {
+		return nil, err
+	}
+	b := x.t.b
+	if x.idx != nil {
+		b = x.idx
+	}
+	return &boltRangeIterator{r: x, c: b.Cursor()}, nil
+}
+
+// prefixEnd возвращает наименьший ключ, больший всех ключей с префиксом, или nil
+func prefixEnd(pref []byte) []byte {
+	end := append([]byte(nil), pref...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+type boltRangeIterator struct {
+	r       *VMBoltRange
+	c       *bolt.Cursor
+	started bool
+	count   int
+}
+
+func (x *boltRangeIterator) VMTypeString() string {
+	return "ИтераторФайловойБазыДанных"
+}
+
+// seekLE устанавливает курсор на последний ключ, не больший key (меньший, если strict)
+func (x *boltRangeIterator) seekLE(key []byte, strict bool) ([]byte, []byte) {
+	if key == nil {
+		return x.c.Last()
+	}
+	k, v := x.c.Seek(key)
+	if k == nil {
+		return x.c.Last()
+	}
+	if c := bytes.Compare(k, key); c > 0 || (strict && c == 0) {
+		return x.c.Prev()
+	}
+	return k, v
+}
+
+func (x *boltRangeIterator) first() ([]byte, []byte) {
+	r := x.r
+	if !r.reverse {
+		start := r.from
+		if r.prefix != nil {
+			start = r.prefix
+		}
+		if r.after != nil && bytes.Compare(r.after, start) >= 0 {
+			k, v := x.c.Seek(r.after)
+			if k != nil && bytes.Equal(k, r.after) {
+				return x.c.Next()
+			}
+			return k, v
+		}
+		if len(start) == 0 {
+			return x.c.First()
+		}
+		return x.c.Seek(start)
+	}
+	var end []byte
+	strict := false
+	switch {
+	case r.prefix != nil:
+		end, strict = prefixEnd(r.prefix), true
+	case len(r.to) > 0:
+		end, strict = r.to, r.toExcl
+	}
+	if r.after != nil && (end == nil || bytes.Compare(r.after, end) <= 0) {
+		end, strict = r.after, true
+	}
+	return x.seekLE(end, strict)
+}
+
+func (x *boltRangeIterator) inRange(k []byte) bool {
+	r := x.r
+	if r.prefix != nil {
+		return bytes.HasPrefix(k, r.prefix)
+	}
+	if r.reverse {
+		return len(r.from) == 0 || bytes.Compare(k, r.from) >= 0
+	}
+	if len(r.to) == 0 {
+		return true
+	}
+	if r.toExcl {
+		return bytes.Compare(k, r.to) < 0
+	}
+	return bytes.Compare(k, r.to) <= 0
+}
+
+func (x *boltRangeIterator) Next() (VMValue, bool, error) {
+	if err := x.r.t.checkTx(); err != nil {
+		return VMNil, false, err
+	}
+	if x.r.limit > 0 && x.count >= x.r.limit {
+		return VMNil, false, nil
+	}
+	var k, v []byte
+	if !x.started {
+		x.started = true
+		k, v = x.skipTables(x.first())
+		for i := 0; i < x.r.offset && k != nil && x.inRange(k); i++ {
+			k, v = x.skipTables(x.step())
+		}
+	} else {
+		k, v = x.skipTables(x.step())
+	}
+	if k == nil || !x.inRange(k) {
+		return VMNil, false, nil
+	}
+	if x.r.idx != nil {
+		// в индексе значением является ключ записи
+		k = v
+		if v = x.r.t.b.Get(k); v == nil {
+			return VMNil, false, VMErrorWrongDBValue
+		}
+	}
+	vv, err := parseBoltValue(v)
+	if err != nil {
+		return VMNil, false, err
+	}
+	x.count++
+	rv := make(VMStringMap, 2)
+	rv["Ключ"] = VMString(k)
+	rv["Значение"] = vv
+	return rv, true, nil
+}
+
+// skipTables пропускает вложенные таблицы, у которых нет значения
+func (x *boltRangeIterator) skipTables(k, v []byte) ([]byte, []byte) {
+	for k != nil && v == nil {
+		k, v = x.step()
+	}
+	return k, v
+}
+
+func (x *boltRangeIterator) step() ([]byte, []byte) {
+	if x.r.reverse {
+		return x.c.Prev()
+	}
+	return x.c.Next()
+}
@@ -0,0 +1,420 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// Обслуживание файловой базы данных: сжатие, выгрузка и загрузка в формате JSON lines,
+// проверка целостности и статистика. Функции используются как из скриптов, так и из командной строки.
+
+// BoltOpen открывает файл базы для обслуживания
+func BoltOpen(name string, readonly bool) (*bolt.DB, error) {
+	return bolt.Open(name, 0o600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: readonly})
+}
+
+// boltWalk обходит таблицы рекурсивно, служебные таблицы индексов пропускаются
+func boltWalk(path []string, b *bolt.Bucket, f func(path []string, b *bolt.Bucket) error) error {
+	if err := f(path, b); err != nil {
+		return err
+	}
+	return b.ForEach(func(k, v []byte) error {
+		if v != nil || string(k) == boltIndexBucket {
+			return nil
+		}
+		sub := append(append([]string(nil), path...), string(k))
+		return boltWalk(sub, b.Bucket(k), f)
+	})
+}
+
+func boltWalkTx(tx *bolt.Tx, f func(path []string, b *bolt.Bucket) error) error {
+	return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		return boltWalk([]string{string(name)}, b, f)
+	})
+}
+
+// boltCopyBucket копирует содержимое таблицы со всеми вложенными, включая индексы
+func boltCopyBucket(dst, src *bolt.Bucket) error {
+	// ключи при копировании идут по порядку, поэтому страницы можно заполнять полностью
+	dst.FillPercent = 1.0
+	if err := dst.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(k, v)
+		}
+		sub, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+		return boltCopyBucket(sub, src.Bucket(k))
+	})
+}
+
+// BoltCompact переписывает базу в новый файл, освобождая неиспользуемые страницы
+func BoltCompact(src *bolt.DB, dstName string) error {
+	if _, err := os.Stat(dstName); err == nil {
+		return VMErrorDBFileExists
+	}
+	dst, err := BoltOpen(dstName, false)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	return src.View(func(stx *bolt.Tx) error {
+		return dst.Update(func(dtx *bolt.Tx) error {
+			return stx.ForEach(func(name []byte, b *bolt.Bucket) error {
+				db, err := dtx.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return boltCopyBucket(db, b)
+			})
+		})
+	})
+}
+
+// boltExportLine строка выгрузки: описание таблицы (с индексами и последовательностью) или запись.
+// Значение записи выгружается в JSON для чтения человеком, а для загрузки - в бинарном виде (Base64)
+// вместе с типом, т.к. в JSON теряются типы вложенных значений (даты и числа в структурах становятся строками).
+// Строки без бинарных данных загружаются из JSON, восстанавливается только тип верхнего уровня.
+type boltExportLine struct {
+	Table    []string        `json:"Таблица"`
+	Key      *string         `json:"Ключ,omitempty"`
+	Type     VMBinaryType    `json:"Тип,omitempty"`
+	Value    json.RawMessage `json:"Значение,omitempty"`
+	Data     []byte          `json:"Данные,omitempty"`
+	Indexes  []string        `json:"Индексы,omitempty"`
+	Sequence uint64          `json:"Последовательность,omitempty"`
+}
+
+// BoltExport выгружает все таблицы в формате JSON lines, по одной записи в строке
+func BoltExport(db *bolt.DB, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	err := db.View(func(tx *bolt.Tx) error {
+		return boltWalkTx(tx, func(path []string, b *bolt.Bucket) error {
+			hdr := boltExportLine{Table: path, Sequence: b.Sequence()}
+			if ib := b.Bucket([]byte(boltIndexBucket)); ib != nil {
+				ib.ForEach(func(k, _ []byte) error {
+					hdr.Indexes = append(hdr.Indexes, string(k))
+					return nil
+				})
+			}
+			if err := enc.Encode(hdr); err != nil {
+				return err
+			}
+			return b.ForEach(func(k, v []byte) error {
+				if v == nil {
+					return nil
+				}
+				vv, err := parseBoltValue(v)
+				if err != nil {
+					return err
+				}
+				raw, err := json.Marshal(vv)
+				if err != nil {
+					return err
+				}
+				key := string(k)
+				return enc.Encode(boltExportLine{Table: path, Key: &key, Type: VMBinaryType(v[0]), Value: raw, Data: v[1:]})
+			})
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// BoltImport загружает выгрузку в одной транзакции: либо все записи, либо ничего
+func BoltImport(db *bolt.DB, r io.Reader) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	return db.Update(func(tx *bolt.Tx) error {
+		for {
+			var line boltExportLine
+			if err := dec.Decode(&line); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if len(line.Table) == 0 {
+				return VMErrorWrongDBValue
+			}
+			b, err := tx.CreateBucketIfNotExists([]byte(line.Table[0]))
+			if err != nil {
+				return err
+			}
+			for _, name := range line.Table[1:] {
+				if b, err = b.CreateBucketIfNotExists([]byte(name)); err != nil {
+					return err
+				}
+			}
+			t := &VMBoltTable{name: strings.Join(line.Table, "/"), b: b, writable: true}
+			if line.Key == nil {
+				// описание таблицы идет до ее записей, поэтому индексы заполняются при установке значений
+				for _, f := range line.Indexes {
+					if err := t.СоздатьИндекс(VMString(f), nil); err != nil {
+						return err
+					}
+				}
+				if line.Sequence > b.Sequence() {
+					if err := b.SetSequence(line.Sequence); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			var v VMValue
+			if line.Data != nil {
+				v, err = line.Type.ParseBinary(line.Data)
+			} else {
+				v, err = line.Type.ParseJSON(line.Value)
+			}
+			if err != nil {
+				return err
+			}
+			bt, ok := v.(VMBinaryTyper)
+			if !ok {
+				return VMErrorNeedBinaryTyper
+			}
+			if err := t.Set(*line.Key, bt); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// BoltCheck проверяет целостность страниц базы и возвращает найденные ошибки
+func BoltCheck(db *bolt.DB) ([]error, error) {
+	var errs []error
+	err := db.View(func(tx *bolt.Tx) error {
+		for err := range tx.Check() {
+			errs = append(errs, err)
+		}
+		return nil
+	})
+	return errs, err
+}
+
+// BoltTableStats статистика одной таблицы, вложенные таблицы учитываются отдельно
+type BoltTableStats struct {
+	Table   string // путь через "/"
+	Keys    int    // количество записей
+	Tables  int    // количество вложенных таблиц
+	Indexes int    // количество индексов
+	Data    int64  // суммарный размер ключей и значений в байтах
+	Alloc   int64  // байт в выделенных страницах, включая вложенные таблицы
+}
+
+func BoltStats(db *bolt.DB) ([]BoltTableStats, error) {
+	var rv []BoltTableStats
+	err := db.View(func(tx *bolt.Tx) error {
+		return boltWalkTx(tx, func(path []string, b *bolt.Bucket) error {
+			st := BoltTableStats{Table: strings.Join(path, "/")}
+			b.ForEach(func(k, v []byte) error {
+				switch {
+				case string(k) == boltIndexBucket:
+					st.Indexes = b.Bucket(k).Stats().BucketN - 1
+				case v == nil:
+					st.Tables++
+				default:
+					st.Keys++
+					st.Data += int64(len(k) + len(v))
+				}
+				return nil
+			})
+			bs := b.Stats()
+			st.Alloc = int64(bs.BranchAlloc + bs.LeafAlloc)
+			rv = append(rv, st)
+			return nil
+		})
+	})
+	return rv, err
+}
+
+func (s BoltTableStats) StringMap() VMStringMap {
+	return VMStringMap{
+		"Таблица":            VMString(s.Table),
+		"Записей":            VMInt(s.Keys),
+		"ВложенныхТаблиц":    VMInt(s.Tables),
+		"Индексов":           VMInt(s.Indexes),
+		"РазмерДанных":       VMInt(s.Data),
+		"ВыделеноСтраницами": VMInt(s.Alloc),
+	}
+}
+
+// opened возвращает открытую базу bolt
+func (x *VMBoltDB) opened() (*bolt.DB, error) {
+	x.Lock()
+	defer x.Unlock()
+	if x.db == nil {
+		return nil, VMErrorDBNotOpened
+	}
+	return x.db, nil
+}
+
+// РезервнаяКопия(ИмяФайла) сохраняет копию базы, не блокируя запись в нее
+func (x *VMBoltDB) РезервнаяКопия(name VMString, rets *VMSlice) error {
+	db, err := x.opened()
+	if err != nil {
+		return err
+	}
+	return db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(string(name), 0o600)
+	})
+}
+
+// Восстановить(ИмяФайла) заменяет базу резервной копией, незавершенные транзакции отменяются.
+// Копия сначала открывается и проверяется, затем записывается во временный файл рядом с базой,
+// который атомарно заменяет файл базы, так что при ошибке текущая база остается нетронутой.
+func (x *VMBoltDB) Восстановить(name VMString, rets *VMSlice) error {
+	x.Lock()
+	fname := x.name
+	x.Unlock()
+	if fname == "" {
+		return VMErrorDBNotOpened
+	}
+	if err := boltValidateFile(string(name)); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(string(name))
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(fname), filepath.Base(fname)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o600)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	x.Close()
+	if err := os.Rename(tmp.Name(), fname); err != nil {
+		os.Remove(tmp.Name())
+		// база остается прежней, открываем ее снова
+		if oerr := x.Open(fname); oerr != nil {
+			return oerr
+		}
+		return err
+	}
+	return x.Open(fname)
+}
+
+// boltValidateFile открывает файл базы только на чтение и проверяет целостность страниц
+func boltValidateFile(name string) error {
+	// bolt создает отсутствующий файл даже при открытии только на чтение
+	if _, err := os.Stat(name); err != nil {
+		return err
+	}
+	db, err := BoltOpen(name, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	errs, err := BoltCheck(db)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %s", VMErrorDBCorrupted, errs[0])
+	}
+	return nil
+}
+
+// Сжать(ИмяНовогоФайла) записывает сжатую копию базы в новый файл
+func (x *VMBoltDB) Сжать(name VMString, rets *VMSlice) error {
+	db, err := x.opened()
+	if err != nil {
+		return err
+	}
+	return BoltCompact(db, string(name))
+}
+
+// Экспортировать(ИмяФайла) выгружает все таблицы в файл JSON lines
+func (x *VMBoltDB) Экспортировать(name VMString, rets *VMSlice) error {
+	db, err := x.opened()
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(string(name))
+	if err != nil {
+		return err
+	}
+	if err = BoltExport(db, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Импортировать(ИмяФайла) загружает выгрузку, существующие записи с теми же ключами перезаписываются
+func (x *VMBoltDB) Импортировать(name VMString, rets *VMSlice) error {
+	db, err := x.opened()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(string(name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return BoltImport(db, f)
+}
+
+// Проверить() возвращает массив строк с описанием ошибок целостности, пустой если база исправна
+func (x *VMBoltDB) Проверить(rets *VMSlice) error {
+	db, err := x.opened()
+	if err != nil {
+		return err
+	}
+	errs, err := BoltCheck(db)
+	if err != nil {
+		return err
+	}
+	rv := make(VMSlice, len(errs))
+	for i, e := range errs {
+		rv[i] = VMString(e.Error())
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// Статистика() возвращает массив структур по каждой таблице: Таблица, Записей, ВложенныхТаблиц,
+// Индексов, РазмерДанных, ВыделеноСтраницами
+func (x *VMBoltDB) Статистика(rets *VMSlice) error {
+	db, err := x.opened()
+	if err != nil {
+		return err
+	}
+	st, err := BoltStats(db)
+	if err != nil {
+		return err
+	}
+	rv := make(VMSlice, len(st))
+	for i, s := range st {
+		rv[i] = s.StringMap()
+	}
+	rets.Append(rv)
+	return nil
+}
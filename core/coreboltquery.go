@@ -0,0 +1,433 @@
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shinanca/gonec/names"
+)
+
+// VMBoltQuery запрос к документам (структурам), хранящимся в таблице файловой базы данных.
+// Создается методом Таблица.Запрос(), условия и параметры задаются цепочкой вызовов:
+//
+//	рез = тб.Запрос().Где("Статус", "=", "новая").Упорядочить("Приоритет", Истина).Ограничение(10).Выполнить()
+//
+// Имя поля "Ключ" означает ключ записи, если в документе нет поля с таким именем.
+// Если условие на равенство или сравнение наложено на поле с индексом, то перебираются только
+// подходящие записи индекса. Без сортировки записи выдаются в порядке ключей (или значений индекса).
+type VMBoltQuery struct {
+	t      *VMBoltTable
+	conds  []boltQueryCond
+	orders []boltQueryOrder
+	fields []string
+	limit  int
+	offset int
+}
+
+type boltQueryCond struct {
+	field string
+	op    string
+	value VMValue
+}
+
+type boltQueryOrder struct {
+	field string
+	desc  bool
+}
+
+type boltQueryRow struct {
+	key string
+	doc VMStringMap
+}
+
+func (x *VMBoltQuery) VMTypeString() string {
+	return "ЗапросФайловойБазыДанных"
+}
+
+func (x *VMBoltQuery) Interface() interface{} {
+	return x
+}
+
+func (x *VMBoltQuery) String() string {
+	return "Запрос к таблице '" + x.t.name + "' файловой базы данных BoltDB"
+}
+
+func (x *VMBoltTable) Запрос(rets *VMSlice) error {
+	if err := x.checkTx(); err != nil {
+		return err
+	}
+	rets.Append(&VMBoltQuery{t: x})
+	return nil
+}
+
+func (x *VMBoltQuery) MethodMember(name int) (VMFunc, bool) {
+	// только эти методы будут доступны из кода на языке Гонец!
+	switch names.UniqueNames.GetLowerCase(name) {
+	case "где":
+		return VMFuncThreeParams(x.Где), true
+	case "упорядочить":
+		return VMFuncOneParamOptionals(1, x.Упорядочить), true
+	case "выбрать":
+		return VMFuncOneParam(x.Выбрать), true
+	case "ограничение":
+		return VMFuncOneParam(x.Ограничение), true
+	case "смещение":
+		return VMFuncOneParam(x.Смещение), true
+	case "выполнить":
+		return VMFuncZeroParams(x.Выполнить), true
+	case "втаблицузначений":
+		return VMFuncZeroParams(x.ВТаблицуЗначений), true
+	case "количество":
+		return VMFuncZeroParams(x.Количество), true
+	case "сумма":
+		return VMFuncOneParam(x.Сумма), true
+	case "минимум":
+		return VMFuncOneParam(x.Минимум), true
+	case "максимум":
+		return VMFuncOneParam(x.Максимум), true
+	}
+	return nil, false
+}
+
+// Где(Поле, Оператор, Значение) добавляет условие, все условия объединяются по И.
+// Операторы: "=", "<>", "<", "<=", ">", ">=", "В" (значение - массив),
+// "Содержит" (подстрока или элемент массива), "НачинаетсяС"
+func (x *VMBoltQuery) Где(field, op VMString, v VMValue, rets *VMSlice) error {
+	o := strings.ToLower(string(op))
+	switch o {
+	case "=", "<>", "!=", "<", "<=", ">", ">=", "содержит", "начинаетсяс":
+	case "в":
+		if _, ok := v.(VMSlice); !ok {
+			return VMErrorNeedSlice
+		}
+	default:
+		return VMErrorQueryOperator
+	}
+	x.conds = append(x.conds, boltQueryCond{field: string(field), op: o, value: v})
+	rets.Append(x)
+	return nil
+}
+
+// Упорядочить(Поле, ПоУбыванию) добавляет поле сортировки, повторные вызовы задают следующие поля
+func (x *VMBoltQuery) Упорядочить(field VMString, rest VMSlice, rets *VMSlice) error {
+	desc := false
+	if len(rest) > 0 {
+		b, ok := rest[0].(VMBool)
+		if !ok {
+			return VMErrorNeedBool
+		}
+		desc = bool(b)
+	}
+	x.orders = append(x.orders, boltQueryOrder{field: string(field), desc: desc})
+	rets.Append(x)
+	return nil
+}
+
+// Выбрать(Поля) задает список полей результата строкой через запятую или массивом
+func (x *VMBoltQuery) Выбрать(v VMValue, rets *VMSlice) error {
+	x.fields = x.fields[:0]
+	switch vv := v.(type) {
+	case VMString:
+		for _, f := range strings.Split(string(vv), ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				x.fields = append(x.fields, f)
+			}
+		}
+	case VMSlice:
+		for _, f := range vv {
+			s, ok := f.(VMString)
+			if !ok {
+				return VMErrorNeedString
+			}
+			x.fields = append(x.fields, string(s))
+		}
+	default:
+		return VMErrorNeedString
+	}
+	rets.Append(x)
+	return nil
+}
+
+func (x *VMBoltQuery) Ограничение(n VMInt, rets *VMSlice) error {
+	x.limit = int(n)
+	rets.Append(x)
+	return nil
+}
+
+func (x *VMBoltQuery) Смещение(n VMInt, rets *VMSlice) error {
+	x.offset = int(n)
+	rets.Append(x)
+	return nil
+}
+
+func queryField(r boltQueryRow, field string) (VMValue, bool) {
+	if v, ok := structParam(r.doc, field); ok {
+		return v, true
+	}
+	if strings.EqualFold(field, "Ключ") {
+		return VMString(r.key), true
+	}
+	return VMNil, false
+}
+
+func (c boltQueryCond) match(r boltQueryRow) bool {
+	v, ok := queryField(r, c.field)
+	if !ok {
+		// отсутствующее поле удовлетворяет только условию неравенства
+		return c.op == "<>" || c.op == "!="
+	}
+	switch c.op {
+	case "=":
+		return EqualVMValues(v, c.value)
+	case "<>", "!=":
+		return !EqualVMValues(v, c.value)
+	case "<":
+		return BoolOperVMValues(v, c.value, LSS)
+	case "<=":
+		return BoolOperVMValues(v, c.value, LEQ)
+	case ">":
+		return BoolOperVMValues(v, c.value, GTR)
+	case ">=":
+		return BoolOperVMValues(v, c.value, GEQ)
+	case "в":
+		for _, e := range c.value.(VMSlice) {
+			if EqualVMValues(v, e) {
+				return true
+			}
+		}
+		return false
+	case "содержит":
+		switch vv := v.(type) {
+		case VMString:
+			s, ok := c.value.(VMString)
+			return ok && strings.Contains(string(vv), string(s))
+		case VMSlice:
+			for _, e := range vv {
+				if EqualVMValues(e, c.value) {
+					return true
+				}
+			}
+		}
+		return false
+	case "начинаетсяс":
+		vv, ok := v.(VMString)
+		s, ok2 := c.value.(VMString)
+		return ok && ok2 && strings.HasPrefix(string(vv), string(s))
+	}
+	return false
+}
+
+// source возвращает диапазон для перебора: по индексу, если он подходит к одному из условий, иначе всю таблицу.
+// Условия проверяются для каждой записи в любом случае, поэтому границы индекса берутся включительно.
+func (x *VMBoltQuery) source() *VMBoltRange {
+	for _, c := range x.conds {
+		fb, err := x.t.index(c.field)
+		if err != nil {
+			continue
+		}
+		e, ok := boltIndexValue(c.value)
+		if !ok {
+			continue
+		}
+		r := &VMBoltRange{t: x.t, idx: fb, toExcl: true}
+		switch c.op {
+		case "=":
+			r.prefix = e
+		case "<", "<=":
+			r.from, r.to = e[:1], prefixEnd(e)
+		case ">", ">=":
+			r.from, r.to = e, prefixEnd(e[:1])
+		default:
+			continue
+		}
+		return r
+	}
+	return &VMBoltRange{t: x.t}
+}
+
+// rows выполняет запрос и возвращает подходящие записи с учетом сортировки, смещения и ограничения
+func (x *VMBoltQuery) rows() ([]boltQueryRow, error) {
+	it, err := x.source().Iterator()
+	if err != nil {
+		return nil, err
+	}
+	// без сортировки перебор прекращается, как только набрано нужное количество
+	stopAt := -1
+	if len(x.orders) == 0 && x.limit > 0 {
+		stopAt = x.offset + x.limit
+	}
+	var rows []boltQueryRow
+	for stopAt < 0 || len(rows) < stopAt {
+		v, ok, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		kv := v.(VMStringMap)
+		doc, ok := kv["Значение"].(VMStringMap)
+		if !ok {
+			continue
+		}
+		r := boltQueryRow{key: string(kv["Ключ"].(VMString)), doc: doc}
+		matched := true
+		for _, c := range x.conds {
+			if !c.match(r) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			rows = append(rows, r)
+		}
+	}
+
+	if len(x.orders) > 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			for _, o := range x.orders {
+				vi, _ := queryField(rows[i], o.field)
+				vj, _ := queryField(rows[j], o.field)
+				if o.desc {
+					vi, vj = vj, vi
+				}
+				if SortLessVMValues(vi, vj) {
+					return true
+				}
+				if SortLessVMValues(vj, vi) {
+					return false
+				}
+			}
+			return false
+		})
+	}
+
+	if x.offset > 0 {
+		if x.offset >= len(rows) {
+			return nil, nil
+		}
+		rows = rows[x.offset:]
+	}
+	if x.limit > 0 && len(rows) > x.limit {
+		rows = rows[:x.limit]
+	}
+	return rows, nil
+}
+
+func (x *VMBoltQuery) project(r boltQueryRow) VMStringMap {
+	if len(x.fields) == 0 {
+		return r.doc
+	}
+	rv := make(VMStringMap, len(x.fields))
+	for _, f := range x.fields {
+		rv[f], _ = queryField(r, f)
+	}
+	return rv
+}
+
+// Выполнить() возвращает массив структур-документов с учетом выбранных полей
+func (x *VMBoltQuery) Выполнить(rets *VMSlice) error {
+	rows, err := x.rows()
+	if err != nil {
+		return err
+	}
+	rv := make(VMSlice, len(rows))
+	for i, r := range rows {
+		rv[i] = x.project(r)
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// ВТаблицуЗначений() возвращает результат в виде таблицы значений, колонки - выбранные поля
+// или все поля документов в порядке их появления
+func (x *VMBoltQuery) ВТаблицуЗначений(rets *VMSlice) error {
+	rows, err := x.rows()
+	if err != nil {
+		return err
+	}
+	vt := NewVMTable()
+	cols := vt.Columns()
+	if len(x.fields) > 0 {
+		for _, f := range x.fields {
+			cols.Add(f)
+		}
+	} else {
+		for _, r := range rows {
+			keys := make([]string, 0, len(r.doc))
+			for k := range r.doc {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				cols.Add(k)
+			}
+		}
+	}
+	for _, r := range rows {
+		l := vt.AddLine()
+		for k, v := range x.project(r) {
+			l.Set(k, v)
+		}
+	}
+	rets.Append(vt)
+	return nil
+}
+
+func (x *VMBoltQuery) Количество(rets *VMSlice) error {
+	rows, err := x.rows()
+	if err != nil {
+		return err
+	}
+	rets.Append(VMInt(len(rows)))
+	return nil
+}
+
+// Сумма(Поле) складывает числовые значения поля, остальные значения пропускаются
+func (x *VMBoltQuery) Сумма(field VMString, rets *VMSlice) error {
+	rows, err := x.rows()
+	if err != nil {
+		return err
+	}
+	var sum VMValue = VMInt(0)
+	for _, r := range rows {
+		v, _ := queryField(r, string(field))
+		switch v.(type) {
+		case VMInt, VMDecNum:
+			if sum, err = sum.(VMOperationer).EvalBinOp(ADD, v.(VMOperationer)); err != nil {
+				return err
+			}
+		}
+	}
+	rets.Append(sum)
+	return nil
+}
+
+func (x *VMBoltQuery) extremum(field string, max bool, rets *VMSlice) error {
+	rows, err := x.rows()
+	if err != nil {
+		return err
+	}
+	var rv VMValue = VMNil
+	for _, r := range rows {
+		v, ok := queryField(r, field)
+		if !ok || v == VMNil {
+			continue
+		}
+		if rv == VMNil || (max && SortLessVMValues(rv, v)) || (!max && SortLessVMValues(v, rv)) {
+			rv = v
+		}
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// Минимум(Поле) возвращает наименьшее значение поля или Неопределено, если значений нет
+func (x *VMBoltQuery) Минимум(field VMString, rets *VMSlice) error {
+	return x.extremum(string(field), false, rets)
+}
+
+// Максимум(Поле) возвращает наибольшее значение поля или Неопределено, если значений нет
+func (x *VMBoltQuery) Максимум(field VMString, rets *VMSlice) error {
+	return x.extremum(string(field), true, rets)
+}
@@ -172,6 +172,9 @@ func (x VMBool) ConvertToType(nt reflect.Type) (VMValue, error) {
 	return VMNil, VMErrorNotConverted
 }
 
+// MarshalBinary записывает Истина как 0, а Ложь как 1.
+// Такой формат уже используется в существующих файлах баз данных, поэтому он сохраняется,
+// а чтение в UnmarshalBinary приведено в соответствие с записью
 func (x VMBool) MarshalBinary() ([]byte, error) {
 	var buf bytes.Buffer
 	if bool(x) {
@@ -187,11 +190,7 @@ func (x *VMBool) UnmarshalBinary(data []byte) error {
 	if v, err := buf.ReadByte(); err != nil {
 		return err
 	} else {
-		if v == 0 {
-			*x = VMBool(false)
-		} else {
-			*x = VMBool(true)
-		}
+		*x = VMBool(v == 0)
 	}
 	return nil
 }
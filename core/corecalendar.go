@@ -0,0 +1,187 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// VMCalendar производственный календарь: рабочими считаются дни, кроме выходных дней недели
+// и праздников, с учетом переносов (выходной день, объявленный рабочим).
+//
+// Файл календаря содержит по одной дате в строке, за которой через ";" может следовать тип дня:
+//
+//	2024-01-01;Праздник
+//	02.11.2024;Рабочий
+//
+// Допустимые типы: Праздник, Выходной, Рабочий, Предпраздничный (рабочий). Без типа - праздник.
+// Пустые строки и строки, начинающиеся с "#" или "//", пропускаются.
+type VMCalendar struct {
+	VMMetaObj
+
+	weekend [7]bool         // индекс - time.Weekday
+	days    map[string]bool // дата в формате 2006-01-02 - является ли день рабочим
+}
+
+func (x *VMCalendar) VMTypeString() string {
+	return "ПроизводственныйКалендарь"
+}
+
+func (x *VMCalendar) VMRegister() {
+	x.weekend[time.Saturday] = true
+	x.weekend[time.Sunday] = true
+	x.days = make(map[string]bool)
+
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) > 1 {
+			return VMErrorNeedArgs(1)
+		}
+		if len(args) == 1 {
+			name, ok := args[0].(VMString)
+			if !ok {
+				return VMErrorNeedString
+			}
+			return x.Load(string(name))
+		}
+		return nil
+	})
+
+	x.VMRegisterMethod("Загрузить", VMFuncOneParam(x.Загрузить))
+	x.VMRegisterMethod("УстановитьВыходныеДни", VMFuncOneParam(x.УстановитьВыходныеДни))
+	x.VMRegisterMethod("ДобавитьПраздник", VMFuncOneParam(x.ДобавитьПраздник))
+	x.VMRegisterMethod("ДобавитьРабочийДень", VMFuncOneParam(x.ДобавитьРабочийДень))
+	x.VMRegisterMethod("ЭтоРабочийДень", VMFuncOneParam(x.ЭтоРабочийДень))
+	x.VMRegisterMethod("ДобавитьРабочиеДни", VMFuncTwoParams(x.ДобавитьРабочиеДни))
+	x.VMRegisterMethod("КоличествоРабочихДней", VMFuncTwoParams(x.КоличествоРабочихДней))
+}
+
+func calendarKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+func parseCalendarDate(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02.01.2006", "20060102"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, VMErrorCalendarFormat
+}
+
+// Load добавляет дни из файла календаря к уже заданным
+func (x *VMCalendar) Load(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	line := 0
+	for sc.Scan() {
+		line++
+		s := strings.TrimSpace(sc.Text())
+		if s == "" || strings.HasPrefix(s, "#") || strings.HasPrefix(s, "//") {
+			continue
+		}
+		ds, kind, _ := strings.Cut(s, ";")
+		t, err := parseCalendarDate(strings.TrimSpace(ds))
+		if err != nil {
+			return fmt.Errorf("%w: строка %d", err, line)
+		}
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "", "праздник", "выходной":
+			x.days[calendarKey(t)] = false
+		case "рабочий", "предпраздничный":
+			x.days[calendarKey(t)] = true
+		default:
+			return fmt.Errorf("%w: строка %d", VMErrorCalendarFormat, line)
+		}
+	}
+	return sc.Err()
+}
+
+// IsWorkday проверяет, является ли день даты рабочим
+func (x *VMCalendar) IsWorkday(t time.Time) bool {
+	if w, ok := x.days[calendarKey(t)]; ok {
+		return w
+	}
+	return !x.weekend[t.Weekday()]
+}
+
+// AddWorkdays сдвигает дату на n рабочих дней вперед (или назад при n < 0), время дня сохраняется
+func (x *VMCalendar) AddWorkdays(t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if x.IsWorkday(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// CountWorkdays считает рабочие дни в интервале дат, обе границы включаются
+func (x *VMCalendar) CountWorkdays(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, from.Location())
+	n := 0
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if x.IsWorkday(d) {
+			n++
+		}
+	}
+	return n
+}
+
+// Загрузить(ИмяФайла) добавляет праздники и переносы из файла
+func (x *VMCalendar) Загрузить(name VMString, rets *VMSlice) error {
+	return x.Load(string(name))
+}
+
+// УстановитьВыходныеДни(Массив) задает выходные дни недели: 1=понедельник, ..., 7=воскресенье
+func (x *VMCalendar) УстановитьВыходныеДни(days VMSlice, rets *VMSlice) error {
+	var weekend [7]bool
+	for _, v := range days {
+		d, ok := v.(VMInt)
+		if !ok || d < 1 || d > 7 {
+			return VMErrorNeedInt
+		}
+		weekend[d%7] = true
+	}
+	x.weekend = weekend
+	return nil
+}
+
+func (x *VMCalendar) ДобавитьПраздник(d VMTime, rets *VMSlice) error {
+	x.days[calendarKey(time.Time(d))] = false
+	return nil
+}
+
+// ДобавитьРабочийДень(Дата) объявляет день рабочим, например выходной при переносе
+func (x *VMCalendar) ДобавитьРабочийДень(d VMTime, rets *VMSlice) error {
+	x.days[calendarKey(time.Time(d))] = true
+	return nil
+}
+
+func (x *VMCalendar) ЭтоРабочийДень(d VMTime, rets *VMSlice) error {
+	rets.Append(VMBool(x.IsWorkday(time.Time(d))))
+	return nil
+}
+
+// ДобавитьРабочиеДни(Дата, Количество) возвращает дату, отстоящую на указанное число рабочих дней
+func (x *VMCalendar) ДобавитьРабочиеДни(d VMTime, n VMInt, rets *VMSlice) error {
+	rets.Append(VMTime(x.AddWorkdays(time.Time(d), int(n))))
+	return nil
+}
+
+// КоличествоРабочихДней(Начало, Окончание) - количество рабочих дней в интервале, включая границы
+func (x *VMCalendar) КоличествоРабочихДней(from, to VMTime, rets *VMSlice) error {
+	rets.Append(VMInt(x.CountWorkdays(time.Time(from), time.Time(to))))
+	return nil
+}
@@ -0,0 +1,368 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/shinanca/gonec/names"
+)
+
+// Пользовательские типы, объявленные в коде на языке Гонец:
+//
+//	Интерфейс Печатаемый
+//		Функция Представление()
+//	КонецИнтерфейса
+//
+//	Класс Товар Реализует Печатаемый
+//		Перем Наименование = ""
+//		Перем Цена = 0
+//		Процедура ПриСозданииОбъекта(Наименование, Цена)
+//			ЭтотОбъект.Наименование = Наименование
+//			ЭтотОбъект.Цена = Цена
+//		КонецПроцедуры
+//		Функция Представление()
+//			Возврат ЭтотОбъект.Наименование + " по " + Строка(ЭтотОбъект.Цена)
+//		КонецФункции
+//	КонецКласса
+//
+//	т = Новый Товар("Хлеб", 30)
+//
+// Методы получают объект скрытым первым параметром ЭтотОбъект.
+// Экземпляры создаются через Новый так же, как системные объекты на основе VMMetaObj.
+
+const (
+	ClassConstructorName = "ПриСозданииОбъекта"
+	ThisObjectName       = "ЭтотОбъект"
+)
+
+// VMInterface описание интерфейса: имена методов и количество их параметров
+type VMInterface struct {
+	Name    string
+	Methods []int
+	NumArgs []int // -1 - любое количество
+}
+
+func (x *VMInterface) VMTypeString() string { return "Интерфейс" }
+
+func (x *VMInterface) String() string { return "Интерфейс " + x.Name }
+
+// ImplementedBy проверяет, что у значения есть все методы интерфейса.
+// У объектов пользовательских типов проверяется и количество параметров.
+func (x *VMInterface) ImplementedBy(v VMValue) bool {
+	if o, ok := v.(*VMObject); ok && o.class != nil {
+		return o.class.missingMethod(x) == 0
+	}
+	for _, m := range x.Methods {
+		switch vv := v.(type) {
+		case VMMetaObject:
+			if _, ok := vv.VMGetMethod(m); !ok {
+				return false
+			}
+		case VMMethodImplementer:
+			if _, ok := vv.MethodMember(m); !ok {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// VMClass описание пользовательского типа
+type VMClass struct {
+	Name       string
+	Fields     []int
+	Defaults   VMSlice        // начальные значения полей
+	Methods    map[int]VMFunc // методы с первым параметром ЭтотОбъект
+	NumArgs    map[int]int    // количество параметров методов без ЭтотОбъект
+	Interfaces []*VMInterface
+}
+
+func NewVMClass(name string) *VMClass {
+	return &VMClass{
+		Name:    name,
+		Methods: make(map[int]VMFunc),
+		NumArgs: make(map[int]int),
+	}
+}
+
+// missingMethod возвращает первый метод интерфейса, которого нет в типе, или 0
+func (c *VMClass) missingMethod(i *VMInterface) int {
+	for j, m := range i.Methods {
+		n, ok := c.NumArgs[m]
+		if !ok || (i.NumArgs[j] >= 0 && n >= 0 && n != i.NumArgs[j]) {
+			return m
+		}
+	}
+	return 0
+}
+
+// Implement проверяет, что тип реализует интерфейс, и запоминает его
+func (c *VMClass) Implement(i *VMInterface) error {
+	if m := c.missingMethod(i); m != 0 {
+		return fmt.Errorf("Тип %s не реализует метод %s интерфейса %s", c.Name, names.UniqueNames.Get(m), i.Name)
+	}
+	c.Interfaces = append(c.Interfaces, i)
+	return nil
+}
+
+// DefineClass регистрирует пользовательский тип в глобальном контексте, чтобы его экземпляры
+// можно было создавать через Новый. Повторное объявление типа с тем же именем заменяет его.
+func (e *Env) DefineClass(c *VMClass) error {
+	id := names.UniqueNames.Set(c.Name)
+	for ee := e; ee != nil; ee = ee.parent {
+		if ee.parent == nil {
+			ee.Lock()
+			if ee.classes == nil {
+				ee.classes = make(map[int]*VMClass)
+			}
+			ee.classes[id] = c
+			ee.typ[id] = ReflectVMObject
+			ee.Unlock()
+			return nil
+		}
+	}
+	return fmt.Errorf("Отсутствует глобальный контекст!")
+}
+
+// Class возвращает пользовательский тип, объявленный в глобальном контексте окружения
+func (e *Env) Class(id int) (*VMClass, error) {
+	for ee := e; ee != nil; ee = ee.parent {
+		ee.RLock()
+		c, ok := ee.classes[id]
+		ee.RUnlock()
+		if ok {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("Тип неопределен '%s'", names.UniqueNames.Get(id))
+}
+
+// VMObject экземпляр пользовательского типа
+type VMObject struct {
+	VMMetaObj
+
+	class  *VMClass
+	fields map[int]VMValue
+	name   string // имя типа и порядок полей восстановленного объекта, пока он не привязан к типу
+	order  []int
+}
+
+var ReflectVMObject = reflect.TypeOf(VMObject{})
+
+// NewVMObject создает экземпляр типа без вызова конструктора
+func NewVMObject(c *VMClass) *VMObject {
+	x := &VMObject{class: c}
+	x.VMInit(x)
+	x.VMRegister()
+	return x
+}
+
+// SetClass задает тип объекта, созданного через Новый, до вызова VMRegister
+func (x *VMObject) SetClass(c *VMClass) { x.class = c }
+
+func (x *VMObject) Class() *VMClass { return x.class }
+
+func (x *VMObject) VMTypeString() string {
+	if x.class == nil {
+		if x.name != "" {
+			return x.name
+		}
+		return "Объект"
+	}
+	return x.class.Name
+}
+
+func copyFieldValue(v VMValue) VMValue {
+	switch vv := v.(type) {
+	case VMSlice:
+		return vv.CopyRecursive()
+	case VMStringMap:
+		return vv.CopyRecursive()
+	}
+	return v
+}
+
+func (x *VMObject) VMRegister() {
+	if x.class == nil {
+		return
+	}
+	x.fields = make(map[int]VMValue, len(x.class.Fields))
+	for i, f := range x.class.Fields {
+		x.fields[f] = copyFieldValue(x.class.Defaults[i])
+	}
+
+	ctor := names.UniqueNames.Set(ClassConstructorName)
+	for id, m := range x.class.Methods {
+		f := x.bind(m)
+		if id == ctor {
+			x.VMRegisterConstructor(func(args VMSlice) error {
+				var rets VMSlice
+				return f(args, &rets)
+			})
+			continue
+		}
+		x.VMRegisterMethod(names.UniqueNames.Get(id), f)
+	}
+}
+
+// bind подставляет объект первым параметром метода. Обертка передает пропущенные параметры
+// методу на языке Гонец без изменений, поэтому не встраивается и отмечена как RegisterScriptFunc
+//
+//go:noinline
+func (x *VMObject) bind(m VMFunc) VMFunc {
+	return func(args VMSlice, rets *VMSlice) error {
+		a := make(VMSlice, 0, len(args)+1)
+		a = append(a, x)
+		a = append(a, args...)
+		return m(a, rets)
+	}
+}
+
+func init() {
+	RegisterScriptFunc((&VMObject{}).bind(nil))
+}
+
+func (x *VMObject) VMIsField(name int) bool {
+	_, ok := x.fields[name]
+	return ok
+}
+
+func (x *VMObject) VMGetField(name int) VMValue {
+	if v, ok := x.fields[name]; ok {
+		return v
+	}
+	panic(fmt.Sprintf("Поле %s не объявлено в типе %s", names.UniqueNames.Get(name), x.VMTypeString()))
+}
+
+func (x *VMObject) VMSetField(name int, val VMValue) {
+	if _, ok := x.fields[name]; !ok {
+		panic(fmt.Sprintf("Поле %s не объявлено в типе %s", names.UniqueNames.Get(name), x.VMTypeString()))
+	}
+	x.fields[name] = val
+}
+
+// OrderedMap возвращает поля объекта в порядке объявления
+func (x *VMObject) OrderedMap() *VMOrderedMap {
+	rv := NewVMOrderedMap()
+	order := x.order
+	if x.class != nil {
+		order = x.class.Fields
+	}
+	for _, f := range order {
+		rv.Set(VMString(names.UniqueNames.Get(f)), x.fields[f])
+	}
+	return rv
+}
+
+func (x *VMObject) EvalBinOp(op VMOperation, y VMOperationer) (VMValue, error) {
+	yy, ok := y.(*VMObject)
+	if !ok {
+		return VMNil, VMErrorIncorrectOperation
+	}
+	switch op {
+	case EQL:
+		return VMBool(x == yy), nil
+	case NEQ:
+		return VMBool(x != yy), nil
+	}
+	return VMNil, VMErrorIncorrectOperation
+}
+
+func (x *VMObject) ConvertToType(nt reflect.Type) (VMValue, error) {
+	switch nt {
+	case ReflectVMString:
+		b, err := x.MarshalJSON()
+		if err != nil {
+			return VMNil, err
+		}
+		return VMString(string(b)), nil
+	case ReflectVMStringMap:
+		return x.OrderedMap().StringMap(), nil
+	case ReflectVMOrderedMap:
+		return x.OrderedMap(), nil
+	}
+	return VMNil, VMErrorNotConverted
+}
+
+func (x *VMObject) String() string {
+	b, err := x.MarshalJSON()
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+func (x *VMObject) MarshalJSON() ([]byte, error) {
+	return x.OrderedMap().MarshalJSON()
+}
+
+func (x *VMObject) BinaryType() VMBinaryType { return VMOBJECT }
+
+// MarshalBinary сохраняет имя типа и поля. Восстановленный объект получает методы,
+// когда привязывается к типу с тем же именем в окружении, см. Bind
+func (x *VMObject) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeBinaryTyped(&buf, VMString(x.VMTypeString())); err != nil {
+		return nil, err
+	}
+	if err := writeBinaryTyped(&buf, x.OrderedMap()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (x *VMObject) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	name, err := readBinaryTyped(buf)
+	if err != nil {
+		return err
+	}
+	sname, ok := name.(VMString)
+	if !ok {
+		return VMErrorNeedString
+	}
+	fv, err := readBinaryTyped(buf)
+	if err != nil {
+		return err
+	}
+	fm, ok := fv.(*VMOrderedMap)
+	if !ok {
+		return VMErrorNeedMap
+	}
+	x.class = nil
+	x.name = string(sname)
+	x.VMInit(x)
+	x.fields = make(map[int]VMValue, len(fm.keys))
+	x.order = make([]int, 0, len(fm.keys))
+	for i, k := range fm.keys {
+		f := names.UniqueNames.Set(string(k.(VMString)))
+		x.fields[f] = fm.vals[i]
+		x.order = append(x.order, f)
+	}
+	return nil
+}
+
+// Bind привязывает восстановленный объект к типу с тем же именем, объявленному в окружении e:
+// объект получает методы типа, а поля, которых в типе нет, отбрасываются
+func (x *VMObject) Bind(e *Env) error {
+	if x.class != nil || x.name == "" {
+		return nil
+	}
+	c, err := e.Class(names.UniqueNames.Set(x.name))
+	if err != nil {
+		return err
+	}
+	saved := x.fields
+	x.class = c
+	x.VMRegister()
+	for f, v := range saved {
+		if x.VMIsField(f) {
+			x.fields[f] = v
+		}
+	}
+	x.name, x.order = "", nil
+	return nil
+}
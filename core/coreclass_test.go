@@ -0,0 +1,105 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/shinanca/gonec/names"
+)
+
+func testClass(name string, field string, method string, ret VMValue) *VMClass {
+	c := NewVMClass(name)
+	c.Fields = []int{names.UniqueNames.Set(field)}
+	c.Defaults = VMSlice{VMInt(0)}
+	m := names.UniqueNames.Set(method)
+	c.Methods[m] = func(args VMSlice, rets *VMSlice) error {
+		rets.Append(ret)
+		return nil
+	}
+	c.NumArgs[m] = 0
+	return c
+}
+
+func TestClassPerEnv(t *testing.T) {
+	id := names.UniqueNames.Set("ТестовыйКласс")
+	a, b := NewEnv(), NewEnv()
+	if err := a.NewEnv().DefineClass(testClass("ТестовыйКласс", "ТестПолеА", "ТестМетод", VMString("а"))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Class(id); err == nil {
+		t.Fatalf("тип одного окружения не должен быть виден в другом")
+	}
+	if err := b.DefineClass(testClass("ТестовыйКласс", "ТестПолеБ", "ТестМетод", VMString("б"))); err != nil {
+		t.Fatal(err)
+	}
+	ca, err := a.Class(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ca.Fields[0] != names.UniqueNames.Set("ТестПолеА") {
+		t.Errorf("тип окружения заменен типом из другого окружения")
+	}
+	if rt, err := a.Type(id); err != nil || rt != ReflectVMObject {
+		t.Errorf("Type() = %v, %v", rt, err)
+	}
+}
+
+func TestObjectBinaryBind(t *testing.T) {
+	id := names.UniqueNames.Set("ТестовыйТовар")
+	f1, f2 := names.UniqueNames.Set("ТестЦена"), names.UniqueNames.Set("ТестУдаленное")
+	meth := names.UniqueNames.Set("ТестОписание")
+
+	src := NewEnv()
+	c := testClass("ТестовыйТовар", "ТестЦена", "ТестОписание", VMString("старый"))
+	c.Fields = append(c.Fields, f2)
+	c.Defaults = append(c.Defaults, VMInt(0))
+	if err := src.DefineClass(c); err != nil {
+		t.Fatal(err)
+	}
+	obj := NewVMObject(c)
+	obj.VMSetField(f1, VMInt(30))
+	obj.VMSetField(f2, VMInt(1))
+	b, err := obj.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := VMOBJECT.ParseBinary(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := v.(*VMObject)
+	if got.VMTypeString() != "ТестовыйТовар" || got.VMGetField(f1) != VMInt(30) {
+		t.Fatalf("восстановлен %s %v", got.VMTypeString(), got)
+	}
+	if got.String() != obj.String() {
+		t.Errorf("порядок полей: %v, want %v", got, obj)
+	}
+
+	dst := NewEnv()
+	if err := got.Bind(dst); err == nil {
+		t.Errorf("привязка к необъявленному типу должна давать ошибку")
+	}
+	if err := dst.DefineClass(testClass("ТестовыйТовар", "ТестЦена", "ТестОписание", VMString("новый"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := got.Bind(dst); err != nil {
+		t.Fatal(err)
+	}
+	if got.VMGetField(f1) != VMInt(30) {
+		t.Errorf("значение поля потеряно: %v", got.VMGetField(f1))
+	}
+	if got.VMIsField(f2) {
+		t.Errorf("поле, которого нет в типе, должно отбрасываться")
+	}
+	m, ok := got.VMGetMethod(meth)
+	if !ok {
+		t.Fatalf("метод типа окружения не привязан")
+	}
+	var rets VMSlice
+	if err := m(VMSlice{}, &rets); err != nil || rets[0] != VMString("новый") {
+		t.Errorf("метод вернул %v, %v", rets, err)
+	}
+	if c, _ := dst.Class(id); got.Class() != c {
+		t.Errorf("объект привязан не к типу окружения")
+	}
+}
@@ -0,0 +1,418 @@
+package core
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Методы высшего порядка для массивов и структур. Функции обратного вызова - обычные функции Гонца,
+// предикаты должны возвращать Булево.
+//
+// Массив передается по значению, поэтому методы, изменяющие его длину (Вставить, Удалить, Очистить),
+// возвращают измененный массив, который нужно присвоить переменной:
+//
+//	м = м.Вставить(0, "первый")
+
+// callVMFunc вызывает функцию Гонца и возвращает ее первое значение
+func callVMFunc(f VMFunc, args ...VMValue) (VMValue, error) {
+	var rets VMSlice
+	if err := f(VMSlice(args), &rets); err != nil {
+		return VMNil, err
+	}
+	if len(rets) == 0 {
+		return VMNil, nil
+	}
+	return rets[0], nil
+}
+
+// callVMPredicate вызывает функцию Гонца, которая должна вернуть Булево
+func callVMPredicate(f VMFunc, args ...VMValue) (bool, error) {
+	v, err := callVMFunc(f, args...)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(VMBooler)
+	if !ok {
+		return false, VMErrorNeedBool
+	}
+	return b.Bool(), nil
+}
+
+// ValueByPath возвращает значение по пути из имен полей структур и индексов массивов через точку,
+// например "Адрес.Город" или "Строки.0.Сумма". Отсутствующее поле дает Неопределено.
+func ValueByPath(v VMValue, path string) (VMValue, error) {
+	if path == "" {
+		return v, nil
+	}
+	for _, p := range strings.Split(path, ".") {
+		switch vv := v.(type) {
+		case VMStringMap:
+			var ok bool
+			if v, ok = vv[p]; !ok {
+				return VMNil, nil
+			}
+		case VMSlice:
+			i, err := strconv.Atoi(p)
+			if err != nil {
+				return VMNil, VMErrorNeedInt
+			}
+			if i < 0 {
+				i += len(vv)
+			}
+			if i < 0 || i >= len(vv) {
+				return VMNil, VMErrorIndexOutOfBoundary
+			}
+			v = vv[i]
+		case VMNilType:
+			return VMNil, nil
+		default:
+			return VMNil, VMErrorNeedMap
+		}
+	}
+	return v, nil
+}
+
+// keyFunc возвращает функцию получения ключа: по функции Гонца или по пути к полю
+func keyFunc(key VMValue) (func(v VMValue) (VMValue, error), error) {
+	switch k := key.(type) {
+	case VMFunc:
+		return func(v VMValue) (VMValue, error) { return callVMFunc(k, v) }, nil
+	case VMString:
+		return func(v VMValue) (VMValue, error) { return ValueByPath(v, string(k)) }, nil
+	}
+	return nil, VMErrorNeedFuncOrPath
+}
+
+// Вставить(Индекс, Значение) вставляет значение перед элементом с индексом,
+// индекс может быть равен длине массива - тогда значение добавляется в конец
+func (x VMSlice) Вставить(p VMInt, v VMValue, rets *VMSlice) error {
+	if int(p) < 0 || int(p) > len(x) {
+		return VMErrorIndexOutOfBoundary
+	}
+	rv := make(VMSlice, 0, len(x)+1)
+	rv = append(rv, x[:p]...)
+	rv = append(rv, v)
+	rv = append(rv, x[p:]...)
+	rets.Append(rv)
+	return nil
+}
+
+// Удалить(Индекс) возвращает массив без элемента с индексом, отрицательный индекс считается с конца
+func (x VMSlice) Удалить(p VMInt, rets *VMSlice) error {
+	i := int(p)
+	if i < 0 {
+		i += len(x)
+	}
+	if i < 0 || i >= len(x) {
+		return VMErrorIndexOutOfBoundary
+	}
+	rv := make(VMSlice, 0, len(x)-1)
+	rv = append(rv, x[:i]...)
+	rv = append(rv, x[i+1:]...)
+	rets.Append(rv)
+	return nil
+}
+
+func (x VMSlice) Очистить(rets *VMSlice) error {
+	rets.Append(VMSlice{})
+	return nil
+}
+
+// Сортировать([Сравнение][, Убывание]) сортирует массив на месте, порядок равных элементов сохраняется.
+// Сравнение - функция двух элементов, возвращающая Истина, если первый меньше второго
+// (или число меньше нуля), либо путь к полю элементов-структур, например "Товар.Наименование".
+func (x VMSlice) Сортировать(args VMSlice, rets *VMSlice) error {
+	desc := false
+	if len(args) > 1 {
+		b, ok := args[1].(VMBool)
+		if !ok {
+			return VMErrorNeedBool
+		}
+		desc = bool(b)
+	}
+
+	less := func(a, b VMValue) (bool, error) { return SortLessVMValues(a, b), nil }
+	if len(args) > 0 {
+		switch c := args[0].(type) {
+		case VMFunc:
+			less = func(a, b VMValue) (bool, error) {
+				v, err := callVMFunc(c, a, b)
+				if err != nil {
+					return false, err
+				}
+				switch vv := v.(type) {
+				case VMBool:
+					return bool(vv), nil
+				case VMNumberer:
+					return vv.DecNum().num.IsNegative(), nil
+				}
+				return false, VMErrorNeedBool
+			}
+		case VMString:
+			less = func(a, b VMValue) (bool, error) {
+				ka, err := ValueByPath(a, string(c))
+				if err != nil {
+					return false, err
+				}
+				kb, err := ValueByPath(b, string(c))
+				if err != nil {
+					return false, err
+				}
+				return SortLessVMValues(ka, kb), nil
+			}
+		case VMNilType:
+		default:
+			return VMErrorNeedFuncOrPath
+		}
+	}
+
+	var err error
+	sort.SliceStable(x, func(i, j int) bool {
+		if err != nil {
+			return false
+		}
+		a, b := x[i], x[j]
+		if desc {
+			a, b = b, a
+		}
+		var r bool
+		r, err = less(a, b)
+		return r
+	})
+	return err
+}
+
+// Отобрать(Условие) возвращает новый массив из элементов, для которых функция вернула Истина
+func (x VMSlice) Отобрать(f VMFunc, rets *VMSlice) error {
+	rv := make(VMSlice, 0)
+	for _, v := range x {
+		ok, err := callVMPredicate(f, v)
+		if err != nil {
+			return err
+		}
+		if ok {
+			rv = append(rv, v)
+		}
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// Преобразовать(Функция) возвращает новый массив из результатов функции для каждого элемента
+func (x VMSlice) Преобразовать(f VMFunc, rets *VMSlice) error {
+	rv := make(VMSlice, len(x))
+	for i, v := range x {
+		r, err := callVMFunc(f, v)
+		if err != nil {
+			return err
+		}
+		rv[i] = r
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// Свернуть(Функция[, НачальноеЗначение]) последовательно вызывает Функция(Накопитель, Элемент).
+// Без начального значения накопителем становится первый элемент, для пустого массива возвращается Неопределено.
+func (x VMSlice) Свернуть(f VMFunc, rest VMSlice, rets *VMSlice) error {
+	src := x
+	var acc VMValue = VMNil
+	if len(rest) > 0 {
+		acc = rest[0]
+	} else if len(src) > 0 {
+		acc, src = src[0], src[1:]
+	}
+	for _, v := range src {
+		r, err := callVMFunc(f, acc, v)
+		if err != nil {
+			return err
+		}
+		acc = r
+	}
+	rets.Append(acc)
+	return nil
+}
+
+// Сгруппировать(Ключ) возвращает структуру, где для каждого строкового представления ключа
+// хранится массив элементов. Ключ - функция элемента или путь к полю.
+func (x VMSlice) Сгруппировать(key VMValue, rets *VMSlice) error {
+	kf, err := keyFunc(key)
+	if err != nil {
+		return err
+	}
+	rv := make(VMStringMap)
+	for _, v := range x {
+		k, err := kf(v)
+		if err != nil {
+			return err
+		}
+		ks := ""
+		if s, ok := k.(VMStringer); ok {
+			ks = s.String()
+		}
+		g, _ := rv[ks].(VMSlice)
+		rv[ks] = append(g, v)
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// Любой(Условие) возвращает Истина, если условие выполняется хотя бы для одного элемента
+func (x VMSlice) Любой(f VMFunc, rets *VMSlice) error {
+	for _, v := range x {
+		ok, err := callVMPredicate(f, v)
+		if err != nil {
+			return err
+		}
+		if ok {
+			rets.Append(VMBool(true))
+			return nil
+		}
+	}
+	rets.Append(VMBool(false))
+	return nil
+}
+
+// Все(Условие) возвращает Истина, если условие выполняется для всех элементов (и для пустого массива)
+func (x VMSlice) Все(f VMFunc, rets *VMSlice) error {
+	for _, v := range x {
+		ok, err := callVMPredicate(f, v)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			rets.Append(VMBool(false))
+			return nil
+		}
+	}
+	rets.Append(VMBool(true))
+	return nil
+}
+
+// sliceBound находит границу среза: индекс (отрицательный - с конца) или первый элемент
+// начиная с from, для которого выполняется условие; если такого нет - длина массива
+func (x VMSlice) sliceBound(b VMValue, from int) (int, error) {
+	switch bb := b.(type) {
+	case VMInt:
+		i := int(bb)
+		if i < 0 {
+			i += len(x)
+		}
+		if i < 0 {
+			i = 0
+		}
+		if i > len(x) {
+			i = len(x)
+		}
+		return i, nil
+	case VMFunc:
+		for i := from; i < len(x); i++ {
+			ok, err := callVMPredicate(bb, x[i])
+			if err != nil {
+				return 0, err
+			}
+			if ok {
+				return i, nil
+			}
+		}
+		return len(x), nil
+	}
+	return 0, VMErrorNeedFuncOrIndex
+}
+
+// Срез(Начало[, Конец]) возвращает новый массив из элементов от Начало включительно до Конец не включительно.
+// Границы - индексы или условия: начало - первый элемент, для которого условие истинно,
+// конец - первый следующий за началом элемент, для которого истинно условие конца.
+func (x VMSlice) Срез(args VMSlice, rets *VMSlice) error {
+	from, err := x.sliceBound(args[0], 0)
+	if err != nil {
+		return err
+	}
+	to := len(x)
+	if len(args) > 1 {
+		start := from
+		if _, ok := args[1].(VMFunc); ok && start < len(x) {
+			start++
+		}
+		if to, err = x.sliceBound(args[1], start); err != nil {
+			return err
+		}
+	}
+	rv := make(VMSlice, 0)
+	if from < to {
+		rv = append(rv, x[from:to]...)
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// Вставить(Ключ, Значение) добавляет или заменяет значение в структуре
+func (x VMStringMap) Вставить(key VMString, v VMValue, rets *VMSlice) error {
+	x[string(key)] = v
+	return nil
+}
+
+func (x VMStringMap) Очистить(rets *VMSlice) error {
+	for k := range x {
+		delete(x, k)
+	}
+	return nil
+}
+
+// Свойство(Ключ[, ПоУмолчанию]) возвращает значение по ключу или пути через точку,
+// а если его нет - значение по умолчанию (Неопределено)
+func (x VMStringMap) Свойство(key VMString, rest VMSlice, rets *VMSlice) error {
+	var def VMValue = VMNil
+	if len(rest) > 0 {
+		def = rest[0]
+	}
+	if v, ok := x[string(key)]; ok {
+		rets.Append(v)
+		return nil
+	}
+	if strings.Contains(string(key), ".") {
+		v, err := ValueByPath(x, string(key))
+		if err == nil && v != VMNil {
+			rets.Append(v)
+			return nil
+		}
+	}
+	rets.Append(def)
+	return nil
+}
+
+// ВставитьВсе(Структура) добавляет все значения другой структуры, существующие ключи заменяются
+func (x VMStringMap) ВставитьВсе(y VMStringMap, rets *VMSlice) error {
+	for k, v := range y {
+		x[k] = v
+	}
+	return nil
+}
+
+// Merge рекурсивно сливает структуру y в x: вложенные структуры сливаются,
+// остальные значения заменяются копиями значений из y
+func (x VMStringMap) Merge(y VMStringMap) {
+	for k, v := range y {
+		if ym, ok := v.(VMStringMap); ok {
+			if xm, ok := x[k].(VMStringMap); ok {
+				xm.Merge(ym)
+				continue
+			}
+		}
+		switch vv := v.(type) {
+		case VMSlice:
+			x[k] = vv.CopyRecursive()
+		case VMStringMap:
+			x[k] = vv.CopyRecursive()
+		default:
+			x[k] = v
+		}
+	}
+}
+
+// Слить(Структура) выполняет глубокое слияние другой структуры с этой
+func (x VMStringMap) Слить(y VMStringMap, rets *VMSlice) error {
+	x.Merge(y)
+	return nil
+}
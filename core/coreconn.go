@@ -436,7 +436,8 @@ func (x *VMConn) Запрос(vsm VMStringMap, rets *VMSlice) error {
 		return VMErrorNonHTTPMethod
 	}
 
-	var m, p, b VMString
+	var m, p VMString
+	var b []byte
 	var h, vals VMStringMap
 
 	if v, ok := vsm["Метод"]; ok {
@@ -450,8 +451,9 @@ func (x *VMConn) Запрос(vsm VMStringMap, rets *VMSlice) error {
 		}
 	}
 	if v, ok := vsm["Тело"]; ok {
-		if b, ok = v.(VMString); !ok {
-			return VMErrorNeedString
+		var err error
+		if b, err = binaryArg(v); err != nil {
+			return err
 		}
 	}
 	if v, ok := vsm["Заголовки"]; ok {
@@ -465,7 +467,7 @@ func (x *VMConn) Запрос(vsm VMStringMap, rets *VMSlice) error {
 		}
 	}
 
-	r, err := x.HttpReq(m, p, []byte(b), h, vals)
+	r, err := x.HttpReq(m, p, b, h, vals)
 	if err != nil {
 		return err
 	}
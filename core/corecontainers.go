@@ -0,0 +1,850 @@
+package core
+
+import (
+	"container/heap"
+	"encoding/json"
+	"sync"
+)
+
+// Коллекции Множество, Очередь, Дек и ОчередьСПриоритетом.
+// Все они перебираются в цикле Для каждого (по снимку содержимого) и сериализуются как массив элементов.
+// Последний параметр конструктора Потокобезопасная = Истина включает блокировку,
+// чтобы коллекцию можно было использовать из нескольких горутин.
+
+// vmLocker необязательная блокировка коллекции
+type vmLocker struct {
+	mu   sync.Mutex
+	safe bool
+}
+
+func (x *vmLocker) lock() {
+	if x.safe {
+		x.mu.Lock()
+	}
+}
+
+func (x *vmLocker) unlock() {
+	if x.safe {
+		x.mu.Unlock()
+	}
+}
+
+// containerArgs разбирает параметры конструктора: начальные элементы (массив) и признак потокобезопасности
+func containerArgs(args VMSlice) (VMSlice, bool, error) {
+	var items VMSlice
+	safe := false
+	for i, a := range args {
+		switch v := a.(type) {
+		case VMSlicer:
+			if i > 0 {
+				return nil, false, VMErrorNeedBool
+			}
+			items = v.Slice()
+		case VMBool:
+			if i != len(args)-1 {
+				return nil, false, VMErrorNeedSlice
+			}
+			safe = bool(v)
+		default:
+			return nil, false, VMErrorNeedSlice
+		}
+	}
+	return items, safe, nil
+}
+
+// sliceIterator перебирает снимок элементов коллекции
+type sliceIterator struct {
+	items VMSlice
+	pos   int
+}
+
+func (x *sliceIterator) VMTypeString() string { return "ИтераторКоллекции" }
+
+func (x *sliceIterator) Interface() interface{} { return x }
+
+func (x *sliceIterator) Next() (VMValue, bool, error) {
+	if x.pos >= len(x.items) {
+		return VMNil, false, nil
+	}
+	x.pos++
+	return x.items[x.pos-1], true, nil
+}
+
+// unmarshalItems разбирает сериализованный массив элементов
+func unmarshalItems(data []byte) (VMSlice, error) {
+	var sl VMSlice
+	if err := (&sl).UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// VMSet множество уникальных значений в порядке добавления.
+// Равенство элементов определяется так же, как равенство ключей соответствия.
+type VMSet struct {
+	VMMetaObj
+	vmLocker
+
+	m *VMOrderedMap
+}
+
+func NewVMSet() *VMSet {
+	x := &VMSet{}
+	x.VMInit(x)
+	x.VMRegister()
+	return x
+}
+
+func (x *VMSet) VMTypeString() string { return "Множество" }
+
+func (x *VMSet) VMRegister() {
+	x.m = NewVMOrderedMap()
+
+	// Новый Множество([Массив][, Потокобезопасное])
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		items, safe, err := containerArgs(args)
+		if err != nil {
+			return err
+		}
+		x.safe = safe
+		for _, v := range items {
+			if err := x.m.Set(v, VMNil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	x.VMRegisterMethod("Добавить", VMFuncNParams(1, x.Добавить))
+	x.VMRegisterMethod("Удалить", VMFuncNParams(1, x.Удалить))
+	x.VMRegisterMethod("Содержит", VMFuncNParams(1, x.Содержит))
+	x.VMRegisterMethod("Количество", VMFuncZeroParams(x.Количество))
+	x.VMRegisterMethod("Очистить", VMFuncZeroParams(x.Очистить))
+	x.VMRegisterMethod("ВМассив", VMFuncZeroParams(x.ВМассив))
+	x.VMRegisterMethod("Объединение", VMFuncOneParam(x.Объединение))
+	x.VMRegisterMethod("Пересечение", VMFuncOneParam(x.Пересечение))
+	x.VMRegisterMethod("Разность", VMFuncOneParam(x.Разность))
+	x.VMRegisterMethod("СимметричнаяРазность", VMFuncOneParam(x.СимметричнаяРазность))
+	x.VMRegisterMethod("ЭтоПодмножество", VMFuncOneParam(x.ЭтоПодмножество))
+}
+
+// Items возвращает элементы множества в порядке добавления
+func (x *VMSet) Items() VMSlice {
+	x.lock()
+	defer x.unlock()
+	return append(VMSlice{}, x.m.keys...)
+}
+
+func (x *VMSet) Has(v VMValue) (bool, error) {
+	x.lock()
+	defer x.unlock()
+	_, ok, err := x.m.Get(v)
+	return ok, err
+}
+
+// combine возвращает новое множество из элементов x, наличие которых в y совпадает с want,
+// и (если addY) элементов y, которых нет в x
+func (x *VMSet) combine(y *VMSet, want, addY bool) (*VMSet, error) {
+	rv := NewVMSet()
+	for _, v := range x.Items() {
+		ok, err := y.Has(v)
+		if err != nil {
+			return nil, err
+		}
+		if ok == want {
+			rv.m.Set(v, VMNil)
+		}
+	}
+	if addY {
+		for _, v := range y.Items() {
+			ok, err := x.Has(v)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				rv.m.Set(v, VMNil)
+			}
+		}
+	}
+	return rv, nil
+}
+
+func (x *VMSet) Union(y *VMSet) (*VMSet, error) {
+	rv := NewVMSet()
+	for _, v := range append(x.Items(), y.Items()...) {
+		if err := rv.m.Set(v, VMNil); err != nil {
+			return nil, err
+		}
+	}
+	return rv, nil
+}
+
+func (x *VMSet) Intersection(y *VMSet) (*VMSet, error) { return x.combine(y, true, false) }
+
+func (x *VMSet) Difference(y *VMSet) (*VMSet, error) { return x.combine(y, false, false) }
+
+func (x *VMSet) SymmetricDifference(y *VMSet) (*VMSet, error) { return x.combine(y, false, true) }
+
+// IsSubset проверяет, что все элементы x есть в y
+func (x *VMSet) IsSubset(y *VMSet) (bool, error) {
+	for _, v := range x.Items() {
+		ok, err := y.Has(v)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (x *VMSet) Добавить(args VMSlice, rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	return x.m.Set(args[0], VMNil)
+}
+
+func (x *VMSet) Удалить(args VMSlice, rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	return x.m.Delete(args[0])
+}
+
+func (x *VMSet) Содержит(args VMSlice, rets *VMSlice) error {
+	ok, err := x.Has(args[0])
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBool(ok))
+	return nil
+}
+
+func (x *VMSet) Количество(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	rets.Append(x.m.Length())
+	return nil
+}
+
+func (x *VMSet) Очистить(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	x.m = NewVMOrderedMap()
+	return nil
+}
+
+func (x *VMSet) ВМассив(rets *VMSlice) error {
+	rets.Append(x.Items())
+	return nil
+}
+
+func (x *VMSet) Объединение(y *VMSet, rets *VMSlice) error {
+	rv, err := x.Union(y)
+	if err != nil {
+		return err
+	}
+	rets.Append(rv)
+	return nil
+}
+
+func (x *VMSet) Пересечение(y *VMSet, rets *VMSlice) error {
+	rv, err := x.Intersection(y)
+	if err != nil {
+		return err
+	}
+	rets.Append(rv)
+	return nil
+}
+
+func (x *VMSet) Разность(y *VMSet, rets *VMSlice) error {
+	rv, err := x.Difference(y)
+	if err != nil {
+		return err
+	}
+	rets.Append(rv)
+	return nil
+}
+
+func (x *VMSet) СимметричнаяРазность(y *VMSet, rets *VMSlice) error {
+	rv, err := x.SymmetricDifference(y)
+	if err != nil {
+		return err
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// ЭтоПодмножество(Множество) проверяет, что все элементы этого множества входят в другое
+func (x *VMSet) ЭтоПодмножество(y *VMSet, rets *VMSlice) error {
+	ok, err := x.IsSubset(y)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBool(ok))
+	return nil
+}
+
+// EvalBinOp: + объединение, * пересечение, - разность, % симметричная разность,
+// = и <> сравнение, <= и >= проверка вхождения одного множества в другое
+func (x *VMSet) EvalBinOp(op VMOperation, y VMOperationer) (VMValue, error) {
+	yy, ok := y.(*VMSet)
+	if !ok {
+		return VMNil, VMErrorIncorrectOperation
+	}
+	switch op {
+	case ADD, OR:
+		return x.Union(yy)
+	case MUL, AND:
+		return x.Intersection(yy)
+	case SUB:
+		return x.Difference(yy)
+	case REM:
+		return x.SymmetricDifference(yy)
+	case EQL, NEQ:
+		sub, err := x.IsSubset(yy)
+		if err != nil {
+			return VMNil, err
+		}
+		eq := sub && len(x.Items()) == len(yy.Items())
+		return VMBool(eq == (op == EQL)), nil
+	case LEQ:
+		sub, err := x.IsSubset(yy)
+		return VMBool(sub), err
+	case GEQ:
+		sub, err := yy.IsSubset(x)
+		return VMBool(sub), err
+	}
+	return VMNil, VMErrorIncorrectOperation
+}
+
+func (x *VMSet) Iterator() (VMIterator, error) {
+	return &sliceIterator{items: x.Items()}, nil
+}
+
+func (x *VMSet) BinaryType() VMBinaryType { return VMSET }
+
+func (x *VMSet) MarshalBinary() ([]byte, error) { return x.Items().MarshalBinary() }
+
+func (x *VMSet) UnmarshalBinary(data []byte) error {
+	sl, err := unmarshalItems(data)
+	if err != nil {
+		return err
+	}
+	x.lock()
+	defer x.unlock()
+	x.m = NewVMOrderedMap()
+	for _, v := range sl {
+		if err := x.m.Set(v, VMNil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *VMSet) MarshalJSON() ([]byte, error) { return json.Marshal(x.Items()) }
+
+func (x *VMSet) String() string { return x.Items().String() }
+
+// vmRing кольцевой буфер с добавлением и извлечением с обоих концов за O(1)
+type vmRing struct {
+	buf        VMSlice
+	head, size int
+}
+
+func (r *vmRing) grow() {
+	n := len(r.buf) * 2
+	if n == 0 {
+		n = 8
+	}
+	nb := make(VMSlice, n)
+	for i := 0; i < r.size; i++ {
+		nb[i] = r.buf[(r.head+i)%len(r.buf)]
+	}
+	r.buf, r.head = nb, 0
+}
+
+func (r *vmRing) pushBack(v VMValue) {
+	if r.size == len(r.buf) {
+		r.grow()
+	}
+	r.buf[(r.head+r.size)%len(r.buf)] = v
+	r.size++
+}
+
+func (r *vmRing) pushFront(v VMValue) {
+	if r.size == len(r.buf) {
+		r.grow()
+	}
+	r.head = (r.head - 1 + len(r.buf)) % len(r.buf)
+	r.buf[r.head] = v
+	r.size++
+}
+
+func (r *vmRing) popFront() (VMValue, bool) {
+	if r.size == 0 {
+		return VMNil, false
+	}
+	v := r.buf[r.head]
+	r.buf[r.head] = nil
+	r.head = (r.head + 1) % len(r.buf)
+	r.size--
+	return v, true
+}
+
+func (r *vmRing) popBack() (VMValue, bool) {
+	if r.size == 0 {
+		return VMNil, false
+	}
+	i := (r.head + r.size - 1) % len(r.buf)
+	v := r.buf[i]
+	r.buf[i] = nil
+	r.size--
+	return v, true
+}
+
+func (r *vmRing) front() VMValue {
+	if r.size == 0 {
+		return VMNil
+	}
+	return r.buf[r.head]
+}
+
+func (r *vmRing) back() VMValue {
+	if r.size == 0 {
+		return VMNil
+	}
+	return r.buf[(r.head+r.size-1)%len(r.buf)]
+}
+
+func (r *vmRing) items() VMSlice {
+	rv := make(VMSlice, r.size)
+	for i := range rv {
+		rv[i] = r.buf[(r.head+i)%len(r.buf)]
+	}
+	return rv
+}
+
+// VMDeque двусторонняя очередь. Извлечение из пустой очереди возвращает Неопределено.
+type VMDeque struct {
+	VMMetaObj
+	vmLocker
+
+	r vmRing
+}
+
+func NewVMDeque() *VMDeque {
+	x := &VMDeque{}
+	x.VMInit(x)
+	x.VMRegister()
+	return x
+}
+
+func (x *VMDeque) VMTypeString() string { return "Дек" }
+
+func (x *VMDeque) VMRegister() {
+	// Новый Дек([Массив][, Потокобезопасный])
+	x.VMRegisterConstructor(x.construct)
+
+	x.VMRegisterMethod("ДобавитьВНачало", VMFuncNParams(1, x.ДобавитьВНачало))
+	x.VMRegisterMethod("ДобавитьВКонец", VMFuncNParams(1, x.ДобавитьВКонец))
+	x.VMRegisterMethod("ИзвлечьПервый", VMFuncZeroParams(x.ИзвлечьПервый))
+	x.VMRegisterMethod("ИзвлечьПоследний", VMFuncZeroParams(x.ИзвлечьПоследний))
+	x.VMRegisterMethod("Первый", VMFuncZeroParams(x.Первый))
+	x.VMRegisterMethod("Последний", VMFuncZeroParams(x.Последний))
+	x.registerCommon()
+}
+
+func (x *VMDeque) construct(args VMSlice) error {
+	items, safe, err := containerArgs(args)
+	if err != nil {
+		return err
+	}
+	x.safe = safe
+	for _, v := range items {
+		x.r.pushBack(v)
+	}
+	return nil
+}
+
+func (x *VMDeque) registerCommon() {
+	x.VMRegisterMethod("Количество", VMFuncZeroParams(x.Количество))
+	x.VMRegisterMethod("Пустой", VMFuncZeroParams(x.Пустой))
+	x.VMRegisterMethod("Очистить", VMFuncZeroParams(x.Очистить))
+	x.VMRegisterMethod("ВМассив", VMFuncZeroParams(x.ВМассив))
+}
+
+func (x *VMDeque) Items() VMSlice {
+	x.lock()
+	defer x.unlock()
+	return x.r.items()
+}
+
+func (x *VMDeque) ДобавитьВНачало(args VMSlice, rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	x.r.pushFront(args[0])
+	return nil
+}
+
+func (x *VMDeque) ДобавитьВКонец(args VMSlice, rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	x.r.pushBack(args[0])
+	return nil
+}
+
+func (x *VMDeque) ИзвлечьПервый(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	v, _ := x.r.popFront()
+	rets.Append(v)
+	return nil
+}
+
+func (x *VMDeque) ИзвлечьПоследний(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	v, _ := x.r.popBack()
+	rets.Append(v)
+	return nil
+}
+
+func (x *VMDeque) Первый(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	rets.Append(x.r.front())
+	return nil
+}
+
+func (x *VMDeque) Последний(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	rets.Append(x.r.back())
+	return nil
+}
+
+func (x *VMDeque) Количество(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	rets.Append(VMInt(x.r.size))
+	return nil
+}
+
+func (x *VMDeque) Пустой(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	rets.Append(VMBool(x.r.size == 0))
+	return nil
+}
+
+func (x *VMDeque) Очистить(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	x.r = vmRing{}
+	return nil
+}
+
+func (x *VMDeque) ВМассив(rets *VMSlice) error {
+	rets.Append(x.Items())
+	return nil
+}
+
+func (x *VMDeque) Iterator() (VMIterator, error) {
+	return &sliceIterator{items: x.Items()}, nil
+}
+
+func (x *VMDeque) BinaryType() VMBinaryType { return VMDEQUE }
+
+func (x *VMDeque) MarshalBinary() ([]byte, error) { return x.Items().MarshalBinary() }
+
+func (x *VMDeque) UnmarshalBinary(data []byte) error {
+	sl, err := unmarshalItems(data)
+	if err != nil {
+		return err
+	}
+	x.lock()
+	defer x.unlock()
+	x.r = vmRing{}
+	for _, v := range sl {
+		x.r.pushBack(v)
+	}
+	return nil
+}
+
+func (x *VMDeque) MarshalJSON() ([]byte, error) { return json.Marshal(x.Items()) }
+
+func (x *VMDeque) String() string { return x.Items().String() }
+
+// VMQueue очередь "первым пришел - первым ушел" на основе дека
+type VMQueue struct {
+	VMDeque
+}
+
+func NewVMQueue() *VMQueue {
+	x := &VMQueue{}
+	x.VMInit(x)
+	x.VMRegister()
+	return x
+}
+
+func (x *VMQueue) VMTypeString() string { return "Очередь" }
+
+func (x *VMQueue) VMRegister() {
+	// Новый Очередь([Массив][, Потокобезопасная])
+	x.VMRegisterConstructor(x.construct)
+
+	x.VMRegisterMethod("Добавить", VMFuncNParams(1, x.ДобавитьВКонец))
+	x.VMRegisterMethod("Извлечь", VMFuncZeroParams(x.ИзвлечьПервый))
+	x.VMRegisterMethod("Первый", VMFuncZeroParams(x.Первый))
+	x.registerCommon()
+	x.VMRegisterMethod("Пустая", VMFuncZeroParams(x.Пустой))
+}
+
+func (x *VMQueue) BinaryType() VMBinaryType { return VMQUEUE }
+
+// vmHeap двоичная куча для очереди с приоритетом, less сообщает, что первый элемент извлекается раньше
+type vmHeap struct {
+	items VMSlice
+	less  func(a, b VMValue) (bool, error)
+	err   error // первая ошибка функции сравнения
+}
+
+func (h *vmHeap) Len() int { return len(h.items) }
+
+func (h *vmHeap) Less(i, j int) bool {
+	if h.err != nil {
+		return false
+	}
+	r, err := h.less(h.items[i], h.items[j])
+	if err != nil {
+		h.err = err
+	}
+	return r
+}
+
+func (h *vmHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *vmHeap) Push(v interface{}) { h.items = append(h.items, v.(VMValue)) }
+
+func (h *vmHeap) Pop() interface{} {
+	n := len(h.items) - 1
+	v := h.items[n]
+	h.items[n] = nil
+	h.items = h.items[:n]
+	return v
+}
+
+// takeErr возвращает и сбрасывает ошибку сравнения
+func (h *vmHeap) takeErr() error {
+	err := h.err
+	h.err = nil
+	return err
+}
+
+// VMPriorityQueue очередь с приоритетом. По умолчанию первым извлекается наименьший элемент,
+// функция сравнения Сравнение(А, Б) должна вернуть Истина, если А извлекается раньше Б.
+// Функция сравнения не сериализуется: после загрузки используется порядок по умолчанию.
+//
+// Функция сравнения вызывается без блокировки, поэтому она может обращаться к самой очереди
+// или ждать других потоков: изменения выполняются над копией кучи и сохраняются,
+// только если очередь за это время не менялась, иначе повторяются.
+type VMPriorityQueue struct {
+	VMMetaObj
+	vmLocker
+
+	h      vmHeap
+	byFunc bool   // задана функция сравнения Гонца
+	ver    uint64 // номер изменения кучи
+}
+
+func NewVMPriorityQueue() *VMPriorityQueue {
+	x := &VMPriorityQueue{}
+	x.VMInit(x)
+	x.VMRegister()
+	return x
+}
+
+func (x *VMPriorityQueue) VMTypeString() string { return "ОчередьСПриоритетом" }
+
+func defaultHeapLess(a, b VMValue) (bool, error) { return SortLessVMValues(a, b), nil }
+
+func (x *VMPriorityQueue) VMRegister() {
+	x.h.less = defaultHeapLess
+
+	// Новый ОчередьСПриоритетом([Сравнение][, Массив][, Потокобезопасная])
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) > 0 {
+			if f, ok := args[0].(VMFunc); ok {
+				x.h.less = func(a, b VMValue) (bool, error) { return callVMPredicate(f, a, b) }
+				x.byFunc = true
+				args = args[1:]
+			}
+		}
+		items, safe, err := containerArgs(args)
+		if err != nil {
+			return err
+		}
+		x.safe = safe
+		x.h.items = append(x.h.items, items...)
+		heap.Init(&x.h)
+		return x.h.takeErr()
+	})
+
+	x.VMRegisterMethod("Добавить", VMFuncNParams(1, x.Добавить))
+	x.VMRegisterMethod("Извлечь", VMFuncZeroParams(x.Извлечь))
+	x.VMRegisterMethod("Первый", VMFuncZeroParams(x.Первый))
+	x.VMRegisterMethod("Количество", VMFuncZeroParams(x.Количество))
+	x.VMRegisterMethod("Пустая", VMFuncZeroParams(x.Пустая))
+	x.VMRegisterMethod("Очистить", VMFuncZeroParams(x.Очистить))
+	x.VMRegisterMethod("ВМассив", VMFuncZeroParams(x.ВМассив))
+}
+
+// snapshot возвращает копию кучи и номер ее изменения
+func (x *VMPriorityQueue) snapshot() (vmHeap, uint64) {
+	x.lock()
+	defer x.unlock()
+	return vmHeap{items: append(VMSlice{}, x.h.items...), less: x.h.less}, x.ver
+}
+
+// update выполняет op над кучей. Для функции сравнения Гонца op выполняется над копией без блокировки
+// и сохраняется, если очередь не изменилась, а при ошибке сравнения очередь остается прежней.
+func (x *VMPriorityQueue) update(op func(h *vmHeap)) error {
+	if !x.byFunc {
+		// сравнение по умолчанию не вызывает код Гонца и не возвращает ошибок
+		x.lock()
+		defer x.unlock()
+		op(&x.h)
+		x.ver++
+		return nil
+	}
+	for {
+		h, ver := x.snapshot()
+		op(&h)
+		if err := h.takeErr(); err != nil {
+			return err
+		}
+		x.lock()
+		if x.ver == ver {
+			x.h.items = h.items
+			x.ver++
+			x.unlock()
+			return nil
+		}
+		x.unlock()
+	}
+}
+
+// Items возвращает элементы в порядке извлечения, очередь не изменяется
+func (x *VMPriorityQueue) Items() (VMSlice, error) {
+	h, _ := x.snapshot()
+	rv := make(VMSlice, 0, len(h.items))
+	for h.Len() > 0 {
+		rv = append(rv, heap.Pop(&h).(VMValue))
+	}
+	return rv, h.takeErr()
+}
+
+func (x *VMPriorityQueue) Добавить(args VMSlice, rets *VMSlice) error {
+	return x.update(func(h *vmHeap) { heap.Push(h, args[0]) })
+}
+
+// Извлечь() возвращает и удаляет первый по приоритету элемент, для пустой очереди - Неопределено
+func (x *VMPriorityQueue) Извлечь(rets *VMSlice) error {
+	var v VMValue = VMNil
+	err := x.update(func(h *vmHeap) {
+		v = VMNil
+		if h.Len() > 0 {
+			v = heap.Pop(h).(VMValue)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	rets.Append(v)
+	return nil
+}
+
+func (x *VMPriorityQueue) Первый(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	if x.h.Len() == 0 {
+		rets.Append(VMNil)
+		return nil
+	}
+	rets.Append(x.h.items[0])
+	return nil
+}
+
+func (x *VMPriorityQueue) Количество(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	rets.Append(VMInt(x.h.Len()))
+	return nil
+}
+
+func (x *VMPriorityQueue) Пустая(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	rets.Append(VMBool(x.h.Len() == 0))
+	return nil
+}
+
+func (x *VMPriorityQueue) Очистить(rets *VMSlice) error {
+	x.lock()
+	defer x.unlock()
+	x.h.items = nil
+	x.ver++
+	return nil
+}
+
+func (x *VMPriorityQueue) ВМассив(rets *VMSlice) error {
+	items, err := x.Items()
+	if err != nil {
+		return err
+	}
+	rets.Append(items)
+	return nil
+}
+
+func (x *VMPriorityQueue) Iterator() (VMIterator, error) {
+	items, err := x.Items()
+	if err != nil {
+		return nil, err
+	}
+	return &sliceIterator{items: items}, nil
+}
+
+func (x *VMPriorityQueue) BinaryType() VMBinaryType { return VMPRIORITYQUEUE }
+
+func (x *VMPriorityQueue) MarshalBinary() ([]byte, error) {
+	items, err := x.Items()
+	if err != nil {
+		return nil, err
+	}
+	return items.MarshalBinary()
+}
+
+func (x *VMPriorityQueue) UnmarshalBinary(data []byte) error {
+	sl, err := unmarshalItems(data)
+	if err != nil {
+		return err
+	}
+	return x.update(func(h *vmHeap) {
+		h.items = sl
+		heap.Init(h)
+	})
+}
+
+func (x *VMPriorityQueue) MarshalJSON() ([]byte, error) {
+	items, err := x.Items()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(items)
+}
+
+func (x *VMPriorityQueue) String() string {
+	items, err := x.Items()
+	if err != nil {
+		return err.Error()
+	}
+	return items.String()
+}
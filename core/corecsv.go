@@ -0,0 +1,618 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	iconv "github.com/djimenez/iconv-go"
+)
+
+// параметры чтения и записи CSV, передаются структурой вторым аргументом конструктора:
+//   Разделитель - символ-разделитель полей, по умолчанию ";"
+//   Кавычка - символ, которым обрамляются поля, по умолчанию "
+//   Кодировка - кодировка файла для iconv, например "cp1251", по умолчанию utf-8
+//   Заголовок - первая строка файла содержит имена колонок, по умолчанию Истина
+//   Типизировать - (чтение) преобразовывать числа и даты, по умолчанию Истина
+//   Заголовки - (запись) массив имен колонок, задающий их порядок
+//   РазделительСтрок - (запись) по умолчанию "\r\n"
+//   РазделительДробнойЧасти - (запись) по умолчанию ","
+
+type csvOptions struct {
+	delim     rune
+	quote     rune
+	encoding  string
+	header    bool
+	typed     bool
+	headers   []string
+	eol       string
+	decimalSp string
+}
+
+func csvRuneParam(params VMStringMap, name string, def rune) (rune, error) {
+	v, ok := structParam(params, name)
+	if !ok {
+		return def, nil
+	}
+	s, ok := v.(VMString)
+	if !ok || utf8.RuneCountInString(string(s)) != 1 {
+		return 0, fmt.Errorf("%w: %s, требуется один символ", VMErrorCSVParam, name)
+	}
+	r, _ := utf8.DecodeRuneInString(string(s))
+	return r, nil
+}
+
+func csvStringParam(params VMStringMap, name string, def string) (string, error) {
+	v, ok := structParam(params, name)
+	if !ok {
+		return def, nil
+	}
+	s, ok := v.(VMString)
+	if !ok {
+		return "", fmt.Errorf("%w: %s, требуется строка", VMErrorCSVParam, name)
+	}
+	return string(s), nil
+}
+
+func parseCSVOptions(args VMSlice) (opts csvOptions, err error) {
+	opts = csvOptions{
+		delim:     ';',
+		quote:     '"',
+		header:    true,
+		typed:     true,
+		eol:       "\r\n",
+		decimalSp: ",",
+	}
+	if len(args) < 2 {
+		return
+	}
+	params, ok := args[1].(VMStringMap)
+	if !ok {
+		return opts, VMErrorNeedMap
+	}
+	if opts.delim, err = csvRuneParam(params, "Разделитель", opts.delim); err != nil {
+		return
+	}
+	if opts.quote, err = csvRuneParam(params, "Кавычка", opts.quote); err != nil {
+		return
+	}
+	if opts.encoding, err = csvStringParam(params, "Кодировка", opts.encoding); err != nil {
+		return
+	}
+	if v, ok := structParam(params, "Заголовок"); ok {
+		b, ok := v.(VMBool)
+		if !ok {
+			return opts, VMErrorNeedBool
+		}
+		opts.header = bool(b)
+	}
+	if v, ok := structParam(params, "Типизировать"); ok {
+		b, ok := v.(VMBool)
+		if !ok {
+			return opts, VMErrorNeedBool
+		}
+		opts.typed = bool(b)
+	}
+	if v, ok := structParam(params, "Заголовки"); ok {
+		sl, ok := v.(VMSlice)
+		if !ok {
+			return opts, VMErrorNeedSlice
+		}
+		for _, h := range sl {
+			hs, ok := h.(VMString)
+			if !ok {
+				return opts, fmt.Errorf("%w: Заголовки, требуется массив строк", VMErrorCSVParam)
+			}
+			opts.headers = append(opts.headers, string(hs))
+		}
+	}
+	if opts.eol, err = csvStringParam(params, "РазделительСтрок", opts.eol); err != nil {
+		return
+	}
+	opts.decimalSp, err = csvStringParam(params, "РазделительДробнойЧасти", opts.decimalSp)
+	return
+}
+
+// isUTF8 кодировка не требует перекодирования
+func isUTF8(enc string) bool {
+	switch strings.ToLower(strings.ReplaceAll(enc, "-", "")) {
+	case "", "utf8":
+		return true
+	}
+	return false
+}
+
+var (
+	csvIntRgx     = regexp.MustCompile(`^-?(0|[1-9]\d*)$`)
+	csvDecRgx     = regexp.MustCompile(`^-?\d+[.,]\d+$`)
+	csvGroupedRgx = regexp.MustCompile(`^-?\d{1,3}([ \x{00a0}]\d{3})+([.,]\d+)?$`)
+	csvDateRgx    = regexp.MustCompile(`^\d{2}\.\d{2}\.\d{4}( \d{1,2}:\d{2}(:\d{2})?)?$|^\d{4}-\d{2}-\d{2}`)
+
+	csvDateLayouts = []string{
+		"02.01.2006",
+		"02.01.2006 15:04:05",
+		"02.01.2006 15:04",
+		"02.01.2006 3:04:05",
+		"2006-01-02",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05",
+	}
+)
+
+// csvTypedValue распознает целые и дробные числа, а также даты в форматах 1С и ISO.
+// Целые с ведущими нулями (коды, ИНН) остаются строками.
+func csvTypedValue(s string) VMValue {
+	if s == "" {
+		return VMString(s)
+	}
+	if csvIntRgx.MatchString(s) {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return VMInt(i)
+		}
+	}
+	ds := s
+	if csvGroupedRgx.MatchString(ds) {
+		ds = strings.NewReplacer(" ", "", "\u00a0", "").Replace(ds)
+		if !strings.ContainsAny(ds, ".,") {
+			if i, err := strconv.ParseInt(ds, 10, 64); err == nil {
+				return VMInt(i)
+			}
+		}
+	}
+	if csvDecRgx.MatchString(ds) {
+		if d, err := ParseVMDecNum(strings.Replace(ds, ",", ".", 1)); err == nil {
+			return d
+		}
+	}
+	if csvDateRgx.MatchString(s) {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return VMTime(t)
+		}
+		for _, l := range csvDateLayouts {
+			if t, err := time.ParseInLocation(l, s, time.Local); err == nil {
+				return VMTime(t)
+			}
+		}
+	}
+	return VMString(s)
+}
+
+// VMCSVReader построчно читает CSV файл, не загружая его целиком в память
+type VMCSVReader struct {
+	VMMetaObj
+
+	opts    csvOptions
+	f       *os.File
+	r       *bufio.Reader
+	headers []string
+	line    int
+}
+
+func (x *VMCSVReader) VMTypeString() string {
+	return "ЧтениеCSV"
+}
+
+func (x *VMCSVReader) VMRegister() {
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) < 1 || len(args) > 2 {
+			return VMErrorNeedArgs(1)
+		}
+		name, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		return x.Open(string(name), args)
+	})
+
+	x.VMRegisterMethod("Прочитать", VMFuncZeroParams(x.Прочитать))
+	x.VMRegisterMethod("ПрочитатьВсе", VMFuncZeroParams(x.ПрочитатьВсе))
+	x.VMRegisterMethod("ВТаблицуЗначений", VMFuncZeroParams(x.ВТаблицуЗначений))
+	x.VMRegisterMethod("Заголовки", VMFuncZeroParams(x.Заголовки))
+	x.VMRegisterMethod("Закрыть", VMFuncZeroParams(x.Закрыть))
+}
+
+func (x *VMCSVReader) Open(name string, args VMSlice) (err error) {
+	if x.opts, err = parseCSVOptions(args); err != nil {
+		return err
+	}
+	if x.f, err = os.Open(name); err != nil {
+		return err
+	}
+	var src io.Reader = x.f
+	if !isUTF8(x.opts.encoding) {
+		if src, err = iconv.NewReader(x.f, x.opts.encoding, "utf-8"); err != nil {
+			x.f.Close()
+			return VMErrorEncoding
+		}
+	}
+	x.r = bufio.NewReader(src)
+	// BOM
+	if r, _, err := x.r.ReadRune(); err == nil && r != '\uFEFF' {
+		x.r.UnreadRune()
+	}
+	if x.opts.header {
+		rec, err := x.readRecord()
+		if err != nil && err != io.EOF {
+			x.f.Close()
+			return err
+		}
+		x.headers = rec
+	}
+	return nil
+}
+
+// readRecord возвращает поля очередной непустой строки или io.EOF
+func (x *VMCSVReader) readRecord() ([]string, error) {
+	var (
+		rec     []string
+		field   strings.Builder
+		inQuote bool
+		quoted  bool
+		any     bool
+	)
+	x.line++
+	start := x.line
+	for {
+		r, _, err := x.r.ReadRune()
+		if err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			if inQuote {
+				return nil, fmt.Errorf("%w: не закрыта кавычка в строке %d", VMErrorCSVFormat, start)
+			}
+			if !any {
+				return nil, io.EOF
+			}
+			return append(rec, field.String()), nil
+		}
+		any = true
+		if inQuote {
+			if r == x.opts.quote {
+				if next, _, err := x.r.ReadRune(); err == nil {
+					if next == x.opts.quote {
+						field.WriteRune(r)
+						continue
+					}
+					x.r.UnreadRune()
+				}
+				inQuote = false
+				continue
+			}
+			if r == '\n' {
+				x.line++
+			}
+			field.WriteRune(r)
+			continue
+		}
+		switch {
+		case r == x.opts.quote && field.Len() == 0 && !quoted:
+			inQuote, quoted = true, true
+		case r == x.opts.delim:
+			rec = append(rec, field.String())
+			field.Reset()
+			quoted = false
+		case r == '\r' || r == '\n':
+			if r == '\r' {
+				if next, _, err := x.r.ReadRune(); err == nil && next != '\n' {
+					x.r.UnreadRune()
+				}
+			}
+			if len(rec) == 0 && field.Len() == 0 && !quoted {
+				// пустая строка
+				any = false
+				x.line++
+				start = x.line
+				continue
+			}
+			return append(rec, field.String()), nil
+		default:
+			field.WriteRune(r)
+		}
+	}
+}
+
+func (x *VMCSVReader) value(s string) VMValue {
+	if x.opts.typed {
+		return csvTypedValue(s)
+	}
+	return VMString(s)
+}
+
+func (x *VMCSVReader) columnName(i int) string {
+	if i < len(x.headers) && x.headers[i] != "" {
+		return x.headers[i]
+	}
+	return "Колонка" + strconv.Itoa(i+1)
+}
+
+// Read возвращает Структуру, если есть заголовок, или Массив значений
+func (x *VMCSVReader) Read() (VMValue, error) {
+	if x.r == nil {
+		return nil, VMErrorCSVClosed
+	}
+	rec, err := x.readRecord()
+	if err != nil {
+		return nil, err
+	}
+	if !x.opts.header {
+		rv := make(VMSlice, len(rec))
+		for i, s := range rec {
+			rv[i] = x.value(s)
+		}
+		return rv, nil
+	}
+	rv := make(VMStringMap, len(x.headers))
+	for i := range x.headers {
+		rv[x.columnName(i)] = VMNil
+	}
+	for i, s := range rec {
+		rv[x.columnName(i)] = x.value(s)
+	}
+	return rv, nil
+}
+
+func (x *VMCSVReader) Прочитать(rets *VMSlice) error {
+	v, err := x.Read()
+	if err == io.EOF {
+		rets.Append(VMNil)
+		rets.Append(VMBool(false))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	rets.Append(v)
+	rets.Append(VMBool(true))
+	return nil
+}
+
+func (x *VMCSVReader) ПрочитатьВсе(rets *VMSlice) error {
+	rv := make(VMSlice, 0, 64)
+	for {
+		v, err := x.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		rv = append(rv, v)
+	}
+	rets.Append(rv)
+	return nil
+}
+
+func (x *VMCSVReader) ВТаблицуЗначений(rets *VMSlice) error {
+	if x.r == nil {
+		return VMErrorCSVClosed
+	}
+	vt := NewVMTable()
+	cols := vt.Columns()
+	for i := range x.headers {
+		cols.Add(x.columnName(i))
+	}
+	for {
+		rec, err := x.readRecord()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		l := vt.AddLine()
+		for i, s := range rec {
+			if i >= len(cols.cols) {
+				cols.Add(x.columnName(i))
+			}
+			l.SetIndex(i, x.value(s))
+		}
+	}
+	rets.Append(vt)
+	return nil
+}
+
+func (x *VMCSVReader) Заголовки(rets *VMSlice) error {
+	rets.Append(NewVMSliceFromStrings(x.headers))
+	return nil
+}
+
+func (x *VMCSVReader) Close() error {
+	if x.f == nil {
+		return nil
+	}
+	err := x.f.Close()
+	x.f, x.r = nil, nil
+	return err
+}
+
+func (x *VMCSVReader) Закрыть(rets *VMSlice) error {
+	return x.Close()
+}
+
+// VMCSVWriter построчно записывает CSV файл
+type VMCSVWriter struct {
+	VMMetaObj
+
+	opts       csvOptions
+	f          *os.File
+	w          *bufio.Writer
+	conv       *iconv.Converter
+	headerDone bool
+}
+
+func (x *VMCSVWriter) VMTypeString() string {
+	return "ЗаписьCSV"
+}
+
+func (x *VMCSVWriter) VMRegister() {
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) < 1 || len(args) > 2 {
+			return VMErrorNeedArgs(1)
+		}
+		name, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		return x.Create(string(name), args)
+	})
+
+	x.VMRegisterMethod("Записать", VMFuncOneParam(x.Записать))
+	x.VMRegisterMethod("ЗаписатьВсе", VMFuncOneParam(x.ЗаписатьВсе))
+	x.VMRegisterMethod("Закрыть", VMFuncZeroParams(x.Закрыть))
+}
+
+func (x *VMCSVWriter) Create(name string, args VMSlice) (err error) {
+	if x.opts, err = parseCSVOptions(args); err != nil {
+		return err
+	}
+	if !isUTF8(x.opts.encoding) {
+		if x.conv, err = iconv.NewConverter("utf-8", x.opts.encoding); err != nil {
+			return VMErrorEncoding
+		}
+	}
+	if x.f, err = os.Create(name); err != nil {
+		return err
+	}
+	x.w = bufio.NewWriter(x.f)
+	return nil
+}
+
+func (x *VMCSVWriter) formatValue(v VMValue) string {
+	switch vv := v.(type) {
+	case nil, VMNilType:
+		return ""
+	case VMTime:
+		t := time.Time(vv)
+		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+			return t.Format("02.01.2006")
+		}
+		return t.Format("02.01.2006 15:04:05")
+	case VMDecNum:
+		return strings.Replace(vv.String(), ".", x.opts.decimalSp, 1)
+	case VMStringer:
+		return vv.String()
+	}
+	return fmt.Sprint(v)
+}
+
+func (x *VMCSVWriter) writeLine(fields []string) error {
+	if x.w == nil {
+		return VMErrorCSVClosed
+	}
+	var sb strings.Builder
+	q := string(x.opts.quote)
+	for i, s := range fields {
+		if i > 0 {
+			sb.WriteRune(x.opts.delim)
+		}
+		if strings.ContainsRune(s, x.opts.delim) || strings.ContainsRune(s, x.opts.quote) ||
+			strings.ContainsAny(s, "\r\n") || strings.TrimSpace(s) != s {
+			sb.WriteString(q)
+			sb.WriteString(strings.ReplaceAll(s, q, q+q))
+			sb.WriteString(q)
+		} else {
+			sb.WriteString(s)
+		}
+	}
+	sb.WriteString(x.opts.eol)
+	line := sb.String()
+	if x.conv != nil {
+		var err error
+		if line, err = x.conv.ConvertString(line); err != nil {
+			return VMErrorEncoding
+		}
+	}
+	_, err := x.w.WriteString(line)
+	return err
+}
+
+func (x *VMCSVWriter) writeHeader() error {
+	if x.headerDone || !x.opts.header || len(x.opts.headers) == 0 {
+		return nil
+	}
+	x.headerDone = true
+	return x.writeLine(x.opts.headers)
+}
+
+// Write записывает Массив значений или Структуру (в т.ч. строку таблицы значений)
+func (x *VMCSVWriter) Write(rec VMValue) error {
+	var fields []string
+	switch r := rec.(type) {
+	case VMSlice:
+		fields = make([]string, len(r))
+		for i, v := range r {
+			fields[i] = x.formatValue(v)
+		}
+	case VMStringMaper:
+		m := r.StringMap()
+		if x.opts.headers == nil {
+			x.opts.headers = make([]string, 0, len(m))
+			for k := range m {
+				x.opts.headers = append(x.opts.headers, k)
+			}
+			sort.Strings(x.opts.headers)
+		}
+		fields = make([]string, len(x.opts.headers))
+		for i, h := range x.opts.headers {
+			fields[i] = x.formatValue(m[h])
+		}
+	default:
+		return VMErrorNeedMap
+	}
+	if err := x.writeHeader(); err != nil {
+		return err
+	}
+	return x.writeLine(fields)
+}
+
+func (x *VMCSVWriter) Записать(rec VMValue, rets *VMSlice) error {
+	return x.Write(rec)
+}
+
+func (x *VMCSVWriter) ЗаписатьВсе(recs VMValue, rets *VMSlice) error {
+	if vt, ok := recs.(*VMTable); ok && x.opts.headers == nil {
+		for _, c := range vt.cols.cols {
+			x.opts.headers = append(x.opts.headers, c.Name())
+		}
+	}
+	sl, ok := recs.(VMSlicer)
+	if !ok {
+		return VMErrorNeedSlice
+	}
+	for _, rec := range sl.Slice() {
+		if err := x.Write(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *VMCSVWriter) Close() error {
+	if x.f == nil {
+		return nil
+	}
+	err := x.writeHeader()
+	if ferr := x.w.Flush(); err == nil {
+		err = ferr
+	}
+	if cerr := x.f.Close(); err == nil {
+		err = cerr
+	}
+	if x.conv != nil {
+		x.conv.Close()
+	}
+	x.f, x.w, x.conv = nil, nil, nil
+	return err
+}
+
+func (x *VMCSVWriter) Закрыть(rets *VMSlice) error {
+	return x.Close()
+}
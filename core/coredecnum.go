@@ -1,11 +1,13 @@
 package core
 
 import (
-	"bytes"
-	"encoding/binary"
+	"fmt"
 	"math"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
+	"unsafe"
 
 	"github.com/covrom/decnum"
 )
@@ -73,6 +75,77 @@ func (x VMDecNum) String() string {
 	return x.num.String()
 }
 
+// Format реализует fmt.Formatter для Формат со строкой формата Go:
+// %f округляет число до точности без перевода в float64, %e и %g выводят его как float64,
+// %d - целую часть, остальные глаголы - строковое представление
+func (x VMDecNum) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'e', 'E', 'g', 'G':
+		fmt.Fprintf(f, formatSpec(f, verb), x.Float())
+	case 'd':
+		fmt.Fprintf(f, formatSpec(f, verb), x.Int())
+	case 'f', 'F':
+		prec, ok := f.Precision()
+		if !ok {
+			prec = 6
+		}
+		r := x.RoundMode(int32(prec), decnum.RoundHalfUp).num
+		s := r.Abs().String()
+		if strings.ContainsAny(s, "Ee") {
+			s = strconv.FormatFloat(math.Abs(x.Float()), 'f', prec, 64)
+		}
+		ip, fp, _ := strings.Cut(s, ".")
+		if len(fp) < prec {
+			fp += strings.Repeat("0", prec-len(fp))
+		}
+		s = ip
+		if prec > 0 {
+			s += "." + fp[:prec]
+		}
+		switch {
+		case r.IsNegative() && !r.IsZero():
+			s = "-" + s
+		case f.Flag('+'):
+			s = "+" + s
+		case f.Flag(' '):
+			s = " " + s
+		}
+		fmt.Fprintf(f, widthSpec(f, 's'), s)
+	default:
+		fmt.Fprintf(f, widthSpec(f, 's'), x.String())
+	}
+}
+
+// formatSpec восстанавливает строку формата из флагов, ширины и точности
+func formatSpec(f fmt.State, verb rune) string {
+	spec := []rune{'%'}
+	for _, fl := range "+-# 0" {
+		if f.Flag(int(fl)) {
+			spec = append(spec, fl)
+		}
+	}
+	rv := string(spec)
+	if w, ok := f.Width(); ok {
+		rv += strconv.Itoa(w)
+	}
+	if p, ok := f.Precision(); ok {
+		rv += "." + strconv.Itoa(p)
+	}
+	return rv + string(verb)
+}
+
+// widthSpec строка формата только с шириной и выравниванием, для вывода готовой строки
+func widthSpec(f fmt.State, verb rune) string {
+	rv := "%"
+	if f.Flag('-') {
+		rv += "-"
+	}
+	if w, ok := f.Width(); ok {
+		rv += strconv.Itoa(w)
+	}
+	return rv + string(verb)
+}
+
 func (x VMDecNum) Int() int64 {
 	i, err := x.num.ToInt64(decnum.RoundDown) // целая часть, без округления
 	if err != nil {
@@ -221,6 +294,8 @@ func (x VMDecNum) EvalBinOp(op VMOperation, y VMOperationer) (VMValue, error) {
 			return x.Mul(NewVMDecNumFromInt64(int64(yy))), nil
 		case VMDecNum:
 			return x.Mul(yy), nil
+		case VMMoney:
+			return yy.EvalBinOp(MUL, x)
 		}
 		return VMNil, VMErrorIncorrectOperation
 	case QUO:
@@ -333,14 +408,21 @@ func (x VMDecNum) ConvertToType(nt reflect.Type) (VMValue, error) {
 	return VMNil, VMErrorNotConverted
 }
 
+// MarshalBinary сохраняет число в текстовом виде, т.к. внутренняя структура decnum.Quad
+// содержит неэкспортируемые поля и не может быть прочитана через encoding/binary
 func (x VMDecNum) MarshalBinary() ([]byte, error) {
-	var buf bytes.Buffer
-	err := binary.Write(&buf, binary.LittleEndian, x.num)
-	return buf.Bytes(), err
+	return x.MarshalText()
 }
 
+// UnmarshalBinary читает число в текстовом виде.
+// Прежние версии записывали decnum.Quad через encoding/binary (16 байт числа и 2 байта статуса),
+// такие значения в существующих файлах баз данных читаются копированием в структуру как есть
 func (x *VMDecNum) UnmarshalBinary(data []byte) error {
-	err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &x.num)
+	err := x.UnmarshalText(data)
+	if err != nil && len(data) == int(unsafe.Sizeof(x.num)) {
+		copy((*[unsafe.Sizeof(x.num)]byte)(unsafe.Pointer(&x.num))[:], data)
+		return nil
+	}
 	return err
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"reflect"
 	"sort"
@@ -36,6 +37,28 @@ func putEnvVals(sl VMSlice) {
 	}
 }
 
+// vmCell ячейка переменной, захваченной замыканием по ссылке.
+// Окружение функции и окружение замыкания хранят одну и ту же ячейку,
+// поэтому присваивания в одном из них видны в другом.
+type vmCell struct {
+	mu sync.RWMutex
+	v  VMValue
+}
+
+func (c *vmCell) VMTypeString() string { return "Ячейка" }
+
+func (c *vmCell) get() VMValue {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.v
+}
+
+func (c *vmCell) set(v VMValue) {
+	c.mu.Lock()
+	c.v = v
+	c.mu.Unlock()
+}
+
 type Vals struct {
 	idx  map[int]int
 	vals VMSlice
@@ -50,7 +73,15 @@ func NewVals() *Vals {
 }
 
 func (v *Vals) Get(name int) (VMValue, bool) {
+	if v == nil {
+		// окружение уже уничтожено
+		return nil, false
+	}
 	if i, ok := v.idx[name]; ok {
+		if c, ok := v.vals[i].(*vmCell); ok {
+			cv := c.get()
+			return cv, cv != nil
+		}
 		return v.vals[i], v.vals[i] != nil
 	}
 	return nil, false
@@ -58,6 +89,10 @@ func (v *Vals) Get(name int) (VMValue, bool) {
 
 func (v *Vals) Set(name int, val VMValue) {
 	if i, ok := v.idx[name]; ok {
+		if c, ok := v.vals[i].(*vmCell); ok {
+			c.set(val)
+			return
+		}
 		v.vals[i] = val
 	} else {
 		i = len(v.vals)
@@ -68,10 +103,43 @@ func (v *Vals) Set(name int, val VMValue) {
 
 func (v *Vals) Del(name int) {
 	if i, ok := v.idx[name]; ok {
+		if c, ok := v.vals[i].(*vmCell); ok {
+			c.set(nil)
+			return
+		}
 		v.vals[i] = nil
 	}
 }
 
+// cell возвращает ячейку переменной или nil, если переменная не захвачена замыканием
+func (v *Vals) cell(name int) *vmCell {
+	if v == nil {
+		return nil
+	}
+	if i, ok := v.idx[name]; ok {
+		c, _ := v.vals[i].(*vmCell)
+		return c
+	}
+	return nil
+}
+
+// box переносит значение переменной в ячейку и возвращает ее,
+// для неопределенной переменной создается пустая ячейка
+func (v *Vals) box(name int) *vmCell {
+	if c := v.cell(name); c != nil {
+		return c
+	}
+	c := &vmCell{}
+	if i, ok := v.idx[name]; ok {
+		c.v = v.vals[i]
+		v.vals[i] = c
+	} else {
+		v.idx[name] = len(v.vals)
+		v.vals = append(v.vals, c)
+	}
+	return c
+}
+
 func (v *Vals) Destroy() {
 	putEnvVals(v.vals)
 }
@@ -91,6 +159,13 @@ type Env struct {
 	lastval      VMValue
 	builtsLoaded bool
 	Valid        bool
+	resources    map[VMReleaser]int // только в глобальном окружении, значение - порядок регистрации
+	resourceSeq  int
+	classes      map[int]*VMClass   // пользовательские типы, только в глобальном окружении
+	exports      map[int]bool       // экспортируемые имена модуля
+	yield        func(VMValue) bool // только в окружении функции-генератора
+	closure      bool               // окружение замыкания с ячейками захваченных переменных
+	pattern      bool               // окружение ветки Когда с именами, связанными шаблоном
 }
 
 // нужно для того, чтобы *Env можно было сохранять в переменные VMValue
@@ -147,6 +222,92 @@ func (e *Env) NewSubEnv() *Env {
 	}
 }
 
+// NewPatternEnv создает окружение ветки Когда. В нем определяются только имена, связанные шаблоном
+// через DefineLocal, остальные имена, определяемые в ветке, определяются в окружении e
+func (e *Env) NewPatternEnv() *Env {
+	ne := e.NewSubEnv()
+	ne.pattern = true
+	return ne
+}
+
+// scope возвращает модуль, в котором находится окружение, или глобальный контекст
+func (e *Env) scope() *Env {
+	scope := e
+	for scope.parent != nil && scope.name == "" {
+		scope = scope.parent
+	}
+	return scope
+}
+
+// NewScopeEnv создает окружение функции, наследуемое от модуля, в котором она определена,
+// или от глобального контекста, поэтому функции модуля видят друг друга и переменные модуля
+func (e *Env) NewScopeEnv() *Env {
+	ne := e.scope().NewSubEnv()
+	ne.interrupt = e.interrupt
+	ne.stdout = e.stdout
+	return ne
+}
+
+// Capture создает окружение замыкания анонимной функции, созданной в окружении e.
+// Переменные внешних функций захватываются по ссылке: значение переносится в ячейку,
+// общую для окружения функции и замыкания. Читаемые имена upvals, которые еще не определены,
+// получают пустую ячейку в e, например, рекурсивная функция в локальной переменной,
+// а имена assigned без определения остаются локальными переменными замыкания.
+// Переменные модуля и глобального контекста не захватываются, а читаются и присваиваются при вызове,
+// поэтому присваивание в замыкании меняет переменную, видимую там, где оно создано, где бы оно ни было создано.
+// Переменные циклов iters копируются в отдельную ячейку, поэтому у замыканий,
+// созданных на разных итерациях, свои значения. Само окружение e замыкание не удерживает.
+func (e *Env) Capture(upvals, assigned, iters []int) *Env {
+	scope := e.scope()
+	ce := scope.NewSubEnv()
+	ce.closure = true
+	capture := func(k int, define bool) {
+		for _, i := range iters {
+			if i == k {
+				if v, err := e.Get(k); err == nil {
+					ce.env.Set(k, &vmCell{v: v})
+				}
+				return
+			}
+		}
+		owner := e
+		for owner.pattern {
+			// в окружении ветки Когда определяются только имена шаблона
+			owner = owner.parent
+		}
+		if !define {
+			owner = nil
+		}
+		for ee := e; ee != scope; ee = ee.parent {
+			ee.RLock()
+			_, ok := ee.env.Get(k)
+			ok = ok || ee.env.cell(k) != nil
+			ee.RUnlock()
+			if ok {
+				owner = ee
+				break
+			}
+		}
+		if owner == nil || owner == scope {
+			return
+		}
+		owner.Lock()
+		c := owner.env.box(k)
+		if owner.lastid == k {
+			owner.lastid = -1
+		}
+		owner.Unlock()
+		ce.env.Set(k, c)
+	}
+	for _, k := range upvals {
+		capture(k, true)
+	}
+	for _, k := range assigned {
+		capture(k, false)
+	}
+	return ce
+}
+
 // Находим или создаем новый модуль в глобальном скоупе
 func (e *Env) NewModule(n string) *Env {
 	// ni := strings.ToLower(n)
@@ -180,8 +341,10 @@ func (e *Env) NewPackage(n string) *Env {
 }
 
 // Destroy deletes current scope.
+// Для глобального окружения освобождаются незавершенные операции зарегистрированных ресурсов.
 func (e *Env) Destroy() {
 	if e.parent == nil {
+		e.releaseResources()
 		return
 	}
 
@@ -209,6 +372,50 @@ func (e *Env) Destroy() {
 	e.env = nil
 }
 
+// AddResource регистрирует ресурс в глобальном окружении для проверки на утечки при его уничтожении
+func (e *Env) AddResource(r VMReleaser) {
+	root := e
+	for root.parent != nil {
+		root = root.parent
+	}
+	root.Lock()
+	if root.resources == nil {
+		root.resources = make(map[VMReleaser]int)
+	}
+	root.resourceSeq++
+	root.resources[r] = root.resourceSeq
+	root.Unlock()
+}
+
+// RemoveResource снимает с учета ресурс, освобожденный до уничтожения глобального окружения
+func (e *Env) RemoveResource(r VMReleaser) {
+	root := e
+	for root.parent != nil {
+		root = root.parent
+	}
+	root.Lock()
+	delete(root.resources, r)
+	root.Unlock()
+}
+
+func (e *Env) releaseResources() {
+	e.Lock()
+	res := make([]VMReleaser, 0, len(e.resources))
+	for r := range e.resources {
+		res = append(res, r)
+	}
+	seq := e.resources
+	e.resources = nil
+	e.Unlock()
+	// ресурсы освобождаются в порядке регистрации
+	sort.Slice(res, func(i, j int) bool { return seq[res[i]] < seq[res[j]] })
+	for _, r := range res {
+		if err := r.ReleaseLeaked(); err != nil {
+			log.Println("Предупреждение:", err)
+		}
+	}
+}
+
 func (e *Env) SetBuiltsIsLoaded() {
 	e.builtsLoaded = true
 }
@@ -278,8 +485,9 @@ func (e *Env) Get(k int) (VMValue, error) {
 		}
 		if v, ok := ee.env.Get(k); ok {
 			li := ee.lastid
+			cached := ee.env.cell(k) == nil
 			ee.RUnlock()
-			if k != li {
+			if k != li && cached {
 				ee.Lock()
 				ee.lastid = k
 				ee.lastval = v
@@ -299,8 +507,7 @@ func (e *Env) Set(k int, v VMValue) error {
 		ee.Lock()
 		if _, ok := ee.env.Get(k); ok {
 			ee.env.Set(k, v)
-			ee.lastid = k
-			ee.lastval = v
+			ee.cache(k, v)
 			ee.Unlock()
 			return nil
 		}
@@ -309,6 +516,29 @@ func (e *Env) Set(k int, v VMValue) error {
 	return fmt.Errorf("Имя не определено '%s'", names.UniqueNames.Get(k))
 }
 
+// Export отмечает имя как экспортируемое из модуля
+func (e *Env) Export(k int) {
+	e.Lock()
+	if e.exports == nil {
+		e.exports = make(map[int]bool)
+	}
+	e.exports[k] = true
+	e.Unlock()
+}
+
+// GetExported возвращает значение имени при обращении к модулю извне (Модуль.Имя).
+// Если в модуле есть функции с Экспорт, то доступны только они, иначе - все имена модуля.
+func (e *Env) GetExported(k int) (VMValue, error) {
+	e.RLock()
+	exp := e.exports
+	hidden := exp != nil && !exp[k]
+	e.RUnlock()
+	if hidden {
+		return nil, fmt.Errorf("Имя не экспортируется модулем '%s'", names.UniqueNames.Get(k))
+	}
+	return e.Get(k)
+}
+
 // DefineGlobal defines symbol in global scope.
 func (e *Env) DefineGlobal(k int, v VMValue) error {
 	for ee := e; ee != nil; ee = ee.parent {
@@ -358,15 +588,68 @@ func (e *Env) DefineTypeStruct(t interface{}) error {
 }
 
 // Define defines symbol in current scope.
+// В теле замыкания присваивание захваченной переменной изменяет ее ячейку,
+// а переменной модуля или глобального контекста - саму переменную.
 func (e *Env) Define(k int, v VMValue) error {
+	if e.pattern {
+		e.RLock()
+		_, local := e.env.idx[k]
+		e.RUnlock()
+		if !local {
+			return e.parent.Define(k, v)
+		}
+	}
+	if p := e.parent; p != nil && p.closure {
+		e.RLock()
+		_, local := e.env.idx[k]
+		e.RUnlock()
+		if !local {
+			p.RLock()
+			c := p.env.cell(k)
+			p.RUnlock()
+			if c != nil && c.get() != nil {
+				c.set(v)
+				return nil
+			}
+			// переменные модуля и глобального контекста, видимые там, где создано замыкание,
+			// пустая ячейка еще не определенной переменной функции их не скрывает
+			if p.parent != nil && p.parent.Set(k, v) == nil {
+				return nil
+			}
+			if c != nil {
+				c.set(v)
+				return nil
+			}
+		}
+	}
+
 	e.Lock()
 	e.env.Set(k, v)
+	e.cache(k, v)
+	e.Unlock()
+
+	return nil
+}
+
+// cache запоминает последнее значение имени, значения в ячейках не запоминаются,
+// т.к. их может изменить другое окружение
+func (e *Env) cache(k int, v VMValue) {
+	if e.env.cell(k) != nil {
+		if e.lastid == k {
+			e.lastid = -1
+		}
+		return
+	}
 	e.lastid = k
 	e.lastval = v
+}
 
+// DefineLocal определяет имя в самом окружении, в том числе в окружении ветки Когда
+func (e *Env) DefineLocal(k int, v VMValue) {
+	e.Lock()
+	e.env.Set(k, v)
+	e.cache(k, v)
 	e.Unlock()
-
-	return nil
 }
 
 func (e *Env) DefineS(k string, v VMValue) error {
@@ -452,6 +735,22 @@ func (e *Env) GetSid() string {
 	return ""
 }
 
+// SetYield делает e окружением функции-генератора, значения Выдать передаются в f
+func (e *Env) SetYield(f func(VMValue) bool) {
+	e.yield = f
+}
+
+// Yield передает значение потребителю генератора.
+// Возвращает false, если потребитель закрыл генератор и тело функции нужно завершить
+func (e *Env) Yield(v VMValue) (bool, error) {
+	for ee := e; ee != nil; ee = ee.parent {
+		if ee.yield != nil {
+			return ee.yield(v), nil
+		}
+	}
+	return false, VMErrorYieldOutsideGenerator
+}
+
 func (e *Env) Interrupt() {
 	*(e.interrupt) = true
 }
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/shinanca/gonec/names"
+)
+
+func TestEnvCapture(t *testing.T) {
+	x := names.UniqueNames.Set("тестзахватх")
+	i := names.UniqueNames.Set("тестзахватсчетчик")
+	later := names.UniqueNames.Set("тестзахватпозже")
+
+	global := NewEnv()
+	fenv := global.NewEnv()
+	fenv.Define(x, VMInt(1))
+	fenv.Define(i, VMInt(1))
+	ce := fenv.Capture([]int{x, i, later}, nil, []int{i})
+
+	if ce.parent != global {
+		t.Fatalf("замыкание не должно удерживать окружение функции")
+	}
+
+	fenv.Define(x, VMInt(2))
+	if v, _ := ce.Get(x); v != VMInt(2) {
+		t.Errorf("замыкание видит %v, want 2", v)
+	}
+	call := ce.NewSubEnv()
+	call.Define(x, VMInt(3))
+	if v, _ := fenv.Get(x); v != VMInt(3) {
+		t.Errorf("присваивание в замыкании: в функции %v, want 3", v)
+	}
+	if _, ok := call.env.idx[x]; ok {
+		t.Errorf("присваивание захваченной переменной не должно создавать локальную")
+	}
+
+	fenv.Define(i, VMInt(2))
+	if v, _ := ce.Get(i); v != VMInt(1) {
+		t.Errorf("переменная цикла: %v, want значение на момент создания 1", v)
+	}
+
+	if _, err := ce.Get(later); err == nil {
+		t.Errorf("неопределенное имя не должно быть доступно")
+	}
+	fenv.Define(later, VMInt(5))
+	if v, _ := ce.Get(later); v != VMInt(5) {
+		t.Errorf("имя, определенное позже: %v, want 5", v)
+	}
+}
+
+// BenchmarkClosureRetained показывает память, которую удерживают долгоживущие замыкания,
+// например, обработчики сервера: прежняя модель удерживала все окружение функции,
+// в котором создано замыкание, а Capture - только захваченные переменные
+func BenchmarkClosureRetained(b *testing.B) {
+	x := names.UniqueNames.Set("тестпамятьх")
+	big := names.UniqueNames.Set("тестпамятьбольшая")
+	global := NewEnv()
+
+	run := func(b *testing.B, closure func(fenv *Env) *Env) {
+		closures := make([]*Env, 0, b.N)
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		for n := 0; n < b.N; n++ {
+			fenv := global.NewEnv()
+			fenv.Define(x, VMInt(n))
+			fenv.Define(big, make(VMSlice, 1024))
+			closures = append(closures, closure(fenv))
+		}
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/float64(b.N), "retained-B/op")
+		runtime.KeepAlive(closures)
+	}
+
+	b.Run("окружение функции", func(b *testing.B) {
+		run(b, func(fenv *Env) *Env { return fenv.NewSubEnv() })
+	})
+	b.Run("захват", func(b *testing.B) {
+		run(b, func(fenv *Env) *Env { return fenv.Capture([]int{x}, nil, nil) })
+	})
+}
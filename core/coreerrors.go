@@ -54,14 +54,39 @@ var (
 
 	VMErrorTransactionIsOpened  = errors.New("Уже была открыта транзакция")
 	VMErrorTransactionNotOpened = errors.New("Не открыта транзакция")
+	VMErrorTransactionTimeout   = errors.New("Транзакция отменена по истечении таймаута")
+	VMErrorDBNotOpened          = errors.New("База данных не открыта")
 	VMErrorTableNotExists       = errors.New("Отсутствует таблица в базе данных")
 	VMErrorWrongDBValue         = errors.New("Невозможно распознать значение в базе данных")
+	VMErrorIndexNotExists       = errors.New("Отсутствует индекс в таблице базы данных")
+	VMErrorQueryOperator        = errors.New("Неизвестный оператор условия запроса")
+	VMErrorIndexValue           = errors.New("Индексироваться могут только строки, числа, даты и булевы значения")
+	VMErrorDBFileExists         = errors.New("Файл базы данных уже существует")
+	VMErrorDBCorrupted          = errors.New("Файл базы данных поврежден")
+	VMErrorIndexAfterKey        = errors.New("Запись с ключом ПослеКлюча отсутствует в индексе")
 
 	VMErrorEan13Format = errors.New("Ean13 состоит из 12 цифр")
 	VMErrorI2Of5Format = errors.New("I2Of5 состоит из чётного количества цифр")
 
 	VMErrorSaveXML = errors.New("Ошибки при записи XML")
 
+	VMErrorCalendarFormat = errors.New("Неверный формат строки производственного календаря")
+	VMErrorTimeFormat     = errors.New("Неверный формат даты и времени")
+	VMErrorTimeZone       = errors.New("Неизвестный часовой пояс")
+
+	VMErrorCronFormat           = errors.New("Неверный формат расписания")
+	VMErrorSchedulerJobNotFound = errors.New("Задание планировщика не найдено")
+
+	VMErrorDivisionByZero  = errors.New("Деление на ноль")
+	VMErrorRoundingMode    = errors.New("Неизвестный режим округления")
+	VMErrorDistributeCoefs = errors.New("Сумма коэффициентов распределения равна нулю")
+	VMErrorMoneyPrecision  = errors.New("Сумма не представима с точностью денежного значения без округления")
+	VMErrorMoneyCurrency   = errors.New("Операция с суммами в разных валютах")
+	VMErrorFormatString    = errors.New("Неверная форматная строка")
+
+	VMErrorNeedFuncOrPath  = errors.New("Требуется функция или путь к полю")
+	VMErrorNeedFuncOrIndex = errors.New("Требуется функция или индекс")
+
 	VMErrorReadXlsxTemplate = errors.New("Ошибка при чтении шаблона xlsx")
 	VMErrorFillXlsx         = errors.New("Ошибка при заполнении шаблона xslx данными")
 	VMErrorSaveXlsx         = errors.New("Ошибка при сохранении заполненного xlsx шаблона")
@@ -72,12 +97,43 @@ var (
 	VMErrorSaveXlsxFile = errors.New("Ошибка при записи xlsx файла")
 
 	VMErrorEncoding = errors.New("Ошибка при кодировании строки")
+
+	VMErrorTableColumnExists   = errors.New("Колонка с таким именем уже существует")
+	VMErrorTableColumnNotFound = errors.New("Колонка с таким именем не найдена")
+
+	VMErrorCSVParam  = errors.New("Неверный параметр CSV")
+	VMErrorCSVFormat = errors.New("Ошибка формата CSV")
+	VMErrorCSVClosed = errors.New("Файл CSV уже закрыт")
+
+	VMErrorPDFOrientation = errors.New("Ориентация страницы должна быть Книжная или Альбомная")
+	VMErrorPDFTemplate    = errors.New("Ошибка при заполнении шаблона PDF")
+
+	VMErrorTemplate       = errors.New("Ошибка шаблона")
+	VMErrorTemplateFormat = errors.New("Формат шаблона должен быть html или текст")
+
+	VMErrorNeedBinaryData       = errors.New("Требуется значение типа ДвоичныеДанные или Строка")
+	VMErrorUnknownHashAlgorithm = errors.New("Неизвестный алгоритм хеширования")
+	VMErrorStreamClosed         = errors.New("Поток закрыт")
+	VMErrorStreamMode           = errors.New("Неверный режим открытия потока")
+
+	VMErrorNeedArchiveSource    = errors.New("Требуется имя файла, поток или ДвоичныеДанные")
+	VMErrorArchiveClosed        = errors.New("Архив уже закрыт")
+	VMErrorArchiveEntryNotFound = errors.New("Элемент архива не найден")
+	VMErrorArchiveUnsafePath    = errors.New("Элемент архива указывает за пределы каталога извлечения")
+	VMErrorZipPassword          = errors.New("Неверный пароль архива или пароль не указан")
+	VMErrorZipMethod            = errors.New("Неподдерживаемый метод сжатия или шифрования в архиве")
+
+	VMErrorYieldOutsideGenerator = errors.New("Выдать допустимо только в теле функции")
 )
 
 func VMErrorNeedArgs(n int) error {
 	return fmt.Errorf("Неверное количество параметров (требуется %d)", n)
 }
 
+func VMErrorFillXlsxCell(sheet, cell, placeholder string, err error) error {
+	return fmt.Errorf("Ошибка при заполнении шаблона xlsx: лист '%s', ячейка %s, выражение '%s': %s", sheet, cell, placeholder, err)
+}
+
 func VMErrorMaxArgs(n int) error {
 	return fmt.Errorf("Неверное количество параметров (максимум %d)", n)
 }
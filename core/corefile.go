@@ -114,6 +114,8 @@ func (f *File) VMRegister() {
 	f.VMRegisterMethod("УстановитьВремяИзменения", VMFuncOneParam(f.УстановитьВремяИзменения))
 	f.VMRegisterMethod("Удалить", VMFuncZeroParams(f.Удалить))
 	f.VMRegisterMethod("ПолучитьДанныеФайла", VMFuncZeroParams(f.ПолучитьДанныеФайла))
+	f.VMRegisterMethod("ПолучитьДвоичныеДанные", VMFuncZeroParams(f.ПолучитьДвоичныеДанные))
+	f.VMRegisterMethod("Строки", VMFuncZeroParams(f.Строки))
 }
 
 func (f *File) ПолучитьДанныеФайла(rets *VMSlice) error {
@@ -124,6 +126,26 @@ func (f *File) ПолучитьДанныеФайла(rets *VMSlice) error {
 	return err
 }
 
+func (f *File) ПолучитьДвоичныеДанные(rets *VMSlice) error {
+	data, err := os.ReadFile(f.name)
+	if err != nil {
+		return f.wrapError(err)
+	}
+	rets.Append(VMBinaryData(data))
+	return nil
+}
+
+// Строки перебирает строки файла в цикле Для каждого, не загружая файл в память.
+// Файл закрывается по окончании перебора или при выходе из цикла
+func (f *File) Строки(rets *VMSlice) error {
+	fl, err := os.Open(f.name)
+	if err != nil {
+		return f.wrapError(err)
+	}
+	rets.Append(NewVMLineIterator(fl, fl))
+	return nil
+}
+
 func (f *File) Существует(rets *VMSlice) error {
 	exists, err := f.Exists()
 	if err == nil {
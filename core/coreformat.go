@@ -0,0 +1,346 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/covrom/decnum"
+)
+
+// Форматные строки 1С: параметры вида Имя=Значение через ";", значение может быть
+// заключено в одинарные или двойные кавычки, параметр без значения считается равным "Истина".
+//
+// Параметры форматирования чисел:
+//
+//	Л   - локализация, например ru_RU или en_US, задает разделители по умолчанию
+//	ЧЦ  - общее количество знаков, не помещающееся число выводится девятками
+//	ЧДЦ - количество знаков дробной части, число округляется по правилу Окр15как20
+//	ЧС  - сдвиг разрядов: ЧС=3 выводит число в тысячах
+//	ЧРД - разделитель целой и дробной части
+//	ЧРГ - разделитель групп разрядов
+//	ЧГ  - размеры групп разрядов через запятую, ЧГ=0 отключает группировку
+//	ЧН  - представление нуля, по умолчанию пустая строка
+//	ЧВН - выводить лидирующие нули до ЧЦ знаков
+//	ЧО  - представление отрицательных: 0 (1,1); 1 -1,1; 2 - 1,1; 3 1,1-; 4 1,1 -
+//
+// Параметры форматирования дат:
+//
+//	ДФ  - шаблон даты, как в методе Формат у даты, например ДФ='дд.ММ.гггг ЧЧ:мм'
+//	ДЛФ - локальный формат: Д - дата, ДД - длинная дата, В - время, ДВ и ДДВ - дата со временем
+//	ДП  - представление пустой даты, по умолчанию пустая строка
+//
+// Параметры форматирования булевых значений:
+//
+//	БЛ - представление значения Ложь, по умолчанию "Нет"
+//	БИ - представление значения Истина, по умолчанию "Да"
+
+// formatKeys1C известные параметры форматной строки 1С
+var formatKeys1C = map[string]bool{
+	"Л": true, "ЧЦ": true, "ЧДЦ": true, "ЧС": true, "ЧРД": true, "ЧРГ": true, "ЧГ": true,
+	"ЧН": true, "ЧВН": true, "ЧО": true, "ДФ": true, "ДЛФ": true, "ДП": true, "БЛ": true, "БИ": true,
+}
+
+// IsFormatString1C проверяет, что строка состоит только из известных параметров форматной строки 1С
+func IsFormatString1C(s string) bool {
+	o, err := ParseFormatString(s)
+	return err == nil && len(o) > 0 && o.check() == nil
+}
+
+// hasFormatVerbs проверяет, что в строке есть подстановки формата Go, кроме %%
+func hasFormatVerbs(s string) bool {
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == '%' {
+			if s[i+1] != '%' {
+				return true
+			}
+			i++
+		}
+	}
+	return false
+}
+
+// FormatOptions разобранная форматная строка, ключи в верхнем регистре
+type FormatOptions map[string]string
+
+// ParseFormatString разбирает форматную строку 1С
+func ParseFormatString(s string) (FormatOptions, error) {
+	opts := make(FormatOptions)
+	rs := []rune(s)
+	i := 0
+	for i < len(rs) {
+		for i < len(rs) && (unicode.IsSpace(rs[i]) || rs[i] == ';') {
+			i++
+		}
+		start := i
+		for i < len(rs) && rs[i] != '=' && rs[i] != ';' {
+			i++
+		}
+		name := strings.ToUpper(strings.TrimSpace(string(rs[start:i])))
+		if name == "" {
+			if i < len(rs) && rs[i] == '=' {
+				return nil, VMErrorFormatString
+			}
+			continue
+		}
+		if i >= len(rs) || rs[i] == ';' {
+			opts[name] = "Истина"
+			continue
+		}
+		i++ // '='
+		for i < len(rs) && unicode.IsSpace(rs[i]) {
+			i++
+		}
+		var val string
+		if i < len(rs) && (rs[i] == '\'' || rs[i] == '"') {
+			q := rs[i]
+			i++
+			start = i
+			for i < len(rs) && rs[i] != q {
+				i++
+			}
+			if i >= len(rs) {
+				return nil, VMErrorFormatString
+			}
+			val = string(rs[start:i])
+			i++
+		} else {
+			start = i
+			for i < len(rs) && rs[i] != ';' {
+				i++
+			}
+			val = strings.TrimSpace(string(rs[start:i]))
+		}
+		opts[name] = val
+	}
+	return opts, nil
+}
+
+// check возвращает ошибку с первым по алфавиту неизвестным параметром
+func (o FormatOptions) check() error {
+	rv := ""
+	for k := range o {
+		if !formatKeys1C[k] && (rv == "" || k < rv) {
+			rv = k
+		}
+	}
+	if rv != "" {
+		return fmt.Errorf("%w: неизвестный параметр %s", VMErrorFormatString, rv)
+	}
+	return nil
+}
+
+// intOpt возвращает целочисленный параметр или def
+func (o FormatOptions) intOpt(name string, def int) int {
+	if v, ok := o[name]; ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+func (o FormatOptions) boolOpt(name string) bool {
+	v, ok := o[name]
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(v) {
+	case "", "0", "ложь", "false", "нет":
+		return false
+	}
+	return true
+}
+
+// locale возвращает язык из параметра Л, по умолчанию русский
+func (o FormatOptions) locale() string {
+	l := strings.ToLower(o["Л"])
+	if l == "" {
+		return "ru"
+	}
+	if i := strings.IndexAny(l, "_-"); i > 0 {
+		l = l[:i]
+	}
+	return l
+}
+
+// groupDigits разделяет целую часть на группы разрядов справа налево,
+// последний размер группы повторяется для старших разрядов
+func groupDigits(digits string, sizes []int, sep string) string {
+	if len(sizes) == 0 || sizes[0] <= 0 || sep == "" {
+		return digits
+	}
+	var groups []string
+	gi := 0
+	for len(digits) > 0 {
+		size := sizes[gi]
+		if gi < len(sizes)-1 && sizes[gi+1] > 0 {
+			gi++
+		}
+		if size >= len(digits) {
+			groups = append(groups, digits)
+			break
+		}
+		groups = append(groups, digits[len(digits)-size:])
+		digits = digits[:len(digits)-size]
+	}
+	for l, r := 0, len(groups)-1; l < r; l, r = l+1, r-1 {
+		groups[l], groups[r] = groups[r], groups[l]
+	}
+	return strings.Join(groups, sep)
+}
+
+// FormatNumber форматирует число по параметрам форматной строки 1С
+func FormatNumber(d VMDecNum, o FormatOptions) string {
+	if shift := o.intOpt("ЧС", 0); shift != 0 {
+		d = d.Div(VMDecNum{num: decnum.FromInt64(10)}.Pow(NewVMDecNumFromInt64(int64(shift))))
+	}
+	frac, hasFrac := o["ЧДЦ"]
+	scale := o.intOpt("ЧДЦ", 0)
+	if hasFrac {
+		d = d.RoundMode(int32(scale), decnum.RoundHalfUp)
+	}
+	if d.num.IsZero() {
+		return o["ЧН"]
+	}
+
+	s := d.num.Abs().String()
+	if strings.ContainsAny(s, "Ee") {
+		// экспоненциальная запись получается у значений вроде 1E+3
+		s = d.num.Abs().RoundWithMode(int32(scale), decnum.RoundHalfUp).String()
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if hasFrac && frac != "" {
+		if len(fracPart) < scale {
+			fracPart += strings.Repeat("0", scale-len(fracPart))
+		}
+	}
+
+	if total, ok := o["ЧЦ"]; ok && total != "" {
+		n := o.intOpt("ЧЦ", 0) - len(fracPart)
+		if n < 0 {
+			n = 0
+		}
+		if len(intPart) > n {
+			// не помещается - выводим максимально возможное значение, как в 1С
+			intPart = strings.Repeat("9", n)
+			fracPart = strings.Repeat("9", len(fracPart))
+		} else if o.boolOpt("ЧВН") {
+			intPart = strings.Repeat("0", n-len(intPart)) + intPart
+		}
+	}
+
+	decSep, grpSep := ",", " "
+	if o.locale() == "en" {
+		decSep, grpSep = ".", ","
+	}
+	if v, ok := o["ЧРД"]; ok {
+		decSep = v
+	}
+	if v, ok := o["ЧРГ"]; ok {
+		grpSep = v
+	}
+	sizes := []int{3}
+	if v, ok := o["ЧГ"]; ok {
+		sizes = sizes[:0]
+		for _, p := range strings.Split(v, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil {
+				break
+			}
+			sizes = append(sizes, n)
+		}
+	}
+
+	res := groupDigits(intPart, sizes, grpSep)
+	if fracPart != "" {
+		res += decSep + fracPart
+	}
+	if !d.num.IsNegative() {
+		return res
+	}
+	switch o.intOpt("ЧО", 1) {
+	case 0:
+		return "(" + res + ")"
+	case 2:
+		return "- " + res
+	case 3:
+		return res + "-"
+	case 4:
+		return res + " -"
+	}
+	return "-" + res
+}
+
+// localDateFormats шаблоны для параметра ДЛФ
+var localDateFormats = map[string]string{
+	"Д":   "дд.ММ.гггг",
+	"ДД":  `д ММММ гггг "г."`,
+	"В":   "Ч:мм:сс",
+	"ДВ":  "дд.ММ.гггг Ч:мм:сс",
+	"ДДВ": `д ММММ гггг "г." Ч:мм:сс`,
+}
+
+// FormatDate форматирует дату по параметрам форматной строки 1С
+func FormatDate(t VMTime, o FormatOptions) (string, error) {
+	if t.IsZero() {
+		return o["ДП"], nil
+	}
+	if f, ok := o["ДФ"]; ok {
+		return t.Format1C(f), nil
+	}
+	f := "ДВ"
+	if v, ok := o["ДЛФ"]; ok {
+		f = strings.ToUpper(v)
+	}
+	layout, ok := localDateFormats[f]
+	if !ok {
+		return "", VMErrorFormatString
+	}
+	return t.Format1C(layout), nil
+}
+
+// FormatBool форматирует булево значение по параметрам форматной строки 1С
+func FormatBool(b VMBool, o FormatOptions) string {
+	yes, no := "Да", "Нет"
+	if o.locale() == "en" {
+		yes, no = "Yes", "No"
+	}
+	if b {
+		if v, ok := o["БИ"]; ok {
+			return v
+		}
+		return yes
+	}
+	if v, ok := o["БЛ"]; ok {
+		return v
+	}
+	return no
+}
+
+// Format1C форматирует значение по форматной строке 1С
+func Format1C(v VMValue, format string) (string, error) {
+	o, err := ParseFormatString(format)
+	if err != nil {
+		return "", err
+	}
+	if err := o.check(); err != nil {
+		return "", err
+	}
+	switch vv := v.(type) {
+	case VMInt:
+		return FormatNumber(NewVMDecNumFromInt64(int64(vv)), o), nil
+	case VMDecNum:
+		return FormatNumber(vv, o), nil
+	case VMMoney:
+		return FormatNumber(vv.amount, o), nil
+	case VMTime:
+		return FormatDate(vv, o)
+	case VMBool:
+		return FormatBool(vv, o), nil
+	case VMStringer:
+		return vv.String(), nil
+	}
+	return "", VMErrorFormatString
+}
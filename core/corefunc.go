@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"reflect"
+	"sync"
 )
 
 // VMFunc вызывается как обертка метода объекта метаданных или обертка функции библиотеки
@@ -28,6 +29,23 @@ func (f VMFunc) Func() VMFunc {
 	return f
 }
 
+// scriptFuncCode - адреса кода функций, которым пропущенные при вызове параметры передаются как nil:
+// функций на языке Гонец и оберток, передающих им параметры без изменений
+var scriptFuncCode sync.Map
+
+// RegisterScriptFunc отмечает функции, созданные тем же литералом, что и f.
+// Функция, которая создает такие литералы, не должна встраиваться (go:noinline),
+// иначе у встроенных копий литерала будет другой адрес кода.
+func RegisterScriptFunc(f VMFunc) {
+	scriptFuncCode.Store(reflect.ValueOf(f).Pointer(), true)
+}
+
+// IsScriptFunc проверяет, что f написана на языке Гонец и сама обрабатывает пропущенные параметры
+func IsScriptFunc(f VMFunc) bool {
+	_, ok := scriptFuncCode.Load(reflect.ValueOf(f).Pointer())
+	return ok
+}
+
 type (
 	VMMethod      = VMFunc
 	VMConstructor = func(VMSlice) error
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -43,6 +44,15 @@ func (x *VMHttpRequest) Close() {
 	x.body = nil
 }
 
+// bodyLines возвращает итератор строк тела, читающий его по мере перебора.
+// Если тело уже прочитано, перебираются строки прочитанного тела
+func (x *VMHttpRequest) bodyLines() VMIteratorCloser {
+	if x.body != nil || x.r == nil || x.r.Body == nil {
+		return NewVMLineIterator(bytes.NewReader(x.body), nil)
+	}
+	return NewVMLineIterator(x.r.Body, x.r.Body)
+}
+
 func (x *VMHttpRequest) ReadBody() (b VMString, err error) {
 	if x.body != nil {
 		return VMString(x.body), nil
@@ -146,6 +156,10 @@ func (x *VMHttpRequest) MethodMember(name int) (VMFunc, bool) {
 		return VMFuncTwoParams(x.УстановитьЗаголовок), true
 	case "тело":
 		return VMFuncZeroParams(x.Тело), true
+	case "телодвоичныеданные":
+		return VMFuncZeroParams(x.ТелоДвоичныеДанные), true
+	case "строкитела":
+		return VMFuncZeroParams(x.СтрокиТела), true
 	case "путь":
 		return VMFuncZeroParams(x.Путь), true
 	case "адрес":
@@ -184,6 +198,20 @@ func (x *VMHttpRequest) Тело(rets *VMSlice) error {
 	return nil
 }
 
+// СтрокиТела перебирает строки тела в цикле Для каждого без загрузки всего тела в память
+func (x *VMHttpRequest) СтрокиТела(rets *VMSlice) error {
+	rets.Append(x.bodyLines())
+	return nil
+}
+
+func (x *VMHttpRequest) ТелоДвоичныеДанные(rets *VMSlice) error {
+	if _, err := x.ReadBody(); err != nil {
+		return err
+	}
+	rets.Append(VMBinaryData(x.body))
+	return nil
+}
+
 func (x *VMHttpRequest) Путь(rets *VMSlice) error {
 	rets.Append(x.Path())
 	return nil
@@ -246,6 +274,15 @@ func (x *VMHttpResponse) Close() {
 	x.body = nil
 }
 
+// bodyLines возвращает итератор строк тела, читающий его по мере перебора.
+// Если тело уже прочитано, перебираются строки прочитанного тела
+func (x *VMHttpResponse) bodyLines() VMIteratorCloser {
+	if x.body != nil || x.r == nil || x.r.Body == nil {
+		return NewVMLineIterator(bytes.NewReader(x.body), nil)
+	}
+	return NewVMLineIterator(x.r.Body, x.r.Body)
+}
+
 func (x *VMHttpResponse) ReadBody() (b VMString, err error) {
 	if x.body != nil {
 		return VMString(x.body), nil
@@ -261,6 +298,23 @@ func (x *VMHttpResponse) ReadBody() (b VMString, err error) {
 }
 
 func (x *VMHttpResponse) Send(status VMInt, b VMString, h VMStringMap) error {
+	if err := x.writeHeader(status, h); err != nil {
+		return err
+	}
+	fmt.Fprintln(x.w, b)
+	return nil
+}
+
+// SendBinary отправляет тело ответа как есть, без перевода строки в конце
+func (x *VMHttpResponse) SendBinary(status VMInt, b []byte, h VMStringMap) error {
+	if err := x.writeHeader(status, h); err != nil {
+		return err
+	}
+	_, err := x.w.Write(b)
+	return err
+}
+
+func (x *VMHttpResponse) writeHeader(status VMInt, h VMStringMap) error {
 	hdrs := x.w.Header()
 	for k, v := range h {
 		vv, ok := v.(VMStringer)
@@ -271,8 +325,6 @@ func (x *VMHttpResponse) Send(status VMInt, b VMString, h VMStringMap) error {
 	}
 
 	x.w.WriteHeader(int(status))
-
-	fmt.Fprintln(x.w, b)
 	return nil
 }
 
@@ -307,6 +359,10 @@ func (x *VMHttpResponse) MethodMember(name int) (VMFunc, bool) {
 		return VMFuncOneParam(x.Отправить), true
 	case "сообщение":
 		return VMFuncZeroParams(x.Сообщение), true
+	case "телодвоичныеданные":
+		return VMFuncZeroParams(x.ТелоДвоичныеДанные), true
+	case "строкитела":
+		return VMFuncZeroParams(x.СтрокиТела), true
 	}
 
 	return nil, false
@@ -335,6 +391,9 @@ func (x *VMHttpResponse) Отправить(vsm VMStringMap, rets *VMSlice) erro
 
 	var b VMString
 	if v, ok := vsm["Тело"]; ok {
+		if bin, ok := v.(VMBinaryData); ok {
+			return x.SendBinary(sts, bin, h)
+		}
 		if b, ok = v.(VMString); !ok {
 			return VMErrorNeedString
 		}
@@ -343,6 +402,20 @@ func (x *VMHttpResponse) Отправить(vsm VMStringMap, rets *VMSlice) erro
 	return x.Send(sts, b, h)
 }
 
+// СтрокиТела перебирает строки тела в цикле Для каждого без загрузки всего тела в память
+func (x *VMHttpResponse) СтрокиТела(rets *VMSlice) error {
+	rets.Append(x.bodyLines())
+	return nil
+}
+
+func (x *VMHttpResponse) ТелоДвоичныеДанные(rets *VMSlice) error {
+	if _, err := x.ReadBody(); err != nil {
+		return err
+	}
+	rets.Append(VMBinaryData(x.body))
+	return nil
+}
+
 func (x *VMHttpResponse) Сообщение(rets *VMSlice) error {
 	v, err := x.RequestAsVMStringMap()
 	if err != nil {
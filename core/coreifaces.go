@@ -59,6 +59,26 @@ type (
 		IndexVal(VMValue) VMValue
 	}
 
+	// VMIterator последовательно выдает значения, не загружая всю коллекцию в память
+	VMIterator interface {
+		VMValue
+		Next() (VMValue, bool, error) // значение, признак наличия значения, ошибка
+	}
+
+	// VMIteratorCloser итератор, удерживающий ресурс: курсор, файл, тело ответа, генератор.
+	// Close вызывается при выходе из цикла Для каждого, в том числе по Прервать, Возврат или ошибке,
+	// и может вызываться повторно
+	VMIteratorCloser interface {
+		VMIterator
+		Close() error
+	}
+
+	// VMIterable может перебираться в цикле Для каждого через итератор
+	VMIterable interface {
+		VMValue
+		Iterator() (VMIterator, error)
+	}
+
 	// VMBinaryTyper может сериализовываться в бинарные данные внутри слайсов и структур
 	VMBinaryTyper interface {
 		VMValue
@@ -160,6 +180,19 @@ type (
 		MethodMember(int) (VMFunc, bool) // возвращает метод в нужном формате
 	}
 
+	// VMReleaser ресурс, незавершенные операции которого (например, транзакции)
+	// освобождаются при уничтожении глобального окружения
+	VMReleaser interface {
+		VMValue
+		ReleaseLeaked() error // возвращает ошибку с описанием найденных утечек
+	}
+
+	// VMEnvBinder получает окружение, в котором он создан через Новый
+	VMEnvBinder interface {
+		VMValue
+		BindEnv(*Env)
+	}
+
 	// VMServicer определяет микросервис, который может регистрироваться в главном менеджере сервисов
 	VMServicer interface {
 		VMValue
@@ -175,3 +208,9 @@ type (
 		null()
 	}
 )
+
+// imageFiler сохраняет картинку в файл (штрихкоды), путь к которому можно получить
+type imageFiler interface {
+	VMValue
+	imagePath() string
+}
@@ -150,6 +150,8 @@ func (x VMInt) EvalBinOp(op VMOperation, y VMOperationer) (VMValue, error) {
 			return VMInt(int64(x) * int64(yy)), nil
 		case VMDecNum:
 			return NewVMDecNumFromInt64(int64(x)).Mul(yy), nil
+		case VMMoney:
+			return yy.EvalBinOp(MUL, x)
 		}
 		return VMNil, VMErrorIncorrectOperation
 	case QUO:
@@ -259,6 +261,32 @@ func (x VMInt) EvalBinOp(op VMOperation, y VMOperationer) (VMValue, error) {
 	return VMNil, VMErrorUnknownOperation
 }
 
+// IntBinOp быстро выполняет операцию над двумя целыми числами без приведения к интерфейсам.
+// Для операций, которые могут завершиться ошибкой или дать нецелый результат, возвращает false.
+func IntBinOp(op VMOperation, x, y VMInt) (VMValue, bool) {
+	switch op {
+	case ADD:
+		return x + y, true
+	case SUB:
+		return x - y, true
+	case MUL:
+		return x * y, true
+	case EQL:
+		return VMBool(x == y), true
+	case NEQ:
+		return VMBool(x != y), true
+	case GTR:
+		return VMBool(x > y), true
+	case GEQ:
+		return VMBool(x >= y), true
+	case LSS:
+		return VMBool(x < y), true
+	case LEQ:
+		return VMBool(x <= y), true
+	}
+	return nil, false
+}
+
 func (x VMInt) ConvertToType(nt reflect.Type) (VMValue, error) {
 	switch nt {
 	case ReflectVMInt:
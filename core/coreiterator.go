@@ -0,0 +1,250 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// generatorItem очередное значение генератора или его завершение
+type generatorItem struct {
+	v   VMValue
+	ok  bool
+	err error
+}
+
+// VMGenerator генератор значений: тело выполняется в отдельной горутине
+// и приостанавливается на каждом Выдать, пока потребитель не запросит следующее значение.
+// Используется для функций с оператором Выдать и для ленивых источников данных на Го.
+// Горутина тела ссылается только на состояние генератора, поэтому генератор, на который
+// скрипт больше не ссылается, собирается сборщиком мусора и при этом закрывается
+type VMGenerator struct {
+	VMMetaObj
+	*generatorState
+	guard *generatorGuard
+}
+
+// generatorGuard закрывает генератор, когда скрипт перестает на него ссылаться.
+// Финализатор ставится не на сам VMGenerator: VMInit создает ссылку генератора на себя,
+// а финализаторы объектов, входящих в цикл ссылок, не вызываются
+type generatorGuard struct {
+	s *generatorState
+}
+
+// generatorState состояние генератора, общее для значения в скрипте и горутины тела
+type generatorState struct {
+	mu      sync.Mutex
+	body    func(yield func(VMValue) bool) error
+	started bool
+	done    bool
+	resume  chan bool
+	out     chan generatorItem
+	cur     VMValue
+	env     *Env // окружение, при уничтожении которого закрывается незавершенный генератор
+}
+
+// NewVMGenerator создает генератор, тело которого передает значения в yield.
+// Если yield вернул false, потребитель закрыл генератор и тело должно завершиться
+func NewVMGenerator(body func(yield func(VMValue) bool) error) *VMGenerator {
+	s := &generatorState{
+		body:   body,
+		resume: make(chan bool),
+		out:    make(chan generatorItem),
+		cur:    VMNil,
+	}
+	x := &VMGenerator{generatorState: s, guard: &generatorGuard{s: s}}
+	x.VMInit(x)
+	x.VMRegister()
+	// брошенный генератор закрывается в отдельной горутине, чтобы не задерживать финализаторы
+	runtime.SetFinalizer(x.guard, func(g *generatorGuard) { go g.s.Close() })
+	return x
+}
+
+func (x *VMGenerator) VMTypeString() string { return "Генератор" }
+
+func (x *VMGenerator) String() string { return "Генератор" }
+
+func (x *VMGenerator) VMRegister() {
+	x.VMRegisterMethod("Следующий", VMFuncZeroParams(x.Следующий))
+	x.VMRegisterMethod("Значение", VMFuncZeroParams(x.Значение))
+	x.VMRegisterMethod("Закрыть", VMFuncZeroParams(x.Закрыть))
+	x.VMRegisterMethod("ВМассив", VMFuncZeroParams(x.ВМассив))
+}
+
+// BindEnv регистрирует незавершенный генератор как ресурс окружения: если скрипт еще ссылается
+// на генератор, но не перебрал и не закрыл его, горутина тела завершается при уничтожении окружения.
+// Завершенный, закрытый или собранный сборщиком мусора генератор снимается с учета
+func (x *VMGenerator) BindEnv(env *Env) {
+	x.mu.Lock()
+	x.env = env
+	x.mu.Unlock()
+	env.AddResource(x.generatorState)
+}
+
+func (x *generatorState) VMTypeString() string { return "Генератор" }
+
+// ReleaseLeaked закрывает генератор, брошенный скриптом
+func (x *generatorState) ReleaseLeaked() error {
+	if err := x.Close(); err != nil {
+		return fmt.Errorf("Генератор не был закрыт скриптом: %v", err)
+	}
+	return nil
+}
+
+// finish отмечает генератор завершенным и снимает его с учета в окружении, вызывается под блокировкой
+func (x *generatorState) finish() {
+	x.done = true
+	x.cur = VMNil
+	if x.env != nil {
+		x.env.RemoveResource(x)
+		x.env = nil
+	}
+}
+
+func (x *generatorState) run() {
+	closed := false
+	yield := func(v VMValue) bool {
+		if closed {
+			return false
+		}
+		x.out <- generatorItem{v: v, ok: true}
+		if !<-x.resume {
+			closed = true
+		}
+		return !closed
+	}
+	go func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+			x.out <- generatorItem{err: err}
+		}()
+		err = x.body(yield)
+	}()
+}
+
+// Next возобновляет тело генератора до следующего Выдать или до завершения
+func (x *generatorState) Next() (VMValue, bool, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.done {
+		return VMNil, false, nil
+	}
+	if x.started {
+		x.resume <- true
+	} else {
+		x.started = true
+		x.run()
+	}
+	it := <-x.out
+	if !it.ok {
+		x.finish()
+		return VMNil, false, it.err
+	}
+	x.cur = it.v
+	return it.v, true, nil
+}
+
+// Close прерывает тело генератора, приостановленное на Выдать, и дожидается его завершения
+func (x *generatorState) Close() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.done {
+		return nil
+	}
+	x.finish()
+	if !x.started {
+		return nil
+	}
+	x.resume <- false
+	// после закрытия yield сразу возвращает false, поэтому следующее сообщение - завершение тела
+	it := <-x.out
+	return it.err
+}
+
+// Следующий переходит к следующему значению, возвращает Ложь, если значений больше нет
+func (x *VMGenerator) Следующий(rets *VMSlice) error {
+	_, ok, err := x.Next()
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBool(ok))
+	return nil
+}
+
+// Значение возвращает текущее значение, полученное последним вызовом Следующий
+func (x *VMGenerator) Значение(rets *VMSlice) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	rets.Append(x.cur)
+	return nil
+}
+
+func (x *VMGenerator) Закрыть(rets *VMSlice) error {
+	return x.Close()
+}
+
+// ВМассив выбирает все оставшиеся значения генератора
+func (x *VMGenerator) ВМассив(rets *VMSlice) error {
+	sl := VMSlice{}
+	for {
+		v, ok, err := x.Next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		sl = append(sl, v)
+	}
+	rets.Append(sl)
+	return nil
+}
+
+// lineIterator построчно читает поток, окончания строк \n и \r\n отбрасываются.
+// По достижении конца потока или при выходе из цикла закрывает closer
+type lineIterator struct {
+	r      *bufio.Reader
+	closer io.Closer
+}
+
+// NewVMLineIterator возвращает итератор строк потока r, closer может быть nil
+func NewVMLineIterator(r io.Reader, closer io.Closer) VMIteratorCloser {
+	return &lineIterator{r: bufio.NewReader(r), closer: closer}
+}
+
+func (x *lineIterator) VMTypeString() string { return "ИтераторСтрок" }
+
+func (x *lineIterator) Interface() interface{} { return x }
+
+func (x *lineIterator) Next() (VMValue, bool, error) {
+	if x.r == nil {
+		return VMNil, false, nil
+	}
+	s, err := x.r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		x.Close()
+		return VMNil, false, err
+	}
+	if err == io.EOF && s == "" {
+		return VMNil, false, x.Close()
+	}
+	s = strings.TrimSuffix(s, "\n")
+	s = strings.TrimSuffix(s, "\r")
+	return VMString(s), true, nil
+}
+
+func (x *lineIterator) Close() error {
+	x.r = nil
+	if x.closer == nil {
+		return nil
+	}
+	c := x.closer
+	x.closer = nil
+	return c.Close()
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestGeneratorReleasedWithEnv(t *testing.T) {
+	env := NewEnv()
+	exited := make(chan struct{})
+	gen := NewVMGenerator(func(yield func(VMValue) bool) error {
+		defer close(exited)
+		for i := 0; yield(VMInt(i)); i++ {
+		}
+		return nil
+	})
+	gen.BindEnv(env)
+	if v, ok, err := gen.Next(); err != nil || !ok || v != VMInt(0) {
+		t.Fatalf("Next() = %v, %v, %v", v, ok, err)
+	}
+
+	env.Destroy()
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("тело брошенного генератора не завершилось при уничтожении окружения")
+	}
+	if _, ok, _ := gen.Next(); ok {
+		t.Errorf("закрытый генератор не должен выдавать значения")
+	}
+}
+
+func TestGeneratorRemovedFromEnv(t *testing.T) {
+	env := NewEnv()
+	done := NewVMGenerator(func(yield func(VMValue) bool) error {
+		yield(VMInt(1))
+		return nil
+	})
+	done.BindEnv(env)
+	closed := NewVMGenerator(func(yield func(VMValue) bool) error { return nil })
+	closed.BindEnv(env)
+
+	for {
+		if _, ok, err := done.Next(); err != nil || !ok {
+			break
+		}
+	}
+	closed.Close()
+	if n := resourceCount(env); n != 0 {
+		t.Errorf("завершенные генераторы остались в окружении: %d", n)
+	}
+}
+
+func resourceCount(env *Env) int {
+	env.RLock()
+	defer env.RUnlock()
+	return len(env.resources)
+}
+
+func TestGeneratorAbandoned(t *testing.T) {
+	env := NewEnv()
+	before := runtime.NumGoroutine()
+	for n := 0; n < 1000; n++ {
+		gen := NewVMGenerator(func(yield func(VMValue) bool) error {
+			for i := 0; yield(VMInt(i)); i++ {
+			}
+			return nil
+		})
+		gen.BindEnv(env)
+		if _, ok, err := gen.Next(); err != nil || !ok {
+			t.Fatalf("Next() = %v, %v", ok, err)
+		}
+	}
+
+	// окружение живет дальше, как у сервера, а брошенные генераторы закрываются сборщиком мусора
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before+10 || resourceCount(env) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("горутин %d, было %d, генераторов в окружении %d", runtime.NumGoroutine(), before, resourceCount(env))
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"reflect"
+	"strings"
 
 	"github.com/shinanca/gonec/names"
 )
@@ -68,6 +69,16 @@ func (x VMStringMap) MethodMember(name int) (VMFunc, bool) {
 		return VMFuncZeroParams(x.Значения), true
 	case "удалить":
 		return VMFuncOneParam(x.Удалить), true
+	case "вставить":
+		return VMFuncTwoParams(x.Вставить), true
+	case "очистить":
+		return VMFuncZeroParams(x.Очистить), true
+	case "свойство":
+		return VMFuncOneParamOptionals(1, x.Свойство), true
+	case "вставитьвсе":
+		return VMFuncOneParam(x.ВставитьВсе), true
+	case "слить":
+		return VMFuncOneParam(x.Слить), true
 	}
 
 	return nil, false
@@ -280,6 +291,8 @@ func (x VMStringMap) ConvertToType(nt reflect.Type) (VMValue, error) {
 	// case ReflectVMSlice:
 	case ReflectVMStringMap:
 		return x, nil
+	case ReflectVMOrderedMap:
+		return NewVMOrderedMapFromStringMap(x), nil
 	}
 
 	if nt.Kind() == reflect.Struct {
@@ -444,3 +457,13 @@ func (x *VMStringMap) UnmarshalJSON(data []byte) error {
 	*x = sm
 	return nil
 }
+
+// structParam возвращает значение поля структуры параметров без учета регистра имени
+func structParam(params VMStringMap, name string) (VMValue, bool) {
+	for k, v := range params {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
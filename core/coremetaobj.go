@@ -77,6 +77,10 @@ func (v *VMMetaObj) VMRegisterField(name string, m VMValue) {
 		*VMChan, *VMDecNum, *VMStringMap,
 		*VMSlice, *VMTime, *VMTimeDuration:
 
+		namtyp := names.UniqueNames.Set(name)
+		v.vmMetaCacheF[namtyp] = m
+	case VMMetaObject:
+		// вложенный объект доступен только для чтения, но его поля и методы можно изменять
 		namtyp := names.UniqueNames.Set(name)
 		v.vmMetaCacheF[namtyp] = m
 	default:
@@ -110,6 +114,8 @@ func (v *VMMetaObj) VMGetField(name int) VMValue {
 			return *rv
 		case *VMTimeDuration:
 			return *rv
+		case VMMetaObject:
+			return rv
 		}
 	}
 	panic("Невозможно получить значение поля")
@@ -0,0 +1,450 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/covrom/decnum"
+	"github.com/shinanca/gonec/names"
+)
+
+// режимы округления доступны из языка как значения структуры РежимОкругления
+var roundingModes = map[string]decnum.RoundingMode{
+	"окр15как20": decnum.RoundHalfUp,   // половина округляется от нуля, как в 1С по умолчанию
+	"окр15как10": decnum.RoundHalfDown, // половина округляется к нулю
+	"банковское": decnum.RoundHalfEven, // половина округляется к четной цифре
+	"вверх":      decnum.RoundCeiling,
+	"вниз":       decnum.RoundFloor,
+	"кнулю":      decnum.RoundDown,
+	"отнуля":     decnum.RoundUp,
+}
+
+// RoundingModes возвращает структуру РежимОкругления
+func RoundingModes() VMStringMap {
+	rv := make(VMStringMap, len(roundingModes))
+	for _, s := range []string{"Окр15как20", "Окр15как10", "Банковское", "Вверх", "Вниз", "КНулю", "ОтНуля"} {
+		rv[s] = VMString(s)
+	}
+	return rv
+}
+
+// roundingModeArg возвращает режим округления из необязательного аргумента с индексом i
+func roundingModeArg(args VMSlice, i int) (decnum.RoundingMode, error) {
+	if i >= len(args) {
+		return decnum.RoundHalfUp, nil
+	}
+	s, ok := args[i].(VMString)
+	if !ok {
+		return 0, VMErrorRoundingMode
+	}
+	m, ok := roundingModes[strings.ToLower(string(s))]
+	if !ok {
+		return 0, VMErrorRoundingMode
+	}
+	return m, nil
+}
+
+// RoundMode округляет до n знаков после запятой (при n < 0 - до десятков, сотен и т.д.)
+func (x VMDecNum) RoundMode(n int32, mode decnum.RoundingMode) VMDecNum {
+	return VMDecNum{num: x.num.RoundWithMode(n, mode)}
+}
+
+// Окр(Число, Разрядность, РежимОкругления) - разрядность по умолчанию 0, режим Окр15как20
+func Окр(args VMSlice, rets *VMSlice) error {
+	n := VMInt(0)
+	if len(args) > 1 {
+		var ok bool
+		if n, ok = args[1].(VMInt); !ok {
+			return VMErrorNeedInt
+		}
+	}
+	mode, err := roundingModeArg(args, 2)
+	if err != nil {
+		return err
+	}
+	switch v := args[0].(type) {
+	case VMInt:
+		if n >= 0 {
+			rets.Append(v)
+			return nil
+		}
+		rets.Append(VMInt(NewVMDecNumFromInt64(int64(v)).RoundMode(int32(n), mode).Int()))
+	case VMDecNum:
+		rets.Append(v.RoundMode(int32(n), mode))
+	case VMMoney:
+		rets.Append(VMMoney{amount: v.amount.RoundMode(int32(n), mode).RoundMode(v.scale, mode), scale: v.scale, currency: v.currency})
+	default:
+		return VMErrorNeedDecNum
+	}
+	return nil
+}
+
+// Distribute распределяет сумму пропорционально коэффициентам с точностью scale знаков.
+// Погрешность округления относится на элемент с наибольшим по модулю коэффициентом,
+// поэтому сумма частей всегда равна исходной сумме.
+func Distribute(sum VMDecNum, coefs []VMDecNum, scale int32) ([]VMDecNum, error) {
+	total := VMDecNumZero
+	maxi := -1
+	for i, c := range coefs {
+		total = total.Add(c)
+		if maxi < 0 || c.num.Abs().Greater(coefs[maxi].num.Abs()) {
+			maxi = i
+		}
+	}
+	if maxi < 0 || total.num.IsZero() {
+		return nil, VMErrorDistributeCoefs
+	}
+	rv := make([]VMDecNum, len(coefs))
+	rest := sum
+	for i, c := range coefs {
+		rv[i] = sum.Mul(c).Div(total).RoundMode(scale, decnum.RoundHalfUp)
+		rest = rest.Sub(rv[i])
+	}
+	rv[maxi] = rv[maxi].Add(rest)
+	return rv, nil
+}
+
+func decNumArgs(sl VMSlice) ([]VMDecNum, error) {
+	rv := make([]VMDecNum, len(sl))
+	for i, v := range sl {
+		n, ok := v.(VMNumberer)
+		if !ok {
+			return nil, VMErrorNeedDecNum
+		}
+		rv[i] = n.DecNum()
+	}
+	return rv, nil
+}
+
+// РаспределитьПропорционально(Сумма, Коэффициенты, Точность) возвращает массив частей суммы,
+// для Денег точность берется из суммы, иначе по умолчанию 2
+func РаспределитьПропорционально(args VMSlice, rets *VMSlice) error {
+	coefs, ok := args[1].(VMSlice)
+	if !ok {
+		return VMErrorNeedSlice
+	}
+	if m, ok := args[0].(VMMoney); ok {
+		parts, err := m.Distribute(coefs)
+		if err != nil {
+			return err
+		}
+		rets.Append(parts)
+		return nil
+	}
+	sum, ok := args[0].(VMNumberer)
+	if !ok {
+		return VMErrorNeedDecNum
+	}
+	scale := VMInt(2)
+	if len(args) > 2 {
+		if scale, ok = args[2].(VMInt); !ok {
+			return VMErrorNeedInt
+		}
+	}
+	cs, err := decNumArgs(coefs)
+	if err != nil {
+		return err
+	}
+	parts, err := Distribute(sum.DecNum(), cs, int32(scale))
+	if err != nil {
+		return err
+	}
+	rv := make(VMSlice, len(parts))
+	for i, p := range parts {
+		rv[i] = p
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// VMMoney денежная сумма с фиксированным количеством знаков после запятой.
+// Операции, результат которых не представим с этой точностью, завершаются ошибкой,
+// а не округляются незаметно: для умножения с округлением есть метод Умножить,
+// для деления - Распределить.
+type VMMoney struct {
+	amount   VMDecNum
+	scale    int32
+	currency string
+}
+
+var ReflectVMMoney = reflect.TypeOf(VMMoney{})
+
+// NewVMMoney создает денежную сумму, сумма должна быть представима с точностью scale знаков
+func NewVMMoney(amount VMDecNum, currency string, scale int32) (VMMoney, error) {
+	q := amount.RoundMode(scale, decnum.RoundHalfUp)
+	if !q.num.Equal(amount.num) {
+		return VMMoney{}, VMErrorMoneyPrecision
+	}
+	return VMMoney{amount: q, scale: scale, currency: currency}, nil
+}
+
+// Деньги(Сумма, Валюта, Точность) - точность по умолчанию 2 знака
+func Деньги(args VMSlice, rets *VMSlice) error {
+	var amount VMDecNum
+	switch v := args[0].(type) {
+	case VMNumberer:
+		amount = v.DecNum()
+	case VMString:
+		d, err := ParseVMDecNum(strings.ReplaceAll(string(v), ",", "."))
+		if err != nil {
+			return VMErrorNeedDecNum
+		}
+		amount = d
+	default:
+		return VMErrorNeedDecNum
+	}
+	var currency string
+	if len(args) > 1 {
+		s, ok := args[1].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		currency = string(s)
+	}
+	scale := VMInt(2)
+	if len(args) > 2 {
+		var ok bool
+		if scale, ok = args[2].(VMInt); !ok || scale < 0 || scale > 18 {
+			return VMErrorNeedInt
+		}
+	}
+	m, err := NewVMMoney(amount, currency, int32(scale))
+	if err != nil {
+		return err
+	}
+	rets.Append(m)
+	return nil
+}
+
+func (x VMMoney) VMTypeString() string { return "Деньги" }
+
+func (x VMMoney) Interface() interface{} {
+	return x.amount.Float()
+}
+
+func (x VMMoney) String() string {
+	if x.currency == "" {
+		return x.amount.String()
+	}
+	return x.amount.String() + " " + x.currency
+}
+
+func (x VMMoney) DecNum() VMDecNum {
+	return x.amount
+}
+
+func (x VMMoney) sameCurrency(y VMMoney) error {
+	if x.currency != y.currency {
+		return VMErrorMoneyCurrency
+	}
+	return nil
+}
+
+// exact возвращает сумму в той же валюте, если результат представим с точностью суммы
+func (x VMMoney) exact(d VMDecNum) (VMMoney, error) {
+	return NewVMMoney(d, x.currency, x.scale)
+}
+
+// Distribute делит сумму на части пропорционально коэффициентам без потери копеек
+func (x VMMoney) Distribute(coefs VMSlice) (VMSlice, error) {
+	cs, err := decNumArgs(coefs)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := Distribute(x.amount, cs, x.scale)
+	if err != nil {
+		return nil, err
+	}
+	rv := make(VMSlice, len(parts))
+	for i, p := range parts {
+		rv[i] = VMMoney{amount: p, scale: x.scale, currency: x.currency}
+	}
+	return rv, nil
+}
+
+func (x VMMoney) EvalUnOp(op rune) (VMValue, error) {
+	switch op {
+	case '-':
+		return VMMoney{amount: x.amount.Mul(VMDecNumNegOne), scale: x.scale, currency: x.currency}, nil
+	}
+	return VMNil, VMErrorUnknownOperation
+}
+
+func (x VMMoney) EvalBinOp(op VMOperation, y VMOperationer) (VMValue, error) {
+	switch op {
+	case ADD, SUB:
+		yy, ok := y.(VMMoney)
+		if !ok {
+			return VMNil, VMErrorIncorrectOperation
+		}
+		if err := x.sameCurrency(yy); err != nil {
+			return VMNil, err
+		}
+		scale := x.scale
+		if yy.scale > scale {
+			scale = yy.scale
+		}
+		r := x.amount.Add(yy.amount)
+		if op == SUB {
+			r = x.amount.Sub(yy.amount)
+		}
+		return VMMoney{amount: r.RoundMode(scale, decnum.RoundHalfUp), scale: scale, currency: x.currency}, nil
+	case MUL:
+		yy, ok := y.(VMNumberer)
+		if !ok {
+			return VMNil, VMErrorIncorrectOperation
+		}
+		return x.exact(x.amount.Mul(yy.DecNum()))
+	case QUO:
+		switch yy := y.(type) {
+		case VMMoney:
+			// отношение сумм - это число
+			if err := x.sameCurrency(yy); err != nil {
+				return VMNil, err
+			}
+			if yy.amount.num.IsZero() {
+				return VMNil, VMErrorDivisionByZero
+			}
+			return x.amount.Div(yy.amount), nil
+		case VMNumberer:
+			if yy.DecNum().num.IsZero() {
+				return VMNil, VMErrorDivisionByZero
+			}
+			return x.exact(x.amount.Div(yy.DecNum()))
+		}
+		return VMNil, VMErrorIncorrectOperation
+	case EQL, NEQ, GTR, GEQ, LSS, LEQ:
+		yy, ok := y.(VMMoney)
+		if !ok {
+			if op == EQL {
+				return VMBool(false), nil
+			}
+			if op == NEQ {
+				return VMBool(true), nil
+			}
+			return VMNil, VMErrorIncorrectOperation
+		}
+		if err := x.sameCurrency(yy); err != nil {
+			return VMNil, err
+		}
+		return x.amount.EvalBinOp(op, yy.amount)
+	}
+	return VMNil, VMErrorIncorrectOperation
+}
+
+func (x VMMoney) ConvertToType(nt reflect.Type) (VMValue, error) {
+	switch nt {
+	case ReflectVMString:
+		return VMString(x.String()), nil
+	case ReflectVMDecNum:
+		return x.amount, nil
+	case ReflectVMMoney:
+		return x, nil
+	}
+	return VMNil, VMErrorNotConverted
+}
+
+func (x VMMoney) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   VMDecNum `json:"Сумма"`
+		Currency string   `json:"Валюта,omitempty"`
+	}{x.amount, x.currency})
+}
+
+func (x VMMoney) MethodMember(name int) (VMFunc, bool) {
+	// только эти методы будут доступны из кода на языке Гонец!
+	switch names.UniqueNames.GetLowerCase(name) {
+	case "сумма":
+		return VMFuncZeroParams(x.Сумма), true
+	case "валюта":
+		return VMFuncZeroParams(x.Валюта), true
+	case "точность":
+		return VMFuncZeroParams(x.Точность), true
+	case "умножить":
+		return VMFuncOneParamOptionals(1, x.Умножить), true
+	case "разделить":
+		return VMFuncOneParamOptionals(1, x.Разделить), true
+	case "распределить":
+		return VMFuncOneParam(x.Распределить), true
+	case "формат":
+		return VMFuncNParamsOptionals(0, 1, x.Формат), true
+	}
+	return nil, false
+}
+
+func (x VMMoney) Сумма(rets *VMSlice) error {
+	rets.Append(x.amount)
+	return nil
+}
+
+func (x VMMoney) Валюта(rets *VMSlice) error {
+	rets.Append(VMString(x.currency))
+	return nil
+}
+
+func (x VMMoney) Точность(rets *VMSlice) error {
+	rets.Append(VMInt(x.scale))
+	return nil
+}
+
+// Умножить(Число, РежимОкругления) умножает с явным округлением до точности суммы
+func (x VMMoney) Умножить(k VMNumberer, rest VMSlice, rets *VMSlice) error {
+	mode, err := roundingModeArg(rest, 0)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMMoney{amount: x.amount.Mul(k.DecNum()).RoundMode(x.scale, mode), scale: x.scale, currency: x.currency})
+	return nil
+}
+
+// Разделить(Число, РежимОкругления) делит с явным округлением до точности суммы,
+// остаток при этом теряется - для деления без потерь используйте Распределить
+func (x VMMoney) Разделить(k VMNumberer, rest VMSlice, rets *VMSlice) error {
+	if k.DecNum().num.IsZero() {
+		return VMErrorDivisionByZero
+	}
+	mode, err := roundingModeArg(rest, 0)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMMoney{amount: x.amount.Div(k.DecNum()).RoundMode(x.scale, mode), scale: x.scale, currency: x.currency})
+	return nil
+}
+
+// Распределить(Коэффициенты) возвращает массив сумм, в точности дающих в сумме исходную
+func (x VMMoney) Распределить(coefs VMSlice, rets *VMSlice) error {
+	parts, err := x.Distribute(coefs)
+	if err != nil {
+		return err
+	}
+	rets.Append(parts)
+	return nil
+}
+
+// Формат(ФорматнаяСтрока) форматирует сумму по правилам 1С, по умолчанию с точностью суммы
+// и разделителями групп, за суммой следует код валюты
+func (x VMMoney) Формат(args VMSlice, rets *VMSlice) error {
+	f := "ЧДЦ=" + strconv.Itoa(int(x.scale)) + "; ЧН=0"
+	if len(args) > 0 {
+		s, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		f += "; " + string(s)
+	}
+	opts, err := ParseFormatString(f)
+	if err != nil {
+		return err
+	}
+	if err := opts.check(); err != nil {
+		return err
+	}
+	s := FormatNumber(x.amount, opts)
+	if x.currency != "" {
+		s += " " + x.currency
+	}
+	rets.Append(VMString(s))
+	return nil
+}
@@ -0,0 +1,486 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/covrom/decnum"
+	"github.com/shinanca/gonec/names"
+)
+
+// VMOrderedMap - соответствие: ключи любых типов, порядок обхода и сериализации совпадает с порядком добавления.
+// Числа сравниваются по значению (1 и 1.0 - один ключ), даты - по моменту времени,
+// массивы, структуры и другие составные значения - по хэшу.
+// Соответствие ссылочное: при присваивании и передаче в функции копия не создается.
+type VMOrderedMap struct {
+	keys  []VMValue
+	vals  []VMValue
+	index map[string]int // нормализованный ключ - позиция в keys и vals
+}
+
+var ReflectVMOrderedMap = reflect.TypeOf(VMOrderedMap{})
+
+func NewVMOrderedMap() *VMOrderedMap {
+	return &VMOrderedMap{index: make(map[string]int)}
+}
+
+// orderedMapKey возвращает нормализованное представление ключа для поиска
+func orderedMapKey(k VMValue) (string, error) {
+	switch kk := k.(type) {
+	case VMString:
+		return "s" + string(kk), nil
+	case VMInt:
+		return "n" + strconv.FormatInt(int64(kk), 10), nil
+	case VMDecNum:
+		if kk.num.ToIntegral(decnum.RoundDown).Equal(kk.num) {
+			if n, err := kk.num.ToInt64(decnum.RoundDown); err == nil {
+				return "n" + strconv.FormatInt(n, 10), nil
+			}
+		}
+		s := kk.num.String()
+		if strings.Contains(s, ".") && !strings.ContainsAny(s, "Ee") {
+			s = strings.TrimRight(s, "0")
+		}
+		return "n" + s, nil
+	case VMBool:
+		if kk {
+			return "b1", nil
+		}
+		return "b0", nil
+	case VMTime:
+		return "t" + time.Time(kk).UTC().Format(time.RFC3339Nano), nil
+	case VMNilType:
+		return "u", nil
+	case VMHasher:
+		return "h" + string(kk.Hash()), nil
+	}
+	return "", VMErrorNeedHash
+}
+
+func (x *VMOrderedMap) VMTypeString() string { return "Соответствие" }
+
+func (x *VMOrderedMap) Interface() interface{} {
+	return x
+}
+
+func (x *VMOrderedMap) init() {
+	if x.index == nil {
+		x.index = make(map[string]int)
+	}
+}
+
+func (x *VMOrderedMap) Length() VMInt {
+	return VMInt(len(x.keys))
+}
+
+// IndexVal возвращает значение по ключу или Неопределено
+func (x *VMOrderedMap) IndexVal(k VMValue) VMValue {
+	v, _, err := x.Get(k)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (x *VMOrderedMap) Get(k VMValue) (VMValue, bool, error) {
+	hk, err := orderedMapKey(k)
+	if err != nil {
+		return VMNil, false, err
+	}
+	if i, ok := x.index[hk]; ok {
+		return x.vals[i], true, nil
+	}
+	return VMNil, false, nil
+}
+
+// Set добавляет ключ в конец или заменяет значение существующего ключа, не меняя его позиции
+func (x *VMOrderedMap) Set(k, v VMValue) error {
+	hk, err := orderedMapKey(k)
+	if err != nil {
+		return err
+	}
+	x.init()
+	if i, ok := x.index[hk]; ok {
+		x.vals[i] = v
+		return nil
+	}
+	x.index[hk] = len(x.keys)
+	x.keys = append(x.keys, k)
+	x.vals = append(x.vals, v)
+	return nil
+}
+
+func (x *VMOrderedMap) Delete(k VMValue) error {
+	hk, err := orderedMapKey(k)
+	if err != nil {
+		return err
+	}
+	i, ok := x.index[hk]
+	if !ok {
+		return nil
+	}
+	delete(x.index, hk)
+	x.keys = append(x.keys[:i], x.keys[i+1:]...)
+	x.vals = append(x.vals[:i], x.vals[i+1:]...)
+	for j := i; j < len(x.keys); j++ {
+		hk, _ := orderedMapKey(x.keys[j])
+		x.index[hk] = j
+	}
+	return nil
+}
+
+// Copy возвращает копию соответствия, вложенные массивы и структуры копируются рекурсивно
+func (x *VMOrderedMap) Copy() *VMOrderedMap {
+	rv := NewVMOrderedMap()
+	for i, k := range x.keys {
+		v := x.vals[i]
+		switch vv := v.(type) {
+		case VMSlice:
+			v = vv.CopyRecursive()
+		case VMStringMap:
+			v = vv.CopyRecursive()
+		case *VMOrderedMap:
+			v = vv.Copy()
+		}
+		rv.Set(k, v)
+	}
+	return rv
+}
+
+// StringMap возвращает структуру, ключами которой становятся строковые представления ключей
+func (x *VMOrderedMap) StringMap() VMStringMap {
+	rv := make(VMStringMap, len(x.keys))
+	for i, k := range x.keys {
+		rv[orderedMapKeyString(k)] = x.vals[i]
+	}
+	return rv
+}
+
+func orderedMapKeyString(k VMValue) string {
+	if s, ok := k.(VMStringer); ok {
+		return s.String()
+	}
+	return ""
+}
+
+// NewVMOrderedMapFromStringMap создает соответствие из структуры, ключи добавляются по возрастанию
+func NewVMOrderedMapFromStringMap(sm VMStringMap) *VMOrderedMap {
+	ks := make([]string, 0, len(sm))
+	for k := range sm {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	rv := NewVMOrderedMap()
+	for _, k := range ks {
+		rv.Set(VMString(k), sm[k])
+	}
+	return rv
+}
+
+// orderedMapIterator перебирает пары КлючИЗначение в порядке добавления
+type orderedMapIterator struct {
+	keys, vals []VMValue
+	pos        int
+}
+
+func (x *orderedMapIterator) VMTypeString() string { return "ИтераторСоответствия" }
+
+func (x *orderedMapIterator) Interface() interface{} { return x }
+
+func (x *orderedMapIterator) Next() (VMValue, bool, error) {
+	if x.pos >= len(x.keys) {
+		return VMNil, false, nil
+	}
+	rv := VMStringMap{"Ключ": x.keys[x.pos], "Значение": x.vals[x.pos]}
+	x.pos++
+	return rv, true, nil
+}
+
+// Iterator обходит снимок соответствия, поэтому его можно изменять внутри цикла
+func (x *VMOrderedMap) Iterator() (VMIterator, error) {
+	return &orderedMapIterator{
+		keys: append([]VMValue(nil), x.keys...),
+		vals: append([]VMValue(nil), x.vals...),
+	}, nil
+}
+
+func (x *VMOrderedMap) MethodMember(name int) (VMFunc, bool) {
+	// только эти методы будут доступны из кода на языке Гонец!
+
+	switch names.UniqueNames.GetLowerCase(name) {
+	case "вставить":
+		return VMFuncNParamsOptionals(1, 1, x.Вставить), true
+	case "получить":
+		return VMFuncNParamsOptionals(1, 1, x.Получить), true
+	case "содержит":
+		return VMFuncNParams(1, x.Содержит), true
+	case "удалить":
+		return VMFuncNParams(1, x.Удалить), true
+	case "очистить":
+		return VMFuncZeroParams(x.Очистить), true
+	case "количество":
+		return VMFuncZeroParams(x.Количество), true
+	case "ключи":
+		return VMFuncZeroParams(x.Ключи), true
+	case "значения":
+		return VMFuncZeroParams(x.Значения), true
+	case "скопировать":
+		return VMFuncZeroParams(x.Скопировать), true
+	case "вструктуру":
+		return VMFuncZeroParams(x.ВСтруктуру), true
+	}
+
+	return nil, false
+}
+
+// Вставить(Ключ[, Значение]) добавляет пару, для существующего ключа значение заменяется
+func (x *VMOrderedMap) Вставить(args VMSlice, rets *VMSlice) error {
+	var v VMValue = VMNil
+	if len(args) > 1 {
+		v = args[1]
+	}
+	return x.Set(args[0], v)
+}
+
+// Получить(Ключ[, ПоУмолчанию]) возвращает значение по ключу, а если ключа нет - значение по умолчанию
+func (x *VMOrderedMap) Получить(args VMSlice, rets *VMSlice) error {
+	v, ok, err := x.Get(args[0])
+	if err != nil {
+		return err
+	}
+	if !ok && len(args) > 1 {
+		v = args[1]
+	}
+	rets.Append(v)
+	return nil
+}
+
+func (x *VMOrderedMap) Содержит(args VMSlice, rets *VMSlice) error {
+	_, ok, err := x.Get(args[0])
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBool(ok))
+	return nil
+}
+
+func (x *VMOrderedMap) Удалить(args VMSlice, rets *VMSlice) error {
+	return x.Delete(args[0])
+}
+
+func (x *VMOrderedMap) Очистить(rets *VMSlice) error {
+	x.keys, x.vals = nil, nil
+	x.index = make(map[string]int)
+	return nil
+}
+
+func (x *VMOrderedMap) Количество(rets *VMSlice) error {
+	rets.Append(x.Length())
+	return nil
+}
+
+// Ключи возвращаются в порядке добавления
+func (x *VMOrderedMap) Ключи(rets *VMSlice) error {
+	rets.Append(append(VMSlice{}, x.keys...))
+	return nil
+}
+
+// Значения возвращаются в порядке добавления ключей
+func (x *VMOrderedMap) Значения(rets *VMSlice) error {
+	rets.Append(append(VMSlice{}, x.vals...))
+	return nil
+}
+
+func (x *VMOrderedMap) Скопировать(rets *VMSlice) error {
+	rets.Append(x.Copy())
+	return nil
+}
+
+// ВСтруктуру() возвращает структуру со строковыми представлениями ключей
+func (x *VMOrderedMap) ВСтруктуру(rets *VMSlice) error {
+	rets.Append(x.StringMap())
+	return nil
+}
+
+func (x *VMOrderedMap) EvalBinOp(op VMOperation, y VMOperationer) (VMValue, error) {
+	switch op {
+	case EQL, NEQ:
+		yy, ok := y.(*VMOrderedMap)
+		if !ok {
+			return VMNil, VMErrorIncorrectOperation
+		}
+		eq := len(x.keys) == len(yy.keys)
+		for i := 0; eq && i < len(x.keys); i++ {
+			v, found, err := yy.Get(x.keys[i])
+			eq = err == nil && found && EqualVMValues(x.vals[i], v)
+		}
+		if op == NEQ {
+			eq = !eq
+		}
+		return VMBool(eq), nil
+	}
+	return VMNil, VMErrorIncorrectOperation
+}
+
+func (x *VMOrderedMap) ConvertToType(nt reflect.Type) (VMValue, error) {
+	switch nt {
+	case ReflectVMString:
+		b, err := json.Marshal(x)
+		if err != nil {
+			return VMNil, err
+		}
+		return VMString(string(b)), nil
+	case ReflectVMStringMap:
+		return x.StringMap(), nil
+	case ReflectVMOrderedMap:
+		return x, nil
+	}
+	return VMNil, VMErrorNotConverted
+}
+
+func (x *VMOrderedMap) BinaryType() VMBinaryType {
+	return VMORDEREDMAP
+}
+
+func writeBinaryTyped(buf *bytes.Buffer, v VMValue) error {
+	bt, ok := v.(VMBinaryTyper)
+	if !ok {
+		return VMErrorNotBinaryConverted
+	}
+	bb, err := bt.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	buf.WriteByte(byte(bt.BinaryType()))                    // тип
+	binary.Write(buf, binary.LittleEndian, uint64(len(bb))) // длина в байтах
+	buf.Write(bb)                                           // байты
+	return nil
+}
+
+func readBinaryTyped(buf *bytes.Buffer) (VMValue, error) {
+	tt, err := buf.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	var l uint64
+	if err := binary.Read(buf, binary.LittleEndian, &l); err != nil {
+		return nil, err
+	}
+	return VMBinaryType(tt).ParseBinary(buf.Next(int(l)))
+}
+
+func (x *VMOrderedMap) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint64(len(x.keys))) // количество пар ключ-значение
+	for i := range x.keys {
+		if err := writeBinaryTyped(&buf, x.keys[i]); err != nil {
+			return nil, err
+		}
+		if err := writeBinaryTyped(&buf, x.vals[i]); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (x *VMOrderedMap) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	var l uint64
+	if err := binary.Read(buf, binary.LittleEndian, &l); err != nil {
+		return err
+	}
+	rv := NewVMOrderedMap()
+	for i := 0; i < int(l); i++ {
+		k, err := readBinaryTyped(buf)
+		if err != nil {
+			return err
+		}
+		v, err := readBinaryTyped(buf)
+		if err != nil {
+			return err
+		}
+		if err := rv.Set(k, v); err != nil {
+			return err
+		}
+	}
+	*x = *rv
+	return nil
+}
+
+func (x *VMOrderedMap) String() string {
+	b, err := json.Marshal(x)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+// MarshalJSON выводит объект с ключами в порядке добавления, ключи преобразуются в строки
+func (x *VMOrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range x.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(orderedMapKeyString(k))
+		if err != nil {
+			return nil, err
+		}
+		vb, err := json.Marshal(x.vals[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON сохраняет порядок ключей объекта, ключи становятся строками,
+// вложенные объекты также становятся соответствиями
+func (x *VMOrderedMap) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil {
+		return err
+	} else if t != json.Delim('{') {
+		return VMErrorNeedMap
+	}
+	rv := NewVMOrderedMap()
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		k, _ := t.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		var v VMValue
+		if tr := bytes.TrimSpace(raw); len(tr) > 0 && tr[0] == '{' {
+			// вложенные объекты тоже сохраняют порядок ключей
+			vm := NewVMOrderedMap()
+			if err := vm.UnmarshalJSON(tr); err != nil {
+				return err
+			}
+			v = vm
+		} else if v, err = VMValuerFromJSON(string(raw)); err != nil {
+			return err
+		}
+		rv.Set(VMString(k), v)
+	}
+	*x = *rv
+	return nil
+}
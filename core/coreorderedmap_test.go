@@ -0,0 +1,82 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestOrderedMapKeys(t *testing.T) {
+	m := NewVMOrderedMap()
+	m.Set(VMInt(1), VMString("целое"))
+	m.Set(NewVMDecNumFromInt64(1), VMString("дробное"))
+	m.Set(VMString("1"), VMString("строка"))
+	m.Set(VMSlice{VMInt(1), VMInt(2)}, VMString("массив"))
+	if m.Length() != 3 {
+		t.Fatalf("Length() = %v, want 3", m.Length())
+	}
+	if v := m.IndexVal(VMInt(1)); v != VMString("дробное") {
+		t.Errorf("1 и 1.0 должны быть одним ключом, получено %v", v)
+	}
+	if v := m.IndexVal(VMSlice{VMInt(1), VMInt(2)}); v != VMString("массив") {
+		t.Errorf("массив как ключ: получено %v", v)
+	}
+	if _, _, err := m.Get(VMFunc(nil)); err == nil {
+		t.Errorf("ключ без хэша должен давать ошибку")
+	}
+}
+
+func TestOrderedMapBinary(t *testing.T) {
+	m := NewVMOrderedMap()
+	tm := VMTime(time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC))
+	m.Set(VMString("я"), VMInt(1))
+	m.Set(VMInt(2), VMString("два"))
+	m.Set(tm, VMBool(true))
+	nested := NewVMOrderedMap()
+	nested.Set(VMString("б"), VMInt(3))
+	nested.Set(VMString("а"), VMInt(4))
+	m.Set(VMString("вложенное"), nested)
+
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := VMORDEREDMAP.ParseBinary(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := v.(*VMOrderedMap)
+	if !ok {
+		t.Fatalf("ParseBinary() вернул %T", v)
+	}
+	if !EqualVMValues(m, got) {
+		t.Errorf("после восстановления %v, want %v", got, m)
+	}
+	if got.String() != m.String() {
+		t.Errorf("порядок ключей: %v, want %v", got, m)
+	}
+	if _, ok := got.keys[1].(VMInt); !ok {
+		t.Errorf("тип ключа потерян: %T", got.keys[1])
+	}
+	if _, ok := got.IndexVal(VMString("вложенное")).(*VMOrderedMap); !ok {
+		t.Errorf("тип вложенного соответствия потерян")
+	}
+}
+
+func TestOrderedMapJSON(t *testing.T) {
+	src := `{"я":1,"б":{"в":2,"а":[1,"x"]},"а":null}`
+	m := NewVMOrderedMap()
+	if err := json.Unmarshal([]byte(src), m); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != src {
+		t.Errorf("MarshalJSON() = %s, want %s", b, src)
+	}
+	if err := m.UnmarshalJSON([]byte(`[1]`)); err == nil {
+		t.Errorf("массив не должен разбираться как соответствие")
+	}
+}
@@ -0,0 +1,794 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymerick/raymond"
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/net/html"
+)
+
+// шрифт по умолчанию - Go, содержит кириллицу и встроен в интерпретатор
+const pdfDefaultFont = "Go"
+
+// VMPDFDocument ДокументPDF - формирование печатных форм в PDF.
+// Параметры конструктора (необязательная структура):
+//   РазмерСтраницы - "A4" (по умолчанию), "A3", "A5", "Letter", "Legal"
+//   Ориентация - "Книжная" (по умолчанию) или "Альбомная"
+//   Поля - отступы от краев страницы в миллиметрах, по умолчанию 10
+type VMPDFDocument struct {
+	VMMetaObj
+
+	pdf    *fpdf.Fpdf
+	family string
+	style  string
+	size   float64
+	header string
+	footer string
+	out    []byte // готовый документ, формируется при первом выводе
+}
+
+func (x *VMPDFDocument) VMTypeString() string {
+	return "ДокументPDF"
+}
+
+func (x *VMPDFDocument) VMRegister() {
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) > 1 {
+			return VMErrorMaxArgs(1)
+		}
+		params := VMStringMap{}
+		if len(args) == 1 {
+			var ok bool
+			if params, ok = args[0].(VMStringMap); !ok {
+				return VMErrorNeedMap
+			}
+		}
+		return x.Init(params)
+	})
+
+	x.VMRegisterMethod("НоваяСтраница", VMFuncZeroParams(x.НоваяСтраница))
+	x.VMRegisterMethod("ДобавитьШрифт", VMFuncTwoParamsOptionals(1, x.ДобавитьШрифт))
+	x.VMRegisterMethod("Шрифт", VMFuncOneParamOptionals(2, x.Шрифт))
+	x.VMRegisterMethod("ЦветТекста", VMFuncThreeParams(x.ЦветТекста))
+	x.VMRegisterMethod("Текст", VMFuncOneParamOptionals(1, x.Текст))
+	x.VMRegisterMethod("ТекстВПозиции", VMFuncThreeParams(x.ТекстВПозиции))
+	x.VMRegisterMethod("ПереводСтроки", VMFuncNParamsOptionals(0, 1, x.ПереводСтроки))
+	x.VMRegisterMethod("Линия", VMFuncNParams(4, x.Линия))
+	x.VMRegisterMethod("Таблица", VMFuncOneParamOptionals(1, x.Таблица))
+	x.VMRegisterMethod("Изображение", VMFuncOneParamOptionals(4, x.Изображение))
+	x.VMRegisterMethod("ВерхнийКолонтитул", VMFuncOneParam(x.ВерхнийКолонтитул))
+	x.VMRegisterMethod("НижнийКолонтитул", VMFuncOneParam(x.НижнийКолонтитул))
+	x.VMRegisterMethod("ЗаполнитьПоШаблону", VMFuncTwoParams(x.ЗаполнитьПоШаблону))
+	x.VMRegisterMethod("Записать", VMFuncOneParam(x.Записать))
+	x.VMRegisterMethod("ПолучитьДвоичныеДанные", VMFuncZeroParams(x.ПолучитьДвоичныеДанные))
+}
+
+func (x *VMPDFDocument) Init(params VMStringMap) error {
+	size, orient, margin := "A4", "P", 10.0
+	for k, v := range params {
+		switch strings.ToLower(k) {
+		case "размерстраницы":
+			size = v.(VMStringer).String()
+		case "ориентация":
+			switch strings.ToLower(v.(VMStringer).String()) {
+			case "книжная", "p", "portrait":
+				orient = "P"
+			case "альбомная", "l", "landscape":
+				orient = "L"
+			default:
+				return VMErrorPDFOrientation
+			}
+		case "поля":
+			n, ok := v.(VMNumberer)
+			if !ok {
+				return VMErrorNeedDecNum
+			}
+			margin = n.Float()
+		}
+	}
+
+	x.pdf = fpdf.New(orient, "mm", size, "")
+	x.pdf.SetMargins(margin, margin, margin)
+	x.pdf.SetAutoPageBreak(true, margin+5)
+	x.pdf.AliasNbPages("{nb}")
+	x.pdf.AddUTF8FontFromBytes(pdfDefaultFont, "", goregular.TTF)
+	x.pdf.AddUTF8FontFromBytes(pdfDefaultFont, "B", gobold.TTF)
+	x.pdf.AddUTF8FontFromBytes(pdfDefaultFont, "I", goitalic.TTF)
+	x.pdf.AddUTF8FontFromBytes(pdfDefaultFont, "BI", gobolditalic.TTF)
+	x.family, x.style, x.size = pdfDefaultFont, "", 11
+	x.pdf.SetFont(x.family, x.style, x.size)
+
+	x.pdf.SetHeaderFuncMode(func() {
+		if x.header != "" {
+			x.colontitle(x.header, x.pdf.GetY())
+			x.pdf.Ln(x.lineHeight())
+		}
+	}, false)
+	x.pdf.SetFooterFunc(func() {
+		if x.footer != "" {
+			_, bottom, _, _ := x.pdf.GetMargins()
+			x.colontitle(x.footer, -bottom)
+		}
+	})
+	return x.pdf.Error()
+}
+
+// colontitle выводит колонтитул мелким шрифтом по центру,
+// [НомерСтраницы] и [ВсегоСтраниц] заменяются на номера
+func (x *VMPDFDocument) colontitle(s string, y float64) {
+	s = strings.NewReplacer(
+		"[НомерСтраницы]", strconv.Itoa(x.pdf.PageNo()),
+		"[ВсегоСтраниц]", "{nb}",
+	).Replace(s)
+	x.pdf.SetY(y)
+	x.pdf.SetFont(x.family, "", x.size*0.8)
+	x.pdf.CellFormat(0, x.lineHeight(), s, "", 0, "C", false, 0, "")
+	x.pdf.SetFont(x.family, x.style, x.size)
+}
+
+// lineHeight высота строки в мм для текущего размера шрифта
+func (x *VMPDFDocument) lineHeight() float64 {
+	_, h := x.pdf.GetFontSize()
+	return h * 1.3
+}
+
+func (x *VMPDFDocument) ensurePage() {
+	if x.pdf.PageNo() == 0 {
+		x.pdf.AddPage()
+	}
+}
+
+func (x *VMPDFDocument) setStyle(style string) {
+	x.style = style
+	x.pdf.SetFont(x.family, x.style, x.size)
+}
+
+// pdfStyle переводит стиль шрифта: Ж - жирный, К - курсив, П - подчеркнутый
+func pdfStyle(s string) string {
+	var rv string
+	for _, r := range strings.ToUpper(s) {
+		switch r {
+		case 'Ж', 'B':
+			rv += "B"
+		case 'К', 'I':
+			rv += "I"
+		case 'П', 'U':
+			rv += "U"
+		}
+	}
+	return rv
+}
+
+func pdfAlign(s string) string {
+	switch strings.ToLower(s) {
+	case "центр", "c", "center":
+		return "C"
+	case "право", "r", "right":
+		return "R"
+	case "поширине", "j", "justify":
+		return "J"
+	}
+	return "L"
+}
+
+func pdfCellText(v VMValue) string {
+	switch vv := v.(type) {
+	case nil, VMNilType:
+		return ""
+	case VMTime:
+		t := time.Time(vv)
+		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+			return t.Format("02.01.2006")
+		}
+		return t.Format("02.01.2006 15:04:05")
+	case VMStringer:
+		return vv.String()
+	}
+	return fmt.Sprint(v)
+}
+
+func (x *VMPDFDocument) НоваяСтраница(rets *VMSlice) error {
+	x.pdf.AddPage()
+	return x.pdf.Error()
+}
+
+// ДобавитьШрифт(Имя, ПутьКФайлуTTF, Стиль) подключает TrueType шрифт,
+// после чего его можно выбрать методом Шрифт
+func (x *VMPDFDocument) ДобавитьШрифт(name, path VMString, rest VMSlice, rets *VMSlice) error {
+	style := ""
+	if len(rest) > 0 {
+		style = pdfStyle(rest[0].(VMStringer).String())
+	}
+	x.pdf.AddUTF8Font(string(name), style, string(path))
+	return x.pdf.Error()
+}
+
+// Шрифт(Размер, Стиль, Имя) - стиль и имя необязательны
+func (x *VMPDFDocument) Шрифт(size VMNumberer, rest VMSlice, rets *VMSlice) error {
+	x.size = size.Float()
+	x.style = ""
+	if len(rest) > 0 {
+		x.style = pdfStyle(rest[0].(VMStringer).String())
+	}
+	if len(rest) > 1 {
+		x.family = rest[1].(VMStringer).String()
+	}
+	x.pdf.SetFont(x.family, x.style, x.size)
+	return x.pdf.Error()
+}
+
+func (x *VMPDFDocument) ЦветТекста(r, g, b VMInt, rets *VMSlice) error {
+	x.pdf.SetTextColor(int(r), int(g), int(b))
+	return nil
+}
+
+// Текст(Строка, Выравнивание) выводит абзац на всю ширину страницы с переносом строк
+func (x *VMPDFDocument) Текст(s VMValue, rest VMSlice, rets *VMSlice) error {
+	align := "L"
+	if len(rest) > 0 {
+		align = pdfAlign(rest[0].(VMStringer).String())
+	}
+	x.ensurePage()
+	x.pdf.MultiCell(0, x.lineHeight(), pdfCellText(s), "", align, false)
+	return x.pdf.Error()
+}
+
+func (x *VMPDFDocument) ТекстВПозиции(px, py VMNumberer, s VMValue, rets *VMSlice) error {
+	x.ensurePage()
+	x.pdf.Text(px.Float(), py.Float(), pdfCellText(s))
+	return x.pdf.Error()
+}
+
+func (x *VMPDFDocument) ПереводСтроки(args VMSlice, rets *VMSlice) error {
+	h := x.lineHeight()
+	if len(args) > 0 {
+		n, ok := args[0].(VMNumberer)
+		if !ok {
+			return VMErrorNeedDecNum
+		}
+		h = n.Float()
+	}
+	x.ensurePage()
+	x.pdf.Ln(h)
+	return nil
+}
+
+func (x *VMPDFDocument) Линия(args VMSlice, rets *VMSlice) error {
+	var c [4]float64
+	for i := range c {
+		n, ok := args[i].(VMNumberer)
+		if !ok {
+			return VMErrorNeedDecNum
+		}
+		c[i] = n.Float()
+	}
+	x.ensurePage()
+	x.pdf.Line(c[0], c[1], c[2], c[3])
+	return x.pdf.Error()
+}
+
+func (x *VMPDFDocument) ВерхнийКолонтитул(s VMString, rets *VMSlice) error {
+	x.header = string(s)
+	return nil
+}
+
+func (x *VMPDFDocument) НижнийКолонтитул(s VMString, rets *VMSlice) error {
+	x.footer = string(s)
+	return nil
+}
+
+// Изображение(ПутьИлиШтрихкод, Ширина, Высота, X, Y) - размеры в мм, 0 - по пропорциям картинки;
+// без координат картинка выводится в текущей позиции
+func (x *VMPDFDocument) Изображение(img VMValue, rest VMSlice, rets *VMSlice) error {
+	var dims [4]float64
+	for i, v := range rest {
+		n, ok := v.(VMNumberer)
+		if !ok {
+			return VMErrorNeedDecNum
+		}
+		dims[i] = n.Float()
+	}
+	var path string
+	switch v := img.(type) {
+	case imageFiler:
+		path = v.imagePath()
+	case VMString:
+		path = string(v)
+	default:
+		return VMErrorNeedString
+	}
+	x.ensurePage()
+	if len(rest) > 2 {
+		x.pdf.ImageOptions(path, dims[2], dims[3], dims[0], dims[1], false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+	} else {
+		x.image(path, dims[0], dims[1])
+	}
+	return x.pdf.Error()
+}
+
+func (x *VMPDFDocument) image(path string, w, h float64) {
+	x.pdf.ImageOptions(path, -1, x.pdf.GetY(), w, h, true, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+}
+
+// Таблица(Данные, Параметры) выводит Массив структур, Массив массивов или ТаблицуЗначений.
+// Параметры: Заголовки - массив имен колонок, Ширины - массив ширин в мм, Граница - Булево
+func (x *VMPDFDocument) Таблица(data VMValue, rest VMSlice, rets *VMSlice) error {
+	var (
+		headers []string
+		widths  []float64
+		border  = true
+		rows    [][]string
+		numeric []bool
+	)
+	if len(rest) > 0 {
+		params, ok := rest[0].(VMStringMap)
+		if !ok {
+			return VMErrorNeedMap
+		}
+		for k, v := range params {
+			switch strings.ToLower(k) {
+			case "заголовки":
+				sl, ok := v.(VMSlice)
+				if !ok {
+					return VMErrorNeedSlice
+				}
+				for _, h := range sl {
+					headers = append(headers, pdfCellText(h))
+				}
+			case "ширины":
+				sl, ok := v.(VMSlice)
+				if !ok {
+					return VMErrorNeedSlice
+				}
+				for _, w := range sl {
+					n, ok := w.(VMNumberer)
+					if !ok {
+						return VMErrorNeedDecNum
+					}
+					widths = append(widths, n.Float())
+				}
+			case "граница":
+				b, ok := v.(VMBool)
+				if !ok {
+					return VMErrorNeedBool
+				}
+				border = bool(b)
+			}
+		}
+	}
+
+	if vt, ok := data.(*VMTable); ok && headers == nil {
+		for _, c := range vt.cols.cols {
+			headers = append(headers, c.Name())
+		}
+	}
+	sl, ok := data.(VMSlicer)
+	if !ok {
+		return VMErrorNeedSlice
+	}
+	addRow := func(vals VMSlice) {
+		row := make([]string, len(vals))
+		for i, v := range vals {
+			row[i] = pdfCellText(v)
+			for len(numeric) <= i {
+				numeric = append(numeric, true)
+			}
+			switch v.(type) {
+			case VMInt, VMDecNum, VMNilType:
+			default:
+				numeric[i] = false
+			}
+		}
+		rows = append(rows, row)
+	}
+	for _, r := range sl.Slice() {
+		switch rv := r.(type) {
+		case VMSlice:
+			addRow(rv)
+		case VMStringMaper:
+			m := rv.StringMap()
+			if headers == nil {
+				for k := range m {
+					headers = append(headers, k)
+				}
+				sort.Strings(headers)
+			}
+			vals := make(VMSlice, len(headers))
+			for i, h := range headers {
+				vals[i] = m[h]
+			}
+			addRow(vals)
+		default:
+			addRow(VMSlice{r})
+		}
+	}
+	aligns := make([]string, len(numeric))
+	for i, n := range numeric {
+		if n {
+			aligns[i] = "R"
+		} else {
+			aligns[i] = "L"
+		}
+	}
+	x.ensurePage()
+	x.table(headers, rows, widths, aligns, border)
+	return x.pdf.Error()
+}
+
+// table выводит таблицу с переносом текста в ячейках, при переходе на новую страницу шапка повторяется
+func (x *VMPDFDocument) table(headers []string, rows [][]string, widths []float64, aligns []string, border bool) {
+	ncols := len(headers)
+	for _, r := range rows {
+		if len(r) > ncols {
+			ncols = len(r)
+		}
+	}
+	if ncols == 0 {
+		return
+	}
+	if len(widths) < ncols {
+		left, _, right, _ := x.pdf.GetMargins()
+		pw, _ := x.pdf.GetPageSize()
+		free := pw - left - right
+		for _, w := range widths {
+			free -= w
+		}
+		rest := ncols - len(widths)
+		for len(widths) < ncols {
+			widths = append(widths, free/float64(rest))
+		}
+	}
+	lh := x.lineHeight()
+	_, ph := x.pdf.GetPageSize()
+	_, _, _, bottom := x.pdf.GetMargins()
+
+	var drawRow func(cells []string, header bool)
+	drawRow = func(cells []string, header bool) {
+		lines := make([][]string, ncols)
+		n := 1
+		for i := 0; i < ncols; i++ {
+			s := ""
+			if i < len(cells) {
+				s = cells[i]
+			}
+			lines[i] = x.pdf.SplitText(s, widths[i]-2)
+			if len(lines[i]) > n {
+				n = len(lines[i])
+			}
+		}
+		h := float64(n) * lh
+		if x.pdf.GetY()+h > ph-bottom {
+			x.pdf.AddPage()
+			if !header && len(headers) > 0 {
+				x.headerRow(drawRow, headers)
+			}
+		}
+		left, _, _, _ := x.pdf.GetMargins()
+		cx, cy := left, x.pdf.GetY()
+		for i := 0; i < ncols; i++ {
+			if header {
+				x.pdf.SetFillColor(230, 230, 230)
+				x.pdf.Rect(cx, cy, widths[i], h, "F")
+			}
+			if border {
+				x.pdf.Rect(cx, cy, widths[i], h, "D")
+			}
+			align := "L"
+			if header {
+				align = "C"
+			} else if i < len(aligns) {
+				align = aligns[i]
+			}
+			for j, l := range lines[i] {
+				x.pdf.SetXY(cx, cy+float64(j)*lh)
+				x.pdf.CellFormat(widths[i], lh, l, "", 0, align, false, 0, "")
+			}
+			cx += widths[i]
+		}
+		x.pdf.SetXY(left, cy+h)
+	}
+
+	if len(headers) > 0 {
+		x.headerRow(drawRow, headers)
+	}
+	for _, r := range rows {
+		drawRow(r, false)
+	}
+}
+
+func (x *VMPDFDocument) headerRow(drawRow func([]string, bool), headers []string) {
+	style := x.style
+	x.setStyle("B")
+	drawRow(headers, true)
+	x.setStyle(style)
+}
+
+// ЗаполнитьПоШаблону(Шаблон, Данные) выводит HTML-подобный шаблон, в котором
+// поля подставляются как {{Поле}}, {{#each Массив}}...{{/each}}.
+// Поддерживаются теги h1-h3, p, div, b, strong, i, em, u, br, hr, table/tr/th/td, img и pagebreak,
+// атрибут align у абзацев и ячеек, width/height в мм у картинок
+func (x *VMPDFDocument) ЗаполнитьПоШаблону(tpl VMString, data VMStringMap, rets *VMSlice) error {
+	jsonStr, err := templateData(data).(VMStringMap).MarshalJSON()
+	if err != nil {
+		return err
+	}
+	var ctx map[string]interface{}
+	if err := json.Unmarshal(jsonStr, &ctx); err != nil {
+		return err
+	}
+	src, err := raymond.Render(string(tpl), ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %s", VMErrorPDFTemplate, err)
+	}
+	x.ensurePage()
+	if err := x.renderHTML(src); err != nil {
+		return fmt.Errorf("%w: %s", VMErrorPDFTemplate, err)
+	}
+	return x.pdf.Error()
+}
+
+type pdfSegment struct {
+	text  string
+	style string
+}
+
+type pdfHTMLRenderer struct {
+	doc      *VMPDFDocument
+	bold     int
+	italic   int
+	under    int
+	align    []string
+	segments []pdfSegment
+
+	inTable bool
+	headers []string
+	rows    [][]string
+	row     []string
+	rowHead bool
+	cell    *strings.Builder
+}
+
+func (r *pdfHTMLRenderer) style() string {
+	s := ""
+	if r.bold > 0 {
+		s += "B"
+	}
+	if r.italic > 0 {
+		s += "I"
+	}
+	if r.under > 0 {
+		s += "U"
+	}
+	return s
+}
+
+func (r *pdfHTMLRenderer) text(s string) {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return
+	}
+	if r.cell != nil {
+		if r.cell.Len() > 0 {
+			r.cell.WriteByte(' ')
+		}
+		r.cell.WriteString(s)
+		return
+	}
+	if r.inTable {
+		return
+	}
+	r.segments = append(r.segments, pdfSegment{text: s, style: r.style()})
+}
+
+// flush выводит накопленный текст абзаца
+func (r *pdfHTMLRenderer) flush() {
+	if len(r.segments) == 0 {
+		return
+	}
+	d := r.doc
+	base := d.style
+	align := "L"
+	if len(r.align) > 0 {
+		align = r.align[len(r.align)-1]
+	}
+	lh := d.lineHeight()
+	if align == "L" {
+		for i, sg := range r.segments {
+			d.setStyle(sg.style)
+			if i > 0 {
+				d.pdf.Write(lh, " ")
+			}
+			d.pdf.Write(lh, sg.text)
+		}
+		d.pdf.Ln(lh)
+	} else {
+		var sb strings.Builder
+		for i, sg := range r.segments {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(sg.text)
+		}
+		d.setStyle(r.segments[0].style)
+		d.pdf.MultiCell(0, lh, sb.String(), "", align, false)
+	}
+	d.setStyle(base)
+	r.segments = r.segments[:0]
+}
+
+func pdfAttr(t html.Token, name string) string {
+	for _, a := range t.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func pdfAttrFloat(t html.Token, name string) float64 {
+	f, _ := strconv.ParseFloat(pdfAttr(t, name), 64)
+	return f
+}
+
+var pdfHeadingSizes = map[string]float64{"h1": 20, "h2": 16, "h3": 13}
+
+func (x *VMPDFDocument) renderHTML(src string) error {
+	r := &pdfHTMLRenderer{doc: x}
+	baseSize := x.size
+	z := html.NewTokenizer(strings.NewReader(src))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			r.flush()
+			if z.Err() == io.EOF {
+				return nil
+			}
+			return z.Err()
+		case html.TextToken:
+			r.text(string(z.Text()))
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			switch t.Data {
+			case "p", "div", "h1", "h2", "h3":
+				r.flush()
+				r.align = append(r.align, pdfAlign(pdfAttr(t, "align")))
+				if sz, ok := pdfHeadingSizes[t.Data]; ok {
+					x.size = sz
+					x.pdf.SetFontSize(sz)
+					r.bold++
+				}
+			case "b", "strong":
+				r.bold++
+			case "i", "em":
+				r.italic++
+			case "u":
+				r.under++
+			case "br":
+				if len(r.segments) == 0 {
+					x.pdf.Ln(x.lineHeight())
+				}
+				r.flush()
+			case "hr":
+				r.flush()
+				left, _, right, _ := x.pdf.GetMargins()
+				pw, _ := x.pdf.GetPageSize()
+				y := x.pdf.GetY() + 1
+				x.pdf.Line(left, y, pw-right, y)
+				x.pdf.Ln(3)
+			case "img":
+				r.flush()
+				if src := pdfAttr(t, "src"); src != "" {
+					x.image(src, pdfAttrFloat(t, "width"), pdfAttrFloat(t, "height"))
+				}
+			case "pagebreak":
+				r.flush()
+				x.pdf.AddPage()
+			case "table":
+				r.flush()
+				r.inTable, r.headers, r.rows = true, nil, nil
+			case "tr":
+				r.row, r.rowHead = nil, true
+			case "th", "td":
+				if t.Data == "td" {
+					r.rowHead = false
+				}
+				r.cell = &strings.Builder{}
+			}
+		case html.EndTagToken:
+			t := z.Token()
+			switch t.Data {
+			case "p", "div", "h1", "h2", "h3":
+				r.flush()
+				if len(r.align) > 0 {
+					r.align = r.align[:len(r.align)-1]
+				}
+				if _, ok := pdfHeadingSizes[t.Data]; ok {
+					x.size = baseSize
+					x.pdf.SetFontSize(baseSize)
+					r.bold--
+				}
+				if t.Data != "div" {
+					x.pdf.Ln(x.lineHeight() / 3)
+				}
+			case "b", "strong":
+				r.bold--
+			case "i", "em":
+				r.italic--
+			case "u":
+				r.under--
+			case "th", "td":
+				if r.cell != nil {
+					r.row = append(r.row, r.cell.String())
+					r.cell = nil
+				}
+			case "tr":
+				if r.rowHead && r.headers == nil && len(r.rows) == 0 {
+					r.headers = r.row
+				} else {
+					r.rows = append(r.rows, r.row)
+				}
+				r.row = nil
+			case "table":
+				r.inTable = false
+				x.table(r.headers, r.rows, nil, nil, true)
+				x.pdf.Ln(x.lineHeight() / 3)
+			}
+		}
+	}
+}
+
+// render завершает документ и запоминает результат.
+// fpdf закрывает документ при выводе и отдает содержимое только один раз,
+// поэтому повторные Записать и ПолучитьДвоичныеДанные возвращают уже сформированный документ
+func (x *VMPDFDocument) render() ([]byte, error) {
+	if x.out != nil {
+		return x.out, nil
+	}
+	x.ensurePage()
+	var buf bytes.Buffer
+	if err := x.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	x.out = buf.Bytes()
+	return x.out, nil
+}
+
+func (x *VMPDFDocument) Output(w io.Writer) error {
+	b, err := x.render()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func (x *VMPDFDocument) Записать(name VMString, rets *VMSlice) error {
+	b, err := x.render()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(string(name), b, 0644)
+}
+
+func (x *VMPDFDocument) ПолучитьДвоичныеДанные(rets *VMSlice) error {
+	b, err := x.render()
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBinaryData(b))
+	return nil
+}
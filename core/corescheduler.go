@@ -0,0 +1,482 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// cronSchedule расписание в формате cron из пяти полей: минута час день месяц деньнедели.
+// Поддерживаются *, списки через запятую, диапазоны через "-", шаг через "/",
+// имена месяцев и дней недели (jan, mon), а также сокращения @hourly, @daily, @weekly, @monthly, @yearly.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64 // битовые маски допустимых значений
+	domAny, dowAny                bool   // поле задано как *
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+func parseCron(spec string) (*cronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if m, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = m
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %s", VMErrorCronFormat, spec)
+	}
+	s := &cronSchedule{
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}
+	var err error
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	masks := [5]*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, f := range fields {
+		if *masks[i], err = parseCronField(f, bounds[i][0], bounds[i][1]); err != nil {
+			return nil, fmt.Errorf("%w: %s", VMErrorCronFormat, f)
+		}
+	}
+	// воскресенье можно указывать как 0 или 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+func parseCronValue(s string) (int, error) {
+	if n, ok := cronNames[strings.ToLower(s)]; ok {
+		return n, nil
+	}
+	return strconv.Atoi(s)
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, VMErrorCronFormat
+			}
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = parseCronValue(a); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseCronValue(b); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, VMErrorCronFormat
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	// как в классическом cron: если ограничены оба поля, достаточно совпадения любого
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next возвращает ближайшее время запуска строго после t
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	// расписание вида "30 февраля" никогда не наступит
+	return time.Time{}
+}
+
+// schedulerJob задание планировщика, все поля состояния защищены мьютексом планировщика
+type schedulerJob struct {
+	name     string
+	spec     string
+	cron     *cronSchedule
+	interval time.Duration
+	f        VMFunc
+
+	running  bool
+	runs     int
+	skipped  int
+	lastRun  time.Time
+	lastDur  time.Duration
+	nextRun  time.Time
+	lastErr  error
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (j *schedulerJob) next(now time.Time) time.Time {
+	if j.cron != nil {
+		return j.cron.Next(now)
+	}
+	return now.Add(j.interval)
+}
+
+var schedulerCounter int64
+
+// VMScheduler планировщик заданий: запускает функции по расписанию cron или с интервалом.
+// Каждый запуск выполняется в собственном окружении вызова функции, а пока предыдущий запуск
+// задания не завершился, следующий пропускается. Запущенный планировщик регистрируется
+// в главном менеджере сервисов и останавливается вместе с ним.
+type VMScheduler struct {
+	VMMetaObj
+
+	mu      sync.Mutex
+	id      string
+	loc     *time.Location
+	jobs    map[string]*schedulerJob
+	order   []string
+	started bool
+	done    chan struct{}
+	wg      sync.WaitGroup // циклы заданий и выполняющиеся запуски
+}
+
+func (x *VMScheduler) VMTypeString() string {
+	return "Планировщик"
+}
+
+func (x *VMScheduler) VMRegister() {
+	x.id = "планировщик-" + strconv.FormatInt(atomic.AddInt64(&schedulerCounter, 1), 10)
+	x.loc = time.Local
+	x.jobs = make(map[string]*schedulerJob)
+
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		loc, err := timeZoneArg(args, 0)
+		if err != nil {
+			return err
+		}
+		x.loc = loc
+		return nil
+	})
+
+	x.VMRegisterMethod("Добавить", VMFuncThreeParams(x.Добавить))
+	x.VMRegisterMethod("Удалить", VMFuncOneParam(x.Удалить))
+	x.VMRegisterMethod("Запустить", VMFuncZeroParams(x.Запустить))
+	x.VMRegisterMethod("Остановить", VMFuncZeroParams(x.Остановить))
+	x.VMRegisterMethod("Ожидать", VMFuncZeroParams(x.Ожидать))
+	x.VMRegisterMethod("Запущен", VMFuncZeroParams(x.Запущен))
+	x.VMRegisterMethod("Состояние", VMFuncOneParam(x.Состояние))
+	x.VMRegisterMethod("Задания", VMFuncZeroParams(x.Задания))
+}
+
+func (x *VMScheduler) Header() VMServiceHeader {
+	return VMServiceHeader{ID: x.id, Name: "Планировщик заданий"}
+}
+
+// Start запускает циклы всех заданий, повторный вызов ничего не делает
+func (x *VMScheduler) Start() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.started {
+		return nil
+	}
+	x.started = true
+	x.done = make(chan struct{})
+	for _, name := range x.order {
+		x.startJob(x.jobs[name])
+	}
+	return nil
+}
+
+func (x *VMScheduler) HealthCheck() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if !x.started {
+		return VMErrorServiceNotReady
+	}
+	return nil
+}
+
+// Stop останавливает циклы заданий и дожидается завершения выполняющихся запусков
+func (x *VMScheduler) Stop() error {
+	x.mu.Lock()
+	if !x.started {
+		x.mu.Unlock()
+		return nil
+	}
+	x.started = false
+	close(x.done)
+	for _, j := range x.jobs {
+		j.stopOnce.Do(func() { close(j.stop) })
+	}
+	x.mu.Unlock()
+	x.wg.Wait()
+	return nil
+}
+
+func (x *VMScheduler) BindEnv(env *Env) {
+	env.AddResource(x)
+}
+
+// ReleaseLeaked останавливает планировщик, если скрипт завершился, не остановив его
+func (x *VMScheduler) ReleaseLeaked() error {
+	if x.HealthCheck() != nil {
+		return nil
+	}
+	x.stopService()
+	return errors.New("Планировщик не был остановлен скриптом и остановлен при завершении работы")
+}
+
+func (x *VMScheduler) stopService() {
+	if _, ok := VMMainServiceBus.GetService(x.id); ok {
+		VMMainServiceBus.Deregister(x)
+	}
+	x.Stop()
+}
+
+// startJob запускает цикл задания, вызывается под блокировкой
+func (x *VMScheduler) startJob(j *schedulerJob) {
+	j.stop = make(chan struct{})
+	j.stopOnce = sync.Once{}
+	x.wg.Add(1)
+	go x.loop(j, j.stop)
+}
+
+func (x *VMScheduler) loop(j *schedulerJob, stop chan struct{}) {
+	defer x.wg.Done()
+	for {
+		next := j.next(time.Now().In(x.loc))
+		x.mu.Lock()
+		j.nextRun = next
+		x.mu.Unlock()
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		x.mu.Lock()
+		if j.running {
+			j.skipped++
+			x.mu.Unlock()
+			continue
+		}
+		j.running = true
+		j.lastRun = time.Now().In(x.loc)
+		x.wg.Add(1)
+		x.mu.Unlock()
+		go x.run(j)
+	}
+}
+
+func (x *VMScheduler) run(j *schedulerJob) {
+	defer x.wg.Done()
+	start := time.Now()
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+		var rets VMSlice
+		err = j.f(VMSlice{}, &rets)
+	}()
+	x.mu.Lock()
+	j.running = false
+	j.runs++
+	j.lastDur = time.Since(start)
+	j.lastErr = err
+	x.mu.Unlock()
+}
+
+// Добавить(Имя, Расписание, Функция) добавляет или заменяет задание.
+// Расписание - строка cron ("*/5 * * * *", "@daily") или Длительность для запуска с интервалом.
+// Функция вызывается без параметров.
+func (x *VMScheduler) Добавить(name VMString, sched VMValue, f VMFunc, rets *VMSlice) error {
+	j := &schedulerJob{name: string(name), f: f}
+	switch v := sched.(type) {
+	case VMString:
+		c, err := parseCron(string(v))
+		if err != nil {
+			return err
+		}
+		j.cron, j.spec = c, string(v)
+	case VMDurationer:
+		j.interval = time.Duration(v.Duration())
+		if j.interval <= 0 {
+			return VMErrorCronFormat
+		}
+		j.spec = VMTimeDuration(j.interval).String()
+	default:
+		return VMErrorCronFormat
+	}
+
+	x.mu.Lock()
+	old, exists := x.jobs[j.name]
+	if exists && old.stop != nil {
+		old.stopOnce.Do(func() { close(old.stop) })
+	}
+	if !exists {
+		x.order = append(x.order, j.name)
+	}
+	x.jobs[j.name] = j
+	if x.started {
+		x.startJob(j)
+	}
+	x.mu.Unlock()
+	return nil
+}
+
+// Удалить(Имя) удаляет задание, выполняющийся запуск доработает до конца
+func (x *VMScheduler) Удалить(name VMString, rets *VMSlice) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	j, ok := x.jobs[string(name)]
+	if !ok {
+		return VMErrorSchedulerJobNotFound
+	}
+	if j.stop != nil {
+		j.stopOnce.Do(func() { close(j.stop) })
+	}
+	delete(x.jobs, string(name))
+	for i, n := range x.order {
+		if n == string(name) {
+			x.order = append(x.order[:i], x.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Запустить() запускает задания и регистрирует планировщик в менеджере сервисов
+func (x *VMScheduler) Запустить(rets *VMSlice) error {
+	if err := x.Start(); err != nil {
+		return err
+	}
+	if _, ok := VMMainServiceBus.GetService(x.id); !ok {
+		return VMMainServiceBus.Register(x)
+	}
+	return nil
+}
+
+// Остановить() останавливает планировщик, дожидаясь завершения выполняющихся заданий
+func (x *VMScheduler) Остановить(rets *VMSlice) error {
+	x.stopService()
+	return nil
+}
+
+// Ожидать() блокирует выполнение до остановки планировщика, в т.ч. менеджером сервисов
+func (x *VMScheduler) Ожидать(rets *VMSlice) error {
+	x.mu.Lock()
+	done, started := x.done, x.started
+	x.mu.Unlock()
+	if !started {
+		return nil
+	}
+	<-done
+	x.wg.Wait()
+	return nil
+}
+
+func (x *VMScheduler) Запущен(rets *VMSlice) error {
+	rets.Append(VMBool(x.HealthCheck() == nil))
+	return nil
+}
+
+// state возвращает структуру состояния задания, вызывается под блокировкой
+func (j *schedulerJob) state() VMStringMap {
+	var next, last VMValue = VMNil, VMNil
+	if !j.nextRun.IsZero() {
+		next = VMTime(j.nextRun)
+	}
+	if !j.lastRun.IsZero() {
+		last = VMTime(j.lastRun)
+	}
+	var lastErr VMValue = VMNil
+	if j.lastErr != nil {
+		lastErr = VMString(j.lastErr.Error())
+	}
+	return VMStringMap{
+		"Имя":                 VMString(j.name),
+		"Расписание":          VMString(j.spec),
+		"Выполняется":         VMBool(j.running),
+		"ПоследнийЗапуск":     last,
+		"СледующийЗапуск":     next,
+		"ДлительностьЗапуска": VMTimeDuration(j.lastDur),
+		"ПоследняяОшибка":     lastErr,
+		"КоличествоЗапусков":  VMInt(j.runs),
+		"Пропущено":           VMInt(j.skipped),
+	}
+}
+
+// Состояние(Имя) возвращает структуру: Имя, Расписание, Выполняется, ПоследнийЗапуск, СледующийЗапуск,
+// ДлительностьЗапуска, ПоследняяОшибка, КоличествоЗапусков, Пропущено (из-за незавершенного предыдущего запуска)
+func (x *VMScheduler) Состояние(name VMString, rets *VMSlice) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	j, ok := x.jobs[string(name)]
+	if !ok {
+		return VMErrorSchedulerJobNotFound
+	}
+	rets.Append(j.state())
+	return nil
+}
+
+// Задания() возвращает массив состояний всех заданий в порядке добавления
+func (x *VMScheduler) Задания(rets *VMSlice) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	rv := make(VMSlice, 0, len(x.order))
+	for _, name := range x.order {
+		rv = append(rv, x.jobs[name].state())
+	}
+	rets.Append(rv)
+	return nil
+}
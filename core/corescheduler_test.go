@@ -0,0 +1,49 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// 15 мая 2024 - среда
+	from := time.Date(2024, 5, 15, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"каждую минуту", "* * * * *", time.Date(2024, 5, 15, 10, 8, 0, 0, time.UTC)},
+		{"шаг", "*/15 * * * *", time.Date(2024, 5, 15, 10, 15, 0, 0, time.UTC)},
+		{"список и диапазон", "5,50 9-11 * * *", time.Date(2024, 5, 15, 10, 50, 0, 0, time.UTC)},
+		{"следующий день", "0 3 * * *", time.Date(2024, 5, 16, 3, 0, 0, 0, time.UTC)},
+		{"день недели по имени", "0 12 * * mon", time.Date(2024, 5, 20, 12, 0, 0, 0, time.UTC)},
+		{"воскресенье как 7", "0 0 * * 7", time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{"день месяца или недели", "0 0 1 * fri", time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)},
+		{"месяц по имени", "0 0 1 jul *", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"ежечасно", "@hourly", time.Date(2024, 5, 15, 11, 0, 0, 0, time.UTC)},
+		{"ежегодно", "@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"29 февраля", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"никогда", "0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("parseCron(%q) error = %v", tt.spec, err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronFormatErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "* * * abc *"} {
+		if _, err := parseCron(spec); !errors.Is(err, VMErrorCronFormat) {
+			t.Errorf("parseCron(%q) error = %v, want VMErrorCronFormat", spec, err)
+		}
+	}
+}
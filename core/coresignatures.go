@@ -0,0 +1,67 @@
+package core
+
+// Сигнатуры встроенных функций для статической проверки типов (gonec -check).
+// Для параметров, которые принимают значения, приводимые к строке или числу,
+// тип не указывается, чтобы не выдавать ложных ошибок.
+
+func init() {
+	sigs := map[string]*VMSignature{
+		// core.go
+		"импорт":         {Params: []string{TypeStringName}, Required: 1},
+		"длина":          {Params: []string{""}, Required: 1, Result: TypeIntName},
+		"диапазон":       {Params: []string{TypeIntName, TypeIntName}, Required: 1, Result: "Массив"},
+		"текущаядата":    {Params: []string{""}, Result: "Дата"},
+		"новаядата":      {Params: []string{TypeIntName, TypeIntName, TypeIntName, TypeIntName, TypeIntName, TypeIntName, ""}, Required: 3, Result: "Дата"},
+		"разобратьдату":  {Params: []string{TypeStringName, TypeStringName}, Required: 1, Result: "Дата"},
+		"прошловременис": {Params: []string{""}, Required: 1, Result: "Длительность"},
+		"пауза":          {Params: []string{TypeNumberName}, Required: 1},
+		"хэш":            {Params: []string{""}, Required: 1, Result: TypeIntName},
+		"уникальныйидентификатор": {Result: TypeStringName},
+		"округлить":               {Params: []string{"", TypeIntName}, Required: 2, Result: TypeNumberName},
+		"длиначисла":              {Params: []string{TypeDecNumName}, Required: 1, Result: TypeIntName},
+		"точностьчисла":           {Params: []string{TypeDecNumName}, Required: 1, Result: TypeIntName},
+		"числопрописью":           {Params: []string{TypeDecNumName}, Required: 1, Result: TypeStringName},
+		"суммапрописью":           {Params: []string{TypeDecNumName}, Required: 1, Result: TypeStringName},
+		"формат":                  {Params: []string{"", ""}, Required: 2, VarArgs: true, Result: TypeStringName},
+		"окр":                     {Params: []string{"", ""}, Required: 1, Result: TypeNumberName},
+		"кодсимвола":              {Params: []string{""}, Required: 1, Result: TypeIntName},
+		"типзнч":                  {Params: []string{""}, Required: 1, Result: TypeStringName},
+		"реализуетинтерфейс":      {Params: []string{"", "Интерфейс"}, Required: 2, Result: TypeBoolName},
+		"сообщить":                {VarArgs: true},
+		"сообщитьф":               {Params: []string{TypeStringName, ""}, Required: 2, VarArgs: true},
+		"переменнаяокружения":     {Params: []string{TypeStringName}, Required: 1, Result: TypeStringName},
+		"двоичныеданныеизbase64":  {Params: []string{TypeStringName}, Required: 1, Result: "ДвоичныеДанные"},
+		"двоичныеданныеизhex":     {Params: []string{TypeStringName}, Required: 1, Result: "ДвоичныеДанные"},
+		"двоичныеданныеизстроки":  {Params: []string{TypeStringName, TypeStringName}, Required: 1, Result: "ДвоичныеДанные"},
+
+		// strings.go
+		"случайнаястрока":    {Params: []string{TypeStringName, TypeIntName}, Required: 2, Result: TypeStringName},
+		"случайноечисло":     {Params: []string{TypeIntName, TypeIntName}, Required: 2, Result: TypeIntName},
+		"нрег":               {Params: []string{""}, Required: 1, Result: TypeStringName},
+		"врег":               {Params: []string{""}, Required: 1, Result: TypeStringName},
+		"лев":                {Params: []string{"", TypeIntName}, Required: 2, Result: TypeStringName},
+		"прав":               {Params: []string{"", TypeIntName}, Required: 2, Result: TypeStringName},
+		"сред":               {Params: []string{"", TypeIntName, TypeIntName}, Required: 2, Result: TypeStringName},
+		"сокрл":              {Params: []string{""}, Required: 1, Result: TypeStringName},
+		"сокрп":              {Params: []string{""}, Required: 1, Result: TypeStringName},
+		"сокрлп":             {Params: []string{""}, Required: 1, Result: TypeStringName},
+		"стрчислострок":      {Params: []string{""}, Required: 1, Result: TypeIntName},
+		"стрполучитьстроку":  {Params: []string{"", TypeIntName}, Required: 2, Result: TypeStringName},
+		"стрдлина":           {Params: []string{""}, Required: 1, Result: TypeIntName},
+		"стрпустая":          {Params: []string{""}, Required: 1, Result: TypeBoolName},
+		"стрначинаетсяс":     {Params: []string{"", ""}, Required: 2, Result: TypeBoolName},
+		"стрзаканчиваетсяна": {Params: []string{"", ""}, Required: 2, Result: TypeBoolName},
+		"стрсодержит":        {Params: []string{"", ""}, Required: 2, Result: TypeBoolName},
+		"стрсодержитлюбой":   {Params: []string{"", ""}, Required: 2, Result: TypeBoolName},
+		"стрколичество":      {Params: []string{"", ""}, Required: 2, Result: TypeIntName},
+		"стрнайти":           {Params: []string{"", ""}, Required: 2, Result: TypeIntName},
+		"стрнайтилюбой":      {Params: []string{"", ""}, Required: 2, Result: TypeIntName},
+		"стрнайтипоследний":  {Params: []string{"", ""}, Required: 2, Result: TypeIntName},
+		"стрзаменить":        {Params: []string{"", "", ""}, Required: 3, Result: TypeStringName},
+		"стрразделить":       {Params: []string{"", "", TypeBoolName}, Required: 2, Result: "Массив"},
+		"стрсоединить":       {Params: []string{"Массив", ""}, Required: 2, Result: TypeStringName},
+	}
+	for name, sig := range sigs {
+		DefineSignature(name, sig)
+	}
+}
@@ -106,21 +106,35 @@ func (x VMSlice) MethodMember(name int) (VMFunc, bool) {
 	// только эти методы будут доступны из кода на языке Гонец!
 	switch names.UniqueNames.GetLowerCase(name) {
 	case "сортировать":
-		return VMFuncZeroParams(x.Сортировать), true
+		return VMFuncNParamsOptionals(0, 2, x.Сортировать), true
 	case "обратныйпорядок":
 		return VMFuncZeroParams(x.ОбратныйПорядок), true
 	case "копировать":
 		return VMFuncZeroParams(x.Копировать), true
 	case "найти":
 		return VMFuncNParams(1, x.Найти), true
-	//case "добавить":
-	//return VMFuncOneParam(x.Добавить), true
 	case "случайныйпорядок":
 		return VMFuncZeroParams(x.СлучайныйПорядок), true
-	// case "вставить":
-	// 	return VMFuncTwoParams[VMInt, VMValue](x.Вставить), true
-	// case "удалить":
-	// 	return VMFuncOneParam[VMInt](x.Удалить), true
+	case "вставить":
+		return VMFuncTwoParams(x.Вставить), true
+	case "удалить":
+		return VMFuncOneParam(x.Удалить), true
+	case "очистить":
+		return VMFuncZeroParams(x.Очистить), true
+	case "отобрать":
+		return VMFuncOneParam(x.Отобрать), true
+	case "преобразовать":
+		return VMFuncOneParam(x.Преобразовать), true
+	case "свернуть":
+		return VMFuncOneParamOptionals(1, x.Свернуть), true
+	case "сгруппировать":
+		return VMFuncNParams(1, func(args VMSlice, rets *VMSlice) error { return x.Сгруппировать(args[0], rets) }), true
+	case "любой":
+		return VMFuncOneParam(x.Любой), true
+	case "все":
+		return VMFuncOneParam(x.Все), true
+	case "срез":
+		return VMFuncNParamsOptionals(1, 1, x.Срез), true
 	case "копироватьуникальные":
 		return VMFuncZeroParams(x.КопироватьУникальные), true
 	}
@@ -128,11 +142,6 @@ func (x VMSlice) MethodMember(name int) (VMFunc, bool) {
 	return nil, false
 }
 
-func (x VMSlice) Сортировать(rets *VMSlice) error {
-	x.SortDefault()
-	return nil
-}
-
 // Найти (значение) (индекс, найдено) - находит индекс значения или места для его вставки (конец списка), если его еще нет
 // возврат унифицирован с возвратом функции НайтиСорт
 func (x VMSlice) Найти(args VMSlice, rets *VMSlice) error {
@@ -159,34 +168,6 @@ func (x VMSlice) СлучайныйПорядок(rets *VMSlice) error {
 	return nil
 }
 
-// Вставить (индекс, значение) - вставляет значение по индексу.
-// Индекс может быть равен длине, тогда вставка происходит в последний элемент.
-// Обычно используется в связке с НайтиСорт, т.к. позволяет вставлять значения с сохранением сортировки по возрастанию
-// func (x VMSlice) Вставить(args VMSlice, rets *VMSlice) error {
-// 	p := args[0].(VMInt)
-// 	if int(p) < 0 || int(p) > len(x) {
-// 		return VMErrorIndexOutOfBoundary
-// 	}
-// 	y := args[1]
-// 	x = append(x, VMNil)
-// 	copy(x[p+1:], x[p:])
-// 	x[p] = y
-// 	rets.Append(x)
-// 	return nil
-// }
-
-// func (x VMSlice) Удалить(args VMSlice, rets *VMSlice) error {
-// 	p := args[0].(VMInt)
-// 	if int(p) < 0 || int(p) >= len(x) {
-// 		return VMErrorIndexOutOfBoundary
-// 	}
-// 	copy(x[p:], x[p+1:])
-// 	x[len(x)-1] = nil
-// 	x = x[:len(x)-1]
-// 	rets.Append(x)
-// 	return nil
-// }
-
 func (x VMSlice) ОбратныйПорядок(rets *VMSlice) error {
 	for left, right := 0, len(x)-1; left < right; left, right = left+1, right-1 {
 		x[left], x[right] = x[right], x[left]
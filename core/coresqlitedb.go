@@ -215,6 +215,32 @@ func (r *VMSqliteQueryResult) scanRow() (VMStringMap, error) {
 	return results, nil
 }
 
+// Iterator позволяет перебирать строки результата в цикле Для каждого без выгрузки в память,
+// при выходе из цикла результат закрывается
+func (r *VMSqliteQueryResult) Iterator() (VMIterator, error) {
+	return &sqliteRowsIterator{r: r}, nil
+}
+
+type sqliteRowsIterator struct {
+	r *VMSqliteQueryResult
+}
+
+func (x *sqliteRowsIterator) VMTypeString() string { return "ИтераторSqliteЗапроса" }
+
+func (x *sqliteRowsIterator) Next() (VMValue, bool, error) {
+	if !x.r.Next() {
+		// возвращаем Err после неявного закрытия
+		return VMNil, false, x.r.Err()
+	}
+	vals, err := x.r.scanRow()
+	if err != nil {
+		return VMNil, false, err
+	}
+	return vals, true, nil
+}
+
+func (x *sqliteRowsIterator) Close() error { return x.r.Close() }
+
 func (r *VMSqliteQueryResult) Следующий(rets *VMSlice) error {
 	rets.Append(VMBool(r.Next()))
 	return nil
@@ -0,0 +1,310 @@
+package core
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// memStream буфер в памяти с произвольным доступом
+type memStream struct {
+	data []byte
+	pos  int64
+}
+
+func (m *memStream) Read(p []byte) (int, error) {
+	if m.pos >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, m.data[m.pos:])
+	m.pos += int64(n)
+	return n, nil
+}
+
+func (m *memStream) Write(p []byte) (int, error) {
+	end := m.pos + int64(len(p))
+	if end > int64(len(m.data)) {
+		if end > int64(cap(m.data)) {
+			nd := make([]byte, len(m.data), end*2)
+			copy(nd, m.data)
+			m.data = nd
+		}
+		m.data = m.data[:end]
+	}
+	copy(m.data[m.pos:], p)
+	m.pos = end
+	return len(p), nil
+}
+
+func (m *memStream) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = m.pos + offset
+	case io.SeekEnd:
+		abs = int64(len(m.data)) + offset
+	default:
+		return 0, VMErrorIncorrectOperation
+	}
+	if abs < 0 {
+		return 0, VMErrorIndexOutOfBoundary
+	}
+	m.pos = abs
+	return abs, nil
+}
+
+// vmStream общая часть потоков ПотокВПамяти и ФайловыйПоток,
+// реализует io.ReadWriteSeeker для использования в функциях на Го
+type vmStream struct {
+	VMMetaObj
+
+	rws    io.ReadWriteSeeker
+	closer io.Closer
+}
+
+func (x *vmStream) registerStreamMethods() {
+	x.VMRegisterMethod("Прочитать", VMFuncOneParam(x.Прочитать))
+	x.VMRegisterMethod("ПрочитатьВсе", VMFuncZeroParams(x.ПрочитатьВсе))
+	x.VMRegisterMethod("Записать", VMFuncOneParam(x.Записать))
+	x.VMRegisterMethod("Перейти", VMFuncOneParamOptionals(1, x.Перейти))
+	x.VMRegisterMethod("ТекущаяПозиция", VMFuncZeroParams(x.ТекущаяПозиция))
+	x.VMRegisterMethod("Размер", VMFuncZeroParams(x.Размер))
+	x.VMRegisterMethod("Закрыть", VMFuncZeroParams(x.Закрыть))
+}
+
+func (x *vmStream) Read(p []byte) (int, error) {
+	if x.rws == nil {
+		return 0, VMErrorStreamClosed
+	}
+	return x.rws.Read(p)
+}
+
+func (x *vmStream) Write(p []byte) (int, error) {
+	if x.rws == nil {
+		return 0, VMErrorStreamClosed
+	}
+	return x.rws.Write(p)
+}
+
+func (x *vmStream) Seek(offset int64, whence int) (int64, error) {
+	if x.rws == nil {
+		return 0, VMErrorStreamClosed
+	}
+	return x.rws.Seek(offset, whence)
+}
+
+func (x *vmStream) Close() error {
+	if x.rws == nil {
+		return nil
+	}
+	x.rws = nil
+	if x.closer != nil {
+		return x.closer.Close()
+	}
+	return nil
+}
+
+// Прочитать(Количество) возвращает не более указанного количества байт, в конце потока - пустые данные
+func (x *vmStream) Прочитать(n VMInt, rets *VMSlice) error {
+	if n < 0 {
+		return VMErrorIndexOutOfBoundary
+	}
+	buf := make([]byte, n)
+	k, err := io.ReadFull(x, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	rets.Append(VMBinaryData(buf[:k]))
+	return nil
+}
+
+func (x *vmStream) ПрочитатьВсе(rets *VMSlice) error {
+	b, err := io.ReadAll(x)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMBinaryData(b))
+	return nil
+}
+
+func (x *vmStream) Записать(v VMValue, rets *VMSlice) error {
+	b, err := binaryArg(v)
+	if err != nil {
+		return err
+	}
+	_, err = x.Write(b)
+	return err
+}
+
+// Перейти(Позиция, Откуда) - откуда: "Начало" (по умолчанию), "Текущая" или "Конец"
+func (x *vmStream) Перейти(pos VMInt, rest VMSlice, rets *VMSlice) error {
+	whence := io.SeekStart
+	if len(rest) > 0 {
+		from, ok := rest[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		switch strings.ToLower(string(from)) {
+		case "начало":
+		case "текущая":
+			whence = io.SeekCurrent
+		case "конец":
+			whence = io.SeekEnd
+		default:
+			return VMErrorIncorrectOperation
+		}
+	}
+	p, err := x.Seek(int64(pos), whence)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMInt(p))
+	return nil
+}
+
+func (x *vmStream) ТекущаяПозиция(rets *VMSlice) error {
+	p, err := x.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMInt(p))
+	return nil
+}
+
+func (x *vmStream) Размер(rets *VMSlice) error {
+	cur, err := x.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	end, err := x.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err = x.Seek(cur, io.SeekStart); err != nil {
+		return err
+	}
+	rets.Append(VMInt(end))
+	return nil
+}
+
+func (x *vmStream) Закрыть(rets *VMSlice) error {
+	return x.Close()
+}
+
+// VMMemoryStream ПотокВПамяти
+type VMMemoryStream struct {
+	vmStream
+
+	mem *memStream
+}
+
+func NewVMMemoryStream(data []byte) *VMMemoryStream {
+	x := &VMMemoryStream{}
+	x.VMInit(x)
+	x.VMRegister()
+	x.mem.data = append(x.mem.data, data...)
+	return x
+}
+
+func (x *VMMemoryStream) VMTypeString() string {
+	return "ПотокВПамяти"
+}
+
+func (x *VMMemoryStream) VMRegister() {
+	x.mem = &memStream{}
+	x.rws = x.mem
+
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) > 1 {
+			return VMErrorMaxArgs(1)
+		}
+		if len(args) == 1 {
+			b, err := binaryArg(args[0])
+			if err != nil {
+				return err
+			}
+			x.mem.data = append(x.mem.data[:0], b...)
+		}
+		return nil
+	})
+
+	x.registerStreamMethods()
+	x.VMRegisterMethod("ПолучитьДвоичныеДанные", VMFuncZeroParams(x.ПолучитьДвоичныеДанные))
+	x.VMRegisterMethod("ЗакрытьИПолучитьДвоичныеДанные", VMFuncZeroParams(x.ЗакрытьИПолучитьДвоичныеДанные))
+}
+
+// Bytes возвращает все содержимое потока независимо от текущей позиции
+func (x *VMMemoryStream) Bytes() []byte {
+	return x.mem.data
+}
+
+func (x *VMMemoryStream) ПолучитьДвоичныеДанные(rets *VMSlice) error {
+	rets.Append(append(VMBinaryData(nil), x.mem.data...))
+	return nil
+}
+
+func (x *VMMemoryStream) ЗакрытьИПолучитьДвоичныеДанные(rets *VMSlice) error {
+	rets.Append(VMBinaryData(x.mem.data))
+	x.mem = &memStream{}
+	return x.Close()
+}
+
+// VMFileStream ФайловыйПоток(ИмяФайла, Режим) - режимы: "Чтение" (по умолчанию),
+// "Запись" (файл создается заново), "Дописать" и "ЧтениеЗапись"
+type VMFileStream struct {
+	vmStream
+
+	name string
+}
+
+func (x *VMFileStream) VMTypeString() string {
+	return "ФайловыйПоток"
+}
+
+func (x *VMFileStream) VMRegister() {
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) < 1 || len(args) > 2 {
+			return VMErrorNeedArgs(1)
+		}
+		name, ok := args[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		mode := "чтение"
+		if len(args) > 1 {
+			m, ok := args[1].(VMString)
+			if !ok {
+				return VMErrorNeedString
+			}
+			mode = strings.ToLower(string(m))
+		}
+		return x.Open(string(name), mode)
+	})
+
+	x.registerStreamMethods()
+}
+
+func (x *VMFileStream) Open(name, mode string) error {
+	var flag int
+	switch mode {
+	case "чтение":
+		flag = os.O_RDONLY
+	case "запись":
+		flag = os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	case "дописать":
+		flag = os.O_RDWR | os.O_CREATE | os.O_APPEND
+	case "чтениезапись":
+		flag = os.O_RDWR | os.O_CREATE
+	default:
+		return VMErrorStreamMode
+	}
+	f, err := os.OpenFile(name, flag, 0o644)
+	if err != nil {
+		return err
+	}
+	x.name = name
+	x.rws, x.closer = f, f
+	return nil
+}
@@ -94,47 +94,11 @@ func (x VMString) Hash() VMString {
 }
 
 func (x VMString) Time() VMTime {
-	t, err := time.Parse(time.RFC3339, string(x))
-	if err == nil {
-		return VMTime(t)
-	}
-	t, err = time.ParseInLocation("2006-01-02T15:04:05", string(x), time.Local)
-	if err == nil {
-		return VMTime(t)
-	}
-	t, err = time.ParseInLocation("2006-01-02 15:04:05", string(x), time.Local)
-	if err == nil {
-		return VMTime(t)
-	}
-	t, err = time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", string(x))
-	if err == nil {
-		return VMTime(t)
-	}
-	t, err = time.ParseInLocation("02.01.2006 15:04:05", string(x), time.Local)
-	if err == nil {
-		return VMTime(t)
-	}
-	t, err = time.ParseInLocation("20060102150405", string(x), time.Local)
-	if err == nil {
-		return VMTime(t)
-	}
-	t, err = time.ParseInLocation("20060102", string(x), time.Local)
-	if err == nil {
-		return VMTime(t)
-	}
-	t, err = time.ParseInLocation("02.01.2006", string(x), time.Local)
-	if err == nil {
-		return VMTime(t)
-	}
-	t, err = time.ParseInLocation("2006-01-02", string(x), time.Local)
-	if err == nil {
-		return VMTime(t)
-	}
-	t, err = time.Parse(time.RFC1123, string(x))
-	if err == nil {
-		return VMTime(t)
+	t, err := ParseTimeInLocation(string(x), time.Local)
+	if err != nil {
+		panic(err.Error())
 	}
-	panic("Неверный формат даты и времени")
+	return t
 }
 
 func (x VMString) Bool() bool {
@@ -254,6 +218,8 @@ func (x VMString) ConvertToType(nt reflect.Type) (VMValue, error) {
 		return VMSliceFromJson(string(x))
 	case ReflectVMStringMap:
 		return VMStringMapFromJson(string(x))
+	case ReflectVMBinaryData:
+		return VMBinaryData(x), nil
 	}
 
 	// попробуем десериализировать структуру из json
@@ -1,12 +1,19 @@
 package core
 
+import (
+	"encoding/json"
+
+	"github.com/shinanca/gonec/names"
+)
+
 // ТаблицаЗначений
 
 type VMTableColumn struct {
 	VMMetaObj
 
 	cols *VMTableColumns
-	name string
+	name VMString
+	id   int // уникальный идентификатор имени без учета регистра
 }
 
 func NewVMTableColumn(vtcs *VMTableColumns) *VMTableColumn {
@@ -23,8 +30,33 @@ func (vtc *VMTableColumn) VMTypeString() string {
 }
 
 func (vtc *VMTableColumn) VMRegister() {
-	// vt.VMRegisterField("ПолеСтрока", &vt.ПолеСтрока)
-	// vt.VMRegisterMethod("Имя", vt.Имя)
+	vtc.VMRegisterField("Имя", &vtc.name)
+}
+
+// VMSetField имя колонки можно поменять, но только на уникальное
+func (vtc *VMTableColumn) VMSetField(name int, val VMValue) {
+	if name != names.UniqueNames.Set("Имя") {
+		vtc.VMMetaObj.VMSetField(name, val)
+		return
+	}
+	s, ok := val.(VMStringer)
+	if !ok {
+		panic(VMErrorNeedString)
+	}
+	id := names.UniqueNames.Set(s.String())
+	if c := vtc.cols.FindById(id); c != nil && c != vtc {
+		panic(VMErrorTableColumnExists)
+	}
+	vtc.name = VMString(s.String())
+	vtc.id = id
+}
+
+func (vtc *VMTableColumn) Name() string {
+	return string(vtc.name)
+}
+
+func (vtc *VMTableColumn) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(vtc.name))
 }
 
 type VMTableColumns struct {
@@ -49,15 +81,102 @@ func (vtcs *VMTableColumns) VMTypeString() string {
 
 func (vtcs *VMTableColumns) VMRegister() {
 	vtcs.cols = make([]*VMTableColumn, 0, 8)
-	// vt.VMRegisterField("ПолеСтрока", &vt.ПолеСтрока)
-	// vt.VMRegisterMethod("Имя", vt.Имя)
+	vtcs.VMRegisterMethod("Добавить", VMFuncOneParam(vtcs.Добавить))
+	vtcs.VMRegisterMethod("Найти", VMFuncOneParam(vtcs.Найти))
+	vtcs.VMRegisterMethod("Количество", VMFuncZeroParams(vtcs.Количество))
+	vtcs.VMRegisterMethod("Получить", VMFuncOneParam(vtcs.Получить))
+}
+
+// Add добавляет колонку, если колонка с таким именем уже есть - возвращает ее
+func (vtcs *VMTableColumns) Add(name string) *VMTableColumn {
+	id := names.UniqueNames.Set(name)
+	if c := vtcs.FindById(id); c != nil {
+		return c
+	}
+	c := NewVMTableColumn(vtcs)
+	c.name = VMString(name)
+	c.id = id
+	vtcs.cols = append(vtcs.cols, c)
+	return c
+}
+
+func (vtcs *VMTableColumns) FindById(id int) *VMTableColumn {
+	for _, c := range vtcs.cols {
+		if c.id == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (vtcs *VMTableColumns) indexById(id int) int {
+	for i, c := range vtcs.cols {
+		if c.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (vtcs *VMTableColumns) Добавить(name VMString, rets *VMSlice) error {
+	if vtcs.FindById(names.UniqueNames.Set(string(name))) != nil {
+		return VMErrorTableColumnExists
+	}
+	rets.Append(vtcs.Add(string(name)))
+	return nil
 }
 
+func (vtcs *VMTableColumns) Найти(name VMString, rets *VMSlice) error {
+	if c := vtcs.FindById(names.UniqueNames.Set(string(name))); c != nil {
+		rets.Append(c)
+	} else {
+		rets.Append(VMNil)
+	}
+	return nil
+}
+
+func (vtcs *VMTableColumns) Количество(rets *VMSlice) error {
+	rets.Append(vtcs.Length())
+	return nil
+}
+
+func (vtcs *VMTableColumns) Получить(idx VMInt, rets *VMSlice) error {
+	if idx < 0 || int(idx) >= len(vtcs.cols) {
+		return VMErrorIndexOutOfBoundary
+	}
+	rets.Append(vtcs.cols[idx])
+	return nil
+}
+
+func (vtcs *VMTableColumns) Slice() VMSlice {
+	rv := make(VMSlice, len(vtcs.cols))
+	for i, c := range vtcs.cols {
+		rv[i] = c
+	}
+	return rv
+}
+
+func (vtcs *VMTableColumns) Length() VMInt {
+	return VMInt(len(vtcs.cols))
+}
+
+func (vtcs *VMTableColumns) IndexVal(idx VMValue) VMValue {
+	if i, ok := idx.(VMInt); ok {
+		return vtcs.cols[int(i)]
+	}
+	panic("индекс должен быть числом")
+}
+
+func (vtcs *VMTableColumns) MarshalJSON() ([]byte, error) {
+	return json.Marshal(vtcs.cols)
+}
+
+// VMTableLine строка таблицы, значения колонок доступны как поля: Строка.Наименование
 type VMTableLine struct {
 	VMMetaObj
 
 	table *VMTable
-	line  VMSlice
+	line  VMSlice // значения по порядку колонок, может быть короче списка колонок
 }
 
 func NewVMTableLine(vt *VMTable) *VMTableLine {
@@ -73,9 +192,61 @@ func (vtl *VMTableLine) VMTypeString() string {
 	return "СтрокаТаблицыЗначений"
 }
 
-func (vtl *VMTableLine) VMRegister() {
-	// vt.VMRegisterField("ПолеСтрока", &vt.ПолеСтрока)
-	// vt.VMRegisterMethod("Имя", vt.Имя)
+func (vtl *VMTableLine) VMRegister() {}
+
+func (vtl *VMTableLine) VMIsField(name int) bool {
+	return vtl.table.cols.indexById(name) >= 0
+}
+
+func (vtl *VMTableLine) VMGetField(name int) VMValue {
+	i := vtl.table.cols.indexById(name)
+	if i < 0 {
+		panic("Невозможно получить значение поля")
+	}
+	return vtl.Index(i)
+}
+
+func (vtl *VMTableLine) VMSetField(name int, val VMValue) {
+	i := vtl.table.cols.indexById(name)
+	if i < 0 {
+		panic("Невозможно установить значение поля")
+	}
+	vtl.SetIndex(i, val)
+}
+
+// Index возвращает значение по номеру колонки
+func (vtl *VMTableLine) Index(i int) VMValue {
+	if i < len(vtl.line) && vtl.line[i] != nil {
+		return vtl.line[i]
+	}
+	return VMNil
+}
+
+// SetIndex устанавливает значение по номеру колонки
+func (vtl *VMTableLine) SetIndex(i int, val VMValue) {
+	for len(vtl.line) <= i {
+		vtl.line = append(vtl.line, VMNil)
+	}
+	vtl.line[i] = val
+}
+
+// Set устанавливает значение колонки по имени, колонка должна существовать
+func (vtl *VMTableLine) Set(name string, val VMValue) {
+	if i := vtl.table.cols.indexById(names.UniqueNames.Set(name)); i >= 0 {
+		vtl.SetIndex(i, val)
+	}
+}
+
+func (vtl *VMTableLine) StringMap() VMStringMap {
+	rv := make(VMStringMap, len(vtl.table.cols.cols))
+	for i, c := range vtl.table.cols.cols {
+		rv[c.Name()] = vtl.Index(i)
+	}
+	return rv
+}
+
+func (vtl *VMTableLine) MarshalJSON() ([]byte, error) {
+	return json.Marshal(vtl.StringMap())
 }
 
 type VMTable struct {
@@ -85,6 +256,13 @@ type VMTable struct {
 	lines []*VMTableLine
 }
 
+func NewVMTable() *VMTable {
+	vt := &VMTable{}
+	vt.VMInit(vt)
+	vt.VMRegister()
+	return vt
+}
+
 func (vt *VMTable) VMTypeString() string {
 	return "ТаблицаЗначений"
 }
@@ -92,11 +270,83 @@ func (vt *VMTable) VMTypeString() string {
 func (vt *VMTable) VMRegister() {
 	vt.cols = NewVMTableColumns(vt)
 
-	vt.lines = make([]*VMTableLine, 20)
-	vt.VMRegisterField("колонки", vt.cols)
+	vt.lines = make([]*VMTableLine, 0, 20)
+	vt.VMRegisterField("Колонки", vt.cols)
 
-	// vt.VMRegisterMethod("колонки", vt.Колонки)
-	// vt.VMRegisterField("ПолеСтрока", &vt.ПолеСтрока)
+	vt.VMRegisterMethod("Добавить", VMFuncZeroParams(vt.Добавить))
+	vt.VMRegisterMethod("Количество", VMFuncZeroParams(vt.Количество))
+	vt.VMRegisterMethod("Получить", VMFuncOneParam(vt.Получить))
+	vt.VMRegisterMethod("Удалить", VMFuncOneParam(vt.Удалить))
+	vt.VMRegisterMethod("Очистить", VMFuncZeroParams(vt.Очистить))
+	vt.VMRegisterMethod("ВыгрузитьКолонку", VMFuncOneParam(vt.ВыгрузитьКолонку))
+	vt.VMRegisterMethod("ВМассив", VMFuncZeroParams(vt.ВМассив))
+}
+
+func (vt *VMTable) Columns() *VMTableColumns {
+	return vt.cols
+}
+
+// AddLine добавляет пустую строку в конец таблицы
+func (vt *VMTable) AddLine() *VMTableLine {
+	l := NewVMTableLine(vt)
+	vt.lines = append(vt.lines, l)
+	return l
+}
+
+func (vt *VMTable) Добавить(rets *VMSlice) error {
+	rets.Append(vt.AddLine())
+	return nil
+}
+
+func (vt *VMTable) Количество(rets *VMSlice) error {
+	rets.Append(vt.Length())
+	return nil
+}
+
+func (vt *VMTable) Получить(idx VMInt, rets *VMSlice) error {
+	if idx < 0 || int(idx) >= len(vt.lines) {
+		return VMErrorIndexOutOfBoundary
+	}
+	rets.Append(vt.lines[idx])
+	return nil
+}
+
+func (vt *VMTable) Удалить(idx VMInt, rets *VMSlice) error {
+	if idx < 0 || int(idx) >= len(vt.lines) {
+		return VMErrorIndexOutOfBoundary
+	}
+	copy(vt.lines[idx:], vt.lines[idx+1:])
+	vt.lines[len(vt.lines)-1] = nil
+	vt.lines = vt.lines[:len(vt.lines)-1]
+	return nil
+}
+
+func (vt *VMTable) Очистить(rets *VMSlice) error {
+	vt.lines = vt.lines[:0]
+	return nil
+}
+
+func (vt *VMTable) ВыгрузитьКолонку(name VMString, rets *VMSlice) error {
+	i := vt.cols.indexById(names.UniqueNames.Set(string(name)))
+	if i < 0 {
+		return VMErrorTableColumnNotFound
+	}
+	rv := make(VMSlice, len(vt.lines))
+	for j, l := range vt.lines {
+		rv[j] = l.Index(i)
+	}
+	rets.Append(rv)
+	return nil
+}
+
+// ВМассив возвращает массив структур, ключи - имена колонок
+func (vt *VMTable) ВМассив(rets *VMSlice) error {
+	rv := make(VMSlice, len(vt.lines))
+	for i, l := range vt.lines {
+		rv[i] = l.StringMap()
+	}
+	rets.Append(rv)
+	return nil
 }
 
 func (vt *VMTable) Slice() VMSlice {
@@ -117,3 +367,7 @@ func (vt *VMTable) IndexVal(idx VMValue) VMValue {
 	}
 	panic("индекс должен быть числом")
 }
+
+func (vt *VMTable) MarshalJSON() ([]byte, error) {
+	return json.Marshal(vt.lines)
+}
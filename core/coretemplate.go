@@ -0,0 +1,386 @@
+package core
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"text/template/parse"
+)
+
+// VMTemplate Шаблон - формирование HTML-страниц и текста по шаблонам на языке шаблонов Го.
+// Данные передаются структурой или массивом, поля доступны как {{.Поле}}, перебор - {{range .Массив}}...{{end}}.
+// В режиме HTML значения экранируются с учетом контекста, вывести разметку как есть можно через {{безЭкранирования .Поле}}.
+// Части страниц подключаются как {{template "имя" .}}, макет объявляет места для страниц как {{block "содержимое" .}}{{end}},
+// а страница, выводимая с макетом, определяет их как {{define "содержимое"}}...{{end}}.
+// Шаблоны компилируются при первом выводе и кешируются до добавления новых шаблонов или функций,
+// поэтому один объект, созданный при запуске, можно использовать из обработчиков Сервера.
+// Параметры конструктора (необязательная структура):
+//
+//	Формат - "html" (по умолчанию) или "текст"
+type VMTemplate struct {
+	VMMetaObj
+
+	mu      sync.RWMutex
+	text    bool
+	names   []string          // имена шаблонов в порядке добавления
+	sources map[string]string // исходные тексты шаблонов
+	pages   map[string]bool   // шаблоны с определениями блоков, в макет подключается только выводимый
+	funcs   map[string]interface{}
+
+	base    templateEngine               // набор всех шаблонов для вывода без макета
+	layouts map[[2]string]templateEngine // наборы для вывода страницы в макете
+}
+
+// templateEngine общая часть наборов шаблонов html/template и text/template
+type templateEngine interface {
+	parse(name, src string) error
+	execute(w io.Writer, name string, data interface{}) error
+}
+
+type htmlEngine struct{ t *htmltemplate.Template }
+
+func (e htmlEngine) parse(name, src string) error {
+	_, err := e.t.New(name).Parse(src)
+	return err
+}
+
+func (e htmlEngine) execute(w io.Writer, name string, data interface{}) error {
+	return e.t.ExecuteTemplate(w, name, data)
+}
+
+type textEngine struct{ t *texttemplate.Template }
+
+func (e textEngine) parse(name, src string) error {
+	_, err := e.t.New(name).Parse(src)
+	return err
+}
+
+func (e textEngine) execute(w io.Writer, name string, data interface{}) error {
+	return e.t.ExecuteTemplate(w, name, data)
+}
+
+func (x *VMTemplate) VMTypeString() string {
+	return "Шаблон"
+}
+
+func (x *VMTemplate) String() string {
+	return "Шаблон"
+}
+
+func (x *VMTemplate) VMRegister() {
+	x.VMRegisterConstructor(func(args VMSlice) error {
+		if len(args) > 1 {
+			return VMErrorMaxArgs(1)
+		}
+		params := VMStringMap{}
+		if len(args) == 1 {
+			var ok bool
+			if params, ok = args[0].(VMStringMap); !ok {
+				return VMErrorNeedMap
+			}
+		}
+		return x.Init(params)
+	})
+
+	x.VMRegisterMethod("Добавить", VMFuncTwoParams(x.Добавить))
+	x.VMRegisterMethod("ДобавитьФайл", VMFuncOneParamOptionals(1, x.ДобавитьФайл))
+	x.VMRegisterMethod("ДобавитьФайлы", VMFuncOneParam(x.ДобавитьФайлы))
+	x.VMRegisterMethod("ЗарегистрироватьФункцию", VMFuncTwoParams(x.ЗарегистрироватьФункцию))
+	x.VMRegisterMethod("Вывести", VMFuncTwoParamsOptionals(1, x.Вывести))
+}
+
+func (x *VMTemplate) Init(params VMStringMap) error {
+	for k, v := range params {
+		switch strings.ToLower(k) {
+		case "формат":
+			switch strings.ToLower(fmt.Sprint(v)) {
+			case "html":
+				x.text = false
+			case "текст", "text":
+				x.text = true
+			default:
+				return VMErrorTemplateFormat
+			}
+		}
+	}
+	x.sources = make(map[string]string)
+	x.pages = make(map[string]bool)
+	x.funcs = map[string]interface{}{
+		"безЭкранирования": func(v interface{}) htmltemplate.HTML {
+			return htmltemplate.HTML(fmt.Sprint(v))
+		},
+	}
+	return nil
+}
+
+// Add добавляет шаблон или заменяет шаблон с тем же именем
+func (x *VMTemplate) Add(name, src string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.sources == nil {
+		x.Init(VMStringMap{})
+	}
+	if _, ok := x.sources[name]; !ok {
+		x.names = append(x.names, name)
+	}
+	x.sources[name] = src
+	x.pages[name] = definesBlocks(name, src)
+	x.reset()
+}
+
+// definesBlocks определяет, есть ли в шаблоне define или block.
+// Синтаксические ошибки здесь не учитываются, о них сообщается при компиляции
+func definesBlocks(name, src string) bool {
+	t := parse.New(name)
+	t.Mode = parse.SkipFuncCheck
+	trees := make(map[string]*parse.Tree)
+	if _, err := t.Parse(src, "", "", trees); err != nil {
+		return false
+	}
+	for n := range trees {
+		if n != name {
+			return true
+		}
+	}
+	return false
+}
+
+// reset сбрасывает скомпилированные наборы, вызывается под блокировкой
+func (x *VMTemplate) reset() {
+	x.base, x.layouts = nil, nil
+}
+
+// compile компилирует набор из шаблонов, для которых use возвращает true,
+// шаблон last добавляется последним, чтобы его определения заменили одноименные блоки
+func (x *VMTemplate) compile(use func(name string) bool, last string) (templateEngine, error) {
+	var e templateEngine
+	if x.text {
+		e = textEngine{texttemplate.New("").Funcs(x.funcs)}
+	} else {
+		e = htmlEngine{htmltemplate.New("").Funcs(x.funcs)}
+	}
+	for _, name := range x.names {
+		if name == last || !use(name) {
+			continue
+		}
+		if err := e.parse(name, x.sources[name]); err != nil {
+			return nil, fmt.Errorf("%w: %s", VMErrorTemplate, err)
+		}
+	}
+	if last != "" {
+		src, ok := x.sources[last]
+		if !ok {
+			return nil, fmt.Errorf("%w: шаблон %q не найден", VMErrorTemplate, last)
+		}
+		if err := e.parse(last, src); err != nil {
+			return nil, fmt.Errorf("%w: %s", VMErrorTemplate, err)
+		}
+	}
+	return e, nil
+}
+
+// engine возвращает скомпилированный набор для вывода страницы page в макете layout.
+// В набор макета входят сам макет, страница и шаблоны без определений блоков,
+// поэтому блоки других страниц не подменяют блоки выводимой
+func (x *VMTemplate) engine(page, layout string) (templateEngine, error) {
+	key := [2]string{page, layout}
+	x.mu.RLock()
+	e := x.base
+	if layout != "" {
+		e = x.layouts[key]
+	}
+	x.mu.RUnlock()
+	if e != nil {
+		return e, nil
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	var err error
+	if layout == "" {
+		if x.base == nil {
+			x.base, err = x.compile(func(string) bool { return true }, "")
+		}
+		return x.base, err
+	}
+	if e, ok := x.layouts[key]; ok {
+		return e, nil
+	}
+	e, err = x.compile(func(name string) bool { return name == layout || !x.pages[name] }, page)
+	if err != nil {
+		return nil, err
+	}
+	if x.layouts == nil {
+		x.layouts = make(map[[2]string]templateEngine)
+	}
+	x.layouts[key] = e
+	return e, nil
+}
+
+// Render выводит шаблон name с данными data, при непустом layout страница выводится внутри макета
+func (x *VMTemplate) Render(name, layout string, data VMValue) (string, error) {
+	e, err := x.engine(name, layout)
+	if err != nil {
+		return "", err
+	}
+	exec := name
+	if layout != "" {
+		exec = layout
+	}
+	var sb strings.Builder
+	if err := e.execute(&sb, exec, templateValue(data)); err != nil {
+		return "", fmt.Errorf("%w: %s", VMErrorTemplate, err)
+	}
+	return sb.String(), nil
+}
+
+// templateValue преобразует данные в значения Го, с которыми работают условия и перебор в шаблонах
+func templateValue(v VMValue) interface{} {
+	switch vv := v.(type) {
+	case nil, VMNilType, VMNullType:
+		return nil
+	case VMString:
+		return string(vv)
+	case VMInt:
+		return int64(vv)
+	case VMBool:
+		return bool(vv)
+	case VMStringMap:
+		rv := make(map[string]interface{}, len(vv))
+		for k, el := range vv {
+			rv[k] = templateValue(el)
+		}
+		return rv
+	case VMSlice:
+		rv := make([]interface{}, len(vv))
+		for i, el := range vv {
+			rv[i] = templateValue(el)
+		}
+		return rv
+	case *VMOrderedMap:
+		rv := make(map[string]interface{}, len(vv.keys))
+		for i, k := range vv.keys {
+			rv[fmt.Sprint(k)] = templateValue(vv.vals[i])
+		}
+		return rv
+	}
+	return v
+}
+
+// vmValueFromTemplate преобразует аргумент функции, вызванной из шаблона, в значение вирт. машины
+func vmValueFromTemplate(v interface{}) VMValue {
+	switch vv := v.(type) {
+	case nil:
+		return VMNil
+	case VMValue:
+		return vv
+	case htmltemplate.HTML:
+		return VMString(vv)
+	case map[string]interface{}:
+		rv := make(VMStringMap, len(vv))
+		for k, el := range vv {
+			rv[k] = vmValueFromTemplate(el)
+		}
+		return rv
+	case []interface{}:
+		rv := make(VMSlice, len(vv))
+		for i, el := range vv {
+			rv[i] = vmValueFromTemplate(el)
+		}
+		return rv
+	}
+	return ReflectToVMValue(reflect.ValueOf(v))
+}
+
+// Добавить(Имя, Текст) добавляет шаблон страницы, части страницы или макета
+func (x *VMTemplate) Добавить(name, src VMString, rets *VMSlice) error {
+	x.Add(string(name), string(src))
+	return nil
+}
+
+// ДобавитьФайл(Путь[, Имя]) добавляет шаблон из файла, по умолчанию имя шаблона - имя файла
+func (x *VMTemplate) ДобавитьФайл(path VMString, rest VMSlice, rets *VMSlice) error {
+	name := filepath.Base(string(path))
+	if len(rest) > 0 {
+		n, ok := rest[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		name = string(n)
+	}
+	b, err := os.ReadFile(string(path))
+	if err != nil {
+		return err
+	}
+	x.Add(name, string(b))
+	return nil
+}
+
+// ДобавитьФайлы(Маска) добавляет шаблоны из всех файлов по маске, например "шаблоны/*.html"
+func (x *VMTemplate) ДобавитьФайлы(pattern VMString, rets *VMSlice) error {
+	files, err := filepath.Glob(string(pattern))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%w: нет файлов по маске %s", VMErrorTemplate, pattern)
+	}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		x.Add(filepath.Base(f), string(b))
+	}
+	return nil
+}
+
+// ЗарегистрироватьФункцию(Имя, Функция) делает функцию доступной в шаблонах как {{Имя .Поле "строка"}}.
+// Функция получает аргументы как обычные значения и возвращает одно значение
+func (x *VMTemplate) ЗарегистрироватьФункцию(name VMString, f VMFunc, rets *VMSlice) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.funcs == nil {
+		x.Init(VMStringMap{})
+	}
+	x.funcs[string(name)] = func(args ...interface{}) (interface{}, error) {
+		vargs := make(VMSlice, len(args))
+		for i, a := range args {
+			vargs[i] = vmValueFromTemplate(a)
+		}
+		var frets VMSlice
+		if err := f(vargs, &frets); err != nil {
+			return nil, err
+		}
+		if len(frets) == 0 {
+			return nil, nil
+		}
+		return templateValue(frets[0]), nil
+	}
+	x.reset()
+	return nil
+}
+
+// Вывести(Имя, Данные[, Макет]) возвращает строку с результатом вывода шаблона
+func (x *VMTemplate) Вывести(name VMString, data VMValue, rest VMSlice, rets *VMSlice) error {
+	layout := ""
+	if len(rest) > 0 {
+		l, ok := rest[0].(VMString)
+		if !ok {
+			return VMErrorNeedString
+		}
+		layout = string(l)
+	}
+	s, err := x.Render(string(name), layout, data)
+	if err != nil {
+		return err
+	}
+	rets.Append(VMString(s))
+	return nil
+}
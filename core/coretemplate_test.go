@@ -0,0 +1,48 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplateFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"макет.html":   `<main>{{block "содержимое" .}}{{end}}</main>`,
+		"главная.html": `{{define "содержимое"}}{{.}}{{end}}`,
+		"заметка.txt":  `не шаблон html`,
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	x := &VMTemplate{}
+	if err := x.Init(VMStringMap{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.ДобавитьФайлы(VMString(filepath.Join(dir, "*.html")), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.ДобавитьФайл(VMString(filepath.Join(dir, "заметка.txt")), VMSlice{VMString("заметка")}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := x.Render("главная.html", "макет.html", VMString("<a>")); err != nil || s != "<main>&lt;a&gt;</main>" {
+		t.Errorf("Render() = %q, %v", s, err)
+	}
+	if s, err := x.Render("заметка", "", VMNil); err != nil || s != "не шаблон html" {
+		t.Errorf("Render() = %q, %v", s, err)
+	}
+	if err := x.ДобавитьФайлы(VMString(filepath.Join(dir, "*.tmpl")), nil); !errors.Is(err, VMErrorTemplate) {
+		t.Errorf("пустая маска: %v", err)
+	}
+	if _, err := x.Render("нет", "", VMNil); !errors.Is(err, VMErrorTemplate) {
+		t.Errorf("нет шаблона: %v", err)
+	}
+	if err := (&VMTemplate{}).Init(VMStringMap{"Формат": VMString("pdf")}); !errors.Is(err, VMErrorTemplateFormat) {
+		t.Errorf("неизвестный формат: %v", err)
+	}
+}
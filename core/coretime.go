@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	_ "time/tzdata" // встроенная база часовых поясов, если в системе нет /usr/share/zoneinfo
 
 	"github.com/shinanca/gonec/names"
 )
@@ -481,12 +482,38 @@ func (t VMTime) MethodMember(name int) (VMFunc, bool) {
 		return VMFuncZeroParams(t.ВремяUTC), true
 	case "локация":
 		return VMFuncZeroParams(t.Локация), true
-	case "влокации":
+	case "влокации", "взоне":
 		return VMFuncOneParam(t.ВЛокации), true
+	case "сзоной":
+		return VMFuncOneParam(t.СЗоной), true
+	case "смещениезоны":
+		return VMFuncZeroParams(t.СмещениеЗоны), true
+	case "имязоны":
+		return VMFuncZeroParams(t.ИмяЗоны), true
 	case "началодня":
 		return VMFuncZeroParams(t.НачалоДня), true
 	case "конецдня":
 		return VMFuncZeroParams(t.КонецДня), true
+	case "началонедели":
+		return VMFuncZeroParams(t.НачалоНедели), true
+	case "конецнедели":
+		return VMFuncZeroParams(t.КонецНедели), true
+	case "началомесяца":
+		return VMFuncZeroParams(t.НачалоМесяца), true
+	case "конецмесяца":
+		return VMFuncZeroParams(t.КонецМесяца), true
+	case "началоквартала":
+		return VMFuncZeroParams(t.НачалоКвартала), true
+	case "конецквартала":
+		return VMFuncZeroParams(t.КонецКвартала), true
+	case "началогода":
+		return VMFuncZeroParams(t.НачалоГода), true
+	case "конецгода":
+		return VMFuncZeroParams(t.КонецГода), true
+	case "разностьвднях":
+		return VMFuncOneParam(t.РазностьВДнях), true
+	case "разностьвмесяцах":
+		return VMFuncOneParam(t.РазностьВМесяцах), true
 	}
 
 	return nil, false
@@ -553,7 +580,7 @@ func (t VMTime) ДеньНедели(rets *VMSlice) error {
 
 func (t VMTime) Quarter() VMInt {
 	// 1-4
-	return VMInt(int64(time.Time(t).Month())/4 + 1)
+	return VMInt((int64(time.Time(t).Month())-1)/3 + 1)
 }
 
 func (t VMTime) Квартал(rets *VMSlice) error {
@@ -646,6 +673,12 @@ func (t VMTime) Юникс(rets *VMSlice) error {
 }
 
 func (t VMTime) Формат(fmtstr VMString, rets *VMSlice) error {
+	rets.Append(VMString(t.Format1C(string(fmtstr))))
+	return nil
+}
+
+// Format1C форматирует дату по шаблону в стиле 1С
+func (t VMTime) Format1C(fmtstr string) string {
 	// д (d) - день месяца (цифрами) без лидирующего нуля;
 	// дд (dd) - день месяца (цифрами) с лидирующим нулем;
 	// ддд (ddd) - краткое название дня недели *);
@@ -661,8 +694,8 @@ func (t VMTime) Формат(fmtstr VMString, rets *VMSlice) error {
 	// гг (yy) - номер года без века с лидирующим нулем;
 	// гггг (yyyy) - номер года с веком;
 
-	// ч (h) - час в 24 часовом варианте без лидирующих нулей;
-	// чч (hh) - час в 24 часовом варианте с лидирующим нулем;
+	// ч (h), Ч (H) - час в 24 часовом варианте без лидирующих нулей;
+	// чч (hh), ЧЧ (HH) - час в 24 часовом варианте с лидирующим нулем;
 
 	// м (m) - минута без лидирующего нуля;
 	// мм (mm) - минута с лидирующим нулем;
@@ -671,6 +704,13 @@ func (t VMTime) Формат(fmtstr VMString, rets *VMSlice) error {
 	// сс (ss) - секунда с лидирующим нулем;
 	// ссс (sss) - миллисекунда с лидирующим нулем
 
+	// ЗЗЗЗ (zzzz) - имя часового пояса, например Europe/Moscow;
+	// ЗЗЗ (zzz) - сокращенное имя часового пояса, например MSK;
+	// ЗЗ (zz) - смещение относительно UTC с двоеточием, например +03:00;
+	// З (z) - смещение относительно UTC без двоеточия, например +0300
+
+	// текст в двойных или одинарных кавычках выводится как есть, например дд "г."
+
 	days := [...]string{
 		"воскресенье",
 		"понедельник",
@@ -723,7 +763,7 @@ func (t VMTime) Формат(fmtstr VMString, rets *VMSlice) error {
 		"декабря",
 	}
 
-	src := []rune(string(fmtstr))
+	src := []rune(fmtstr)
 	res := make([]rune, 0, len(src)*2)
 	wasday := false
 	hour, min, sec := time.Time(t).Clock()
@@ -752,6 +792,10 @@ func (t VMTime) Формат(fmtstr VMString, rets *VMSlice) error {
 				res = append(res, []rune(strconv.FormatInt(int64(t.Year()), 10))...)
 				i += 4
 				continue
+			case "ЗЗЗЗ", "zzzz":
+				res = append(res, []rune(t.LocationString())...)
+				i += 4
+				continue
 
 			}
 		}
@@ -779,6 +823,11 @@ func (t VMTime) Формат(fmtstr VMString, rets *VMSlice) error {
 				res = append(res, []rune(sm)...)
 				i += 3
 				continue
+			case "ЗЗЗ", "zzz":
+				name, _ := time.Time(t).Zone()
+				res = append(res, []rune(name)...)
+				i += 3
+				continue
 			}
 		}
 
@@ -810,7 +859,7 @@ func (t VMTime) Формат(fmtstr VMString, rets *VMSlice) error {
 				res = append(res, []rune(sm)...)
 				i += 2
 				continue
-			case "чч", "hh":
+			case "чч", "hh", "ЧЧ", "HH":
 				sm := strconv.Itoa(int(hour))
 				if len(sm) < 2 {
 					sm = "0" + sm
@@ -834,11 +883,23 @@ func (t VMTime) Формат(fmtstr VMString, rets *VMSlice) error {
 				res = append(res, []rune(sm)...)
 				i += 2
 				continue
+			case "ЗЗ", "zz":
+				res = append(res, []rune(time.Time(t).Format("-07:00"))...)
+				i += 2
+				continue
 			}
 		}
 
 		c := src[i]
 		switch c {
+		case '"', '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				j++
+			}
+			res = append(res, src[i+1:j]...)
+			i = j + 1
+			continue
 		case 'д', 'd':
 			sm := strconv.Itoa(d)
 			res = append(res, []rune(sm)...)
@@ -855,7 +916,7 @@ func (t VMTime) Формат(fmtstr VMString, rets *VMSlice) error {
 			res = append(res, []rune(sm)...)
 			i++
 			continue
-		case 'ч', 'h':
+		case 'ч', 'h', 'Ч', 'H':
 			sm := strconv.Itoa(int(hour))
 			res = append(res, []rune(sm)...)
 			i++
@@ -875,13 +936,16 @@ func (t VMTime) Формат(fmtstr VMString, rets *VMSlice) error {
 			res = append(res, []rune(sm)...)
 			i++
 			continue
+		case 'З', 'z':
+			res = append(res, []rune(time.Time(t).Format("-0700"))...)
+			i++
+			continue
 		}
 		res = append(res, c)
 		i++
 	}
 
-	rets.Append(VMString(string(res)))
-	return nil
+	return string(res)
 }
 
 func (t VMTime) Sub(t2 VMTime) VMTimeDuration {
@@ -981,7 +1045,7 @@ func (t VMTime) InLocation(name string) VMTime {
 }
 
 func (t VMTime) ВЛокации(locs VMString, rets *VMSlice) error { //(name string) VMTime {
-	loc, err := time.LoadLocation(string(locs))
+	loc, err := LoadTimeZone(string(locs))
 	if err != nil {
 		return err
 	}
@@ -989,6 +1053,113 @@ func (t VMTime) ВЛокации(locs VMString, rets *VMSlice) error { //(name s
 	return nil
 }
 
+// СЗоной(Зона) возвращает дату с теми же числом и временем, но в другом часовом поясе
+func (t VMTime) СЗоной(locs VMString, rets *VMSlice) error {
+	loc, err := LoadTimeZone(string(locs))
+	if err != nil {
+		return err
+	}
+	tt := time.Time(t)
+	rets.Append(VMTime(time.Date(tt.Year(), tt.Month(), tt.Day(), tt.Hour(), tt.Minute(), tt.Second(), tt.Nanosecond(), loc)))
+	return nil
+}
+
+// СмещениеЗоны() возвращает смещение часового пояса даты относительно UTC в секундах
+func (t VMTime) СмещениеЗоны(rets *VMSlice) error {
+	_, off := time.Time(t).Zone()
+	rets.Append(VMInt(off))
+	return nil
+}
+
+// ИмяЗоны() возвращает сокращенное имя часового пояса на дату, например MSK
+func (t VMTime) ИмяЗоны(rets *VMSlice) error {
+	name, _ := time.Time(t).Zone()
+	rets.Append(VMString(name))
+	return nil
+}
+
+// LoadTimeZone возвращает часовой пояс по имени из базы IANA (Europe/Moscow), Local, UTC,
+// или фиксированное смещение вида +03:00, -0530, UTC+3
+func LoadTimeZone(name string) (*time.Location, error) {
+	switch strings.ToLower(name) {
+	case "", "local", "местное":
+		return time.Local, nil
+	case "utc":
+		return time.UTC, nil
+	}
+	off := name
+	if len(off) > 3 && (strings.EqualFold(off[:3], "UTC") || strings.EqualFold(off[:3], "GMT")) {
+		off = off[3:]
+	}
+	if off[0] == '+' || off[0] == '-' {
+		hh, mm, ok := strings.Cut(off[1:], ":")
+		if !ok && len(hh) == 4 {
+			hh, mm = hh[:2], hh[2:]
+		}
+		h, err := strconv.Atoi(hh)
+		if err != nil || h > 14 {
+			return nil, VMErrorTimeZone
+		}
+		m := 0
+		if mm != "" {
+			if m, err = strconv.Atoi(mm); err != nil || m > 59 {
+				return nil, VMErrorTimeZone
+			}
+		}
+		sec := (h*60 + m) * 60
+		if off[0] == '-' {
+			sec = -sec
+		}
+		return time.FixedZone(name, sec), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, VMErrorTimeZone
+	}
+	return loc, nil
+}
+
+// timeZoneArg возвращает часовой пояс из необязательного строкового аргумента с индексом i
+func timeZoneArg(args VMSlice, i int) (*time.Location, error) {
+	if i >= len(args) {
+		return time.Local, nil
+	}
+	s, ok := args[i].(VMString)
+	if !ok {
+		return nil, VMErrorNeedString
+	}
+	return LoadTimeZone(string(s))
+}
+
+var timeLayouts = [...]string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"02.01.2006 15:04:05",
+	"20060102150405",
+	"20060102",
+	"02.01.2006",
+	"2006-01-02",
+}
+
+// ParseTimeInLocation разбирает дату в одном из поддерживаемых форматов.
+// Если смещение не указано в самой строке, дата считается заданной в часовом поясе loc.
+func ParseTimeInLocation(s string, loc *time.Location) (VMTime, error) {
+	for _, layout := range [...]string{time.RFC3339, "2006-01-02 15:04:05.999999999 -0700 MST"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return VMTime(t), nil
+		}
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return VMTime(t), nil
+		}
+	}
+	if t, err := time.Parse(time.RFC1123, s); err == nil {
+		return VMTime(t), nil
+	}
+	return VMTime{}, VMErrorTimeFormat
+}
+
 func (t VMTime) НачалоДня(rets *VMSlice) error { //(name string) VMTime {
 	roundedTime := VMTime(time.Date(int(t.Year()), time.Month(t.Month()), int(t.Day()), 0, 0, 0, 0, time.Time(t).Location()))
 	rets.Append(roundedTime)
@@ -1001,6 +1172,115 @@ func (t VMTime) КонецДня(rets *VMSlice) error { //(name string) VMTime {
 	return nil
 }
 
+// BeginOfPeriod возвращает начало дня, с которого начинается период из months месяцев,
+// содержащий дату (1 - месяц, 3 - квартал, 12 - год)
+func (t VMTime) BeginOfPeriod(months int) VMTime {
+	tt := time.Time(t)
+	m := (int(tt.Month())-1)/months*months + 1
+	return VMTime(time.Date(tt.Year(), time.Month(m), 1, 0, 0, 0, 0, tt.Location()))
+}
+
+// EndOfPeriod возвращает последнюю секунду периода из months месяцев, содержащего дату
+func (t VMTime) EndOfPeriod(months int) VMTime {
+	b := time.Time(t.BeginOfPeriod(months)).AddDate(0, months, 0)
+	return VMTime(b.Add(-time.Second))
+}
+
+// BeginOfWeek возвращает начало понедельника недели, содержащей дату
+func (t VMTime) BeginOfWeek() VMTime {
+	tt := time.Time(t)
+	wd := (int(tt.Weekday()) + 6) % 7 // 0=понедельник
+	return VMTime(time.Date(tt.Year(), tt.Month(), tt.Day()-wd, 0, 0, 0, 0, tt.Location()))
+}
+
+func (t VMTime) НачалоНедели(rets *VMSlice) error {
+	rets.Append(t.BeginOfWeek())
+	return nil
+}
+
+func (t VMTime) КонецНедели(rets *VMSlice) error {
+	b := time.Time(t.BeginOfWeek())
+	rets.Append(VMTime(time.Date(b.Year(), b.Month(), b.Day()+6, 23, 59, 59, 0, b.Location())))
+	return nil
+}
+
+func (t VMTime) НачалоМесяца(rets *VMSlice) error {
+	rets.Append(t.BeginOfPeriod(1))
+	return nil
+}
+
+func (t VMTime) КонецМесяца(rets *VMSlice) error {
+	rets.Append(t.EndOfPeriod(1))
+	return nil
+}
+
+func (t VMTime) НачалоКвартала(rets *VMSlice) error {
+	rets.Append(t.BeginOfPeriod(3))
+	return nil
+}
+
+func (t VMTime) КонецКвартала(rets *VMSlice) error {
+	rets.Append(t.EndOfPeriod(3))
+	return nil
+}
+
+func (t VMTime) НачалоГода(rets *VMSlice) error {
+	rets.Append(t.BeginOfPeriod(12))
+	return nil
+}
+
+func (t VMTime) КонецГода(rets *VMSlice) error {
+	rets.Append(t.EndOfPeriod(12))
+	return nil
+}
+
+// DaysBetween возвращает количество календарных дней от даты d до t без учета времени,
+// переход на летнее время не влияет на результат
+func (t VMTime) DaysBetween(d VMTime) VMInt {
+	tt, dd := time.Time(t), time.Time(d).In(time.Time(t).Location())
+	t0 := time.Date(tt.Year(), tt.Month(), tt.Day(), 0, 0, 0, 0, time.UTC)
+	d0 := time.Date(dd.Year(), dd.Month(), dd.Day(), 0, 0, 0, 0, time.UTC)
+	return VMInt(t0.Sub(d0) / (24 * time.Hour))
+}
+
+// MonthsBetween возвращает количество полных месяцев от даты d до t, отрицательное если t раньше d.
+// Месяц считается полным, когда наступил тот же день и время следующего месяца,
+// для конца месяца - его последний день (31 января + 1 месяц = 28 или 29 февраля).
+func (t VMTime) MonthsBetween(d VMTime) VMInt {
+	tt, dd := time.Time(t), time.Time(d).In(time.Time(t).Location())
+	if tt.Before(dd) {
+		return -VMTime(dd).MonthsBetween(VMTime(tt))
+	}
+	n := (tt.Year()-dd.Year())*12 + int(tt.Month()) - int(dd.Month())
+	if n > 0 && tt.Before(addMonthsClamped(dd, n)) {
+		n--
+	}
+	return VMInt(n)
+}
+
+// addMonthsClamped прибавляет месяцы, не переходя за конец получившегося месяца
+func addMonthsClamped(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// РазностьВДнях(Дата) возвращает количество календарных дней от указанной даты до текущей
+func (t VMTime) РазностьВДнях(d VMTime, rets *VMSlice) error {
+	rets.Append(t.DaysBetween(d))
+	return nil
+}
+
+// РазностьВМесяцах(Дата) возвращает количество полных месяцев от указанной даты до текущей
+func (t VMTime) РазностьВМесяцах(d VMTime, rets *VMSlice) error {
+	rets.Append(t.MonthsBetween(d))
+	return nil
+}
+
 // EvalBinOp сравнивает два значения или выполняет бинарную операцию
 func (x VMTime) EvalBinOp(op VMOperation, y VMOperationer) (VMValue, error) {
 	switch op {
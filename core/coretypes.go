@@ -94,6 +94,13 @@ const (
 	VMDURATION
 	VMNIL
 	VMNULL
+	VMBINARYDATA
+	VMORDEREDMAP
+	VMSET
+	VMQUEUE
+	VMDEQUE
+	VMPRIORITYQUEUE
+	VMOBJECT
 )
 
 func (x VMBinaryType) ParseBinary(data []byte) (VMValue, error) {
@@ -134,6 +141,97 @@ func (x VMBinaryType) ParseBinary(data []byte) (VMValue, error) {
 		return VMNil, nil
 	case VMNULL:
 		return VMNullVar, nil
+	case VMBINARYDATA:
+		var v VMBinaryData
+		err := (&v).UnmarshalBinary(data)
+		return v, err
+	case VMORDEREDMAP:
+		v := NewVMOrderedMap()
+		err := v.UnmarshalBinary(data)
+		return v, err
+	case VMSET:
+		v := NewVMSet()
+		err := v.UnmarshalBinary(data)
+		return v, err
+	case VMQUEUE:
+		v := NewVMQueue()
+		err := v.UnmarshalBinary(data)
+		return v, err
+	case VMDEQUE:
+		v := NewVMDeque()
+		err := v.UnmarshalBinary(data)
+		return v, err
+	case VMPRIORITYQUEUE:
+		v := NewVMPriorityQueue()
+		err := v.UnmarshalBinary(data)
+		return v, err
+	case VMOBJECT:
+		v := &VMObject{}
+		err := v.UnmarshalBinary(data)
+		return v, err
+	}
+	return nil, VMErrorUnknownType
+}
+
+// ParseJSON восстанавливает значение известного типа из JSON, аналогично ParseBinary.
+// Типы вложенных значений в JSON не сохраняются, поэтому, например, даты внутри структуры станут строками.
+func (x VMBinaryType) ParseJSON(data []byte) (VMValue, error) {
+	switch x {
+	case VMBOOL:
+		var v VMBool
+		err := (&v).UnmarshalJSON(data)
+		return v, err
+	case VMINT:
+		var v VMInt
+		err := (&v).UnmarshalJSON(data)
+		return v, err
+	case VMDECNUM:
+		var v VMDecNum
+		err := (&v).UnmarshalJSON(data)
+		return v, err
+	case VMSTRING:
+		var v VMString
+		err := (&v).UnmarshalJSON(data)
+		return v, err
+	case VMSLICE:
+		var v VMSlice
+		err := (&v).UnmarshalJSON(data)
+		return v, err
+	case VMSTRINGMAP:
+		var v VMStringMap
+		err := (&v).UnmarshalJSON(data)
+		return v, err
+	case VMTIME:
+		var v VMTime
+		err := (&v).UnmarshalJSON(data)
+		return v, err
+	case VMDURATION:
+		var v VMTimeDuration
+		err := (&v).UnmarshalJSON(data)
+		return v, err
+	case VMNIL:
+		return VMNil, nil
+	case VMNULL:
+		return VMNullVar, nil
+	case VMBINARYDATA:
+		var v VMBinaryData
+		err := (&v).UnmarshalJSON(data)
+		return v, err
+	case VMORDEREDMAP:
+		v := NewVMOrderedMap()
+		err := v.UnmarshalJSON(data)
+		return v, err
+	case VMSET, VMQUEUE, VMDEQUE, VMPRIORITYQUEUE:
+		// коллекции выгружаются в JSON как массив элементов
+		sl, err := VMSliceFromJson(string(data))
+		if err != nil {
+			return nil, err
+		}
+		bb, err := sl.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return x.ParseBinary(bb)
 	}
 	return nil, VMErrorUnknownType
 }
@@ -389,6 +487,9 @@ func ReflectToVMValue(rv reflect.Value) VMInterfacer {
 			return x
 		}
 	case reflect.Array, reflect.Slice:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return VMBinaryData(rv.Bytes())
+		}
 		// проверяем, может это VMSlicer
 		if x, ok := rv.Interface().(VMSlicer); ok {
 			return x
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"crypto/rand"
+	"hash/crc32"
+	"io"
+)
+
+// традиционное шифрование PKWARE (ZipCrypto) - поддерживается всеми архиваторами,
+// но криптографически слабое, применяется только для совместимости
+
+const zipCryptoHeaderLen = 12
+
+type zipCryptoKeys [3]uint32
+
+func newZipCryptoKeys(password string) *zipCryptoKeys {
+	k := &zipCryptoKeys{0x12345678, 0x23456789, 0x34567890}
+	for i := 0; i < len(password); i++ {
+		k.update(password[i])
+	}
+	return k
+}
+
+func zipCryptoCRC(crc uint32, b byte) uint32 {
+	return (crc >> 8) ^ crc32.IEEETable[byte(crc)^b]
+}
+
+func (k *zipCryptoKeys) update(b byte) {
+	k[0] = zipCryptoCRC(k[0], b)
+	k[1] = (k[1]+(k[0]&0xff))*134775813 + 1
+	k[2] = zipCryptoCRC(k[2], byte(k[1]>>24))
+}
+
+func (k *zipCryptoKeys) stream() byte {
+	t := k[2] | 2
+	return byte((t * (t ^ 1)) >> 8)
+}
+
+func (k *zipCryptoKeys) encrypt(p []byte) {
+	for i, b := range p {
+		p[i] = b ^ k.stream()
+		k.update(b)
+	}
+}
+
+func (k *zipCryptoKeys) decrypt(p []byte) {
+	for i, c := range p {
+		b := c ^ k.stream()
+		k.update(b)
+		p[i] = b
+	}
+}
+
+// zipCryptoWriter шифрует поток, первым записывается заголовок с проверочным байтом
+type zipCryptoWriter struct {
+	w    io.Writer
+	keys *zipCryptoKeys
+	buf  []byte
+}
+
+func newZipCryptoWriter(w io.Writer, password string, check byte) (*zipCryptoWriter, error) {
+	zw := &zipCryptoWriter{w: w, keys: newZipCryptoKeys(password)}
+	hdr := make([]byte, zipCryptoHeaderLen)
+	if _, err := rand.Read(hdr); err != nil {
+		return nil, err
+	}
+	hdr[zipCryptoHeaderLen-1] = check
+	zw.keys.encrypt(hdr)
+	if _, err := w.Write(hdr); err != nil {
+		return nil, err
+	}
+	return zw, nil
+}
+
+func (zw *zipCryptoWriter) Write(p []byte) (int, error) {
+	if cap(zw.buf) < len(p) {
+		zw.buf = make([]byte, len(p))
+	}
+	b := zw.buf[:len(p)]
+	copy(b, p)
+	zw.keys.encrypt(b)
+	return zw.w.Write(b)
+}
+
+// zipCryptoReader расшифровывает поток после проверки пароля по заголовку
+type zipCryptoReader struct {
+	r    io.Reader
+	keys *zipCryptoKeys
+}
+
+func newZipCryptoReader(r io.Reader, password string, check byte) (*zipCryptoReader, error) {
+	zr := &zipCryptoReader{r: r, keys: newZipCryptoKeys(password)}
+	hdr := make([]byte, zipCryptoHeaderLen)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, err
+	}
+	zr.keys.decrypt(hdr)
+	if hdr[zipCryptoHeaderLen-1] != check {
+		return nil, VMErrorZipPassword
+	}
+	return zr, nil
+}
+
+func (zr *zipCryptoReader) Read(p []byte) (int, error) {
+	n, err := zr.r.Read(p)
+	zr.keys.decrypt(p[:n])
+	return n, err
+}
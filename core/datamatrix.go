@@ -19,6 +19,10 @@ func (f *DataMatrix) VMTypeString() string {
 	return "DMКод"
 }
 
+func (f *DataMatrix) imagePath() string {
+	return f.path
+}
+
 func (f *DataMatrix) VMRegister() {
 	f.VMRegisterConstructor(func(args VMSlice) error {
 		if len(args) != 3 {
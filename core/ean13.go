@@ -21,6 +21,10 @@ func (f *Ean13) VMTypeString() string {
 	return "EAN13Код"
 }
 
+func (f *Ean13) imagePath() string {
+	return f.path
+}
+
 func isDigitsOnly(s string) bool {
 	for _, char := range s {
 		if !unicode.IsDigit(char) {
@@ -20,6 +20,10 @@ func (f *I2of5) VMTypeString() string {
 	return "I2OF5Код"
 }
 
+func (f *I2of5) imagePath() string {
+	return f.path
+}
+
 func (f *I2of5) VMRegister() {
 	f.VMRegisterConstructor(func(args VMSlice) error {
 		if len(args) != 4 {
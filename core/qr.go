@@ -19,6 +19,10 @@ func (f *QrCode) VMTypeString() string {
 	return "QRКод"
 }
 
+func (f *QrCode) imagePath() string {
+	return f.path
+}
+
 func (f *QrCode) VMRegister() {
 	f.VMRegisterConstructor(func(args VMSlice) error {
 		if len(args) != 3 {
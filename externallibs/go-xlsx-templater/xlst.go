@@ -1,27 +1,37 @@
 package xlst
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/aymerick/raymond"
 	"github.com/tealeg/xlsx"
+	"github.com/xuri/excelize/v2"
 )
 
 var (
 	rgx         = regexp.MustCompile(`\{\{\s*([\p{Latin}\p{Cyrillic}]+)\.[\p{Latin}\p{Cyrillic}]+\s*\}\}`)
-	rangeRgx    = regexp.MustCompile(`\{\{\s*range\s+(\w+)\s*\}\}`)
+	rangeRgx    = regexp.MustCompile(`\{\{\s*range\s+([\p{L}\p{N}_.]+)\s*\}\}`)
+	ifRgx       = regexp.MustCompile(`\{\{\s*(if|unless)\s+([\p{L}\p{N}_.]+)\s*\}\}`)
+	elseRgx     = regexp.MustCompile(`\{\{\s*else\s*\}\}`)
 	rangeEndRgx = regexp.MustCompile(`\{\{\s*end\s*\}\}`)
+	sheetRgx    = regexp.MustCompile(`\{\{\s*sheet\s+([\p{L}\p{N}_.]+)(?:\s+([\p{L}\p{N}_.]+))?\s*\}\}`)
+	imageRgx    = regexp.MustCompile(`^\s*\{\{\s*image\s+([\p{L}\p{N}_.]+)(?:\s+(fit))?\s*\}\}\s*$`)
+	cellRefRgx  = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.$!'])(\$?)([A-Z]{1,3})(\$?)([0-9]+)(?::(\$?)([A-Z]{1,3})(\$?)([0-9]+))?`)
 )
 
 // Xlst Represents template struct
 type Xlst struct {
 	file   *xlsx.File
 	report *xlsx.File
+	images []picture
 }
 
 // Options for render has only one property WrapTextInAllCells for wrapping text
@@ -29,6 +39,48 @@ type Options struct {
 	WrapTextInAllCells bool
 }
 
+// CellError describes a failure while rendering a particular template cell
+type CellError struct {
+	Sheet       string // template sheet name
+	Cell        string // template cell, e.g. "B7"
+	Placeholder string // cell contents that failed to render
+	Err         error
+}
+
+func (e *CellError) Error() string {
+	return fmt.Sprintf("sheet %q, cell %s, placeholder %q: %v", e.Sheet, e.Cell, e.Placeholder, e.Err)
+}
+
+func (e *CellError) Unwrap() error {
+	return e.Err
+}
+
+// picture is an image placed into a report cell after the report is generated
+type picture struct {
+	sheet string
+	cell  string
+	path  string
+	fit   bool
+}
+
+// formulaCell is a rendered cell whose formula must be shifted to the report rows
+type formulaCell struct {
+	cell    *xlsx.Cell
+	formula string
+	tplRow  int
+	outRow  int
+}
+
+// renderer renders template rows of one sheet into one report sheet
+type renderer struct {
+	tplSheet *xlsx.Sheet
+	sheet    *xlsx.Sheet
+	options  *Options
+	rowMap   map[int][]int // template row -> report rows, in ascending order
+	formulas []formulaCell
+	images   []picture
+}
+
 // New creates new Xlst struct and returns pointer to it
 func New() *Xlst {
 	return &Xlst{}
@@ -56,18 +108,34 @@ func (m *Xlst) RenderWithOptions(in interface{}, options *Options) error {
 		options = new(Options)
 	}
 	report := xlsx.NewFile()
+	m.images = nil
 	for si, sheet := range m.file.Sheets {
 		ctx := getCtx(in, si)
-		report.AddSheet(sheet.Name)
-		cloneSheet(sheet, report.Sheets[si])
 
-		err := renderRows(report.Sheets[si], sheet.Rows, ctx, options)
-		if err != nil {
-			return err
+		prop, nameProp := getSheetProp(sheet)
+		if prop == "" {
+			if err := m.renderSheet(report, sheet, sheet.Name, sheet.Rows, 0, ctx, options); err != nil {
+				return err
+			}
+			continue
 		}
 
-		for _, col := range sheet.Cols {
-			report.Sheets[si].Cols = append(report.Sheets[si].Cols, col)
+		// лист размножается по элементам массива
+		items, ok := getRangeCtx(ctx, prop)
+		if !ok {
+			return &CellError{Sheet: sheet.Name, Cell: "A1", Placeholder: sheet.Rows[0].Cells[0].Value,
+				Err: fmt.Errorf("Not expected context property for sheet %q", prop)}
+		}
+		for idx, item := range items {
+			name := fmt.Sprintf("%s %d", sheet.Name, idx+1)
+			if nameProp != "" {
+				if v, ok := lookup(item, nameProp); ok && v != nil {
+					name = fmt.Sprint(v)
+				}
+			}
+			if err := m.renderSheet(report, sheet, name, sheet.Rows[1:], 1, item, options); err != nil {
+				return err
+			}
 		}
 	}
 	m.report = report
@@ -75,6 +143,33 @@ func (m *Xlst) RenderWithOptions(in interface{}, options *Options) error {
 	return nil
 }
 
+func (m *Xlst) renderSheet(report *xlsx.File, sheet *xlsx.Sheet, name string, rows []*xlsx.Row, base int,
+	ctx map[string]interface{}, options *Options,
+) error {
+	out, err := report.AddSheet(name)
+	if err != nil {
+		return &CellError{Sheet: sheet.Name, Cell: "A1", Placeholder: name, Err: err}
+	}
+	cloneSheet(sheet, out)
+
+	r := &renderer{
+		tplSheet: sheet,
+		sheet:    out,
+		options:  options,
+		rowMap:   make(map[int][]int),
+	}
+	if err := r.renderRows(rows, base, ctx); err != nil {
+		return err
+	}
+	r.shiftFormulas()
+	m.images = append(m.images, r.images...)
+
+	for _, col := range sheet.Cols {
+		out.Cols = append(out.Cols, col)
+	}
+	return nil
+}
+
 // ReadTemplate reads template from disk and stores it in a struct
 func (m *Xlst) ReadTemplate(path string) error {
 	file, err := xlsx.OpenFile(path)
@@ -90,7 +185,15 @@ func (m *Xlst) Save(path string) error {
 	if m.report == nil {
 		return errors.New("Report was not generated")
 	}
-	return m.report.Save(path)
+	if len(m.images) == 0 {
+		return m.report.Save(path)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return m.Write(f)
 }
 
 // Write writes generated report to provided writer
@@ -98,58 +201,104 @@ func (m *Xlst) Write(writer io.Writer) error {
 	if m.report == nil {
 		return errors.New("Report was not generated")
 	}
-	return m.report.Write(writer)
+	if len(m.images) == 0 {
+		return m.report.Write(writer)
+	}
+
+	// картинки умеет вставлять только excelize, поэтому отчет перечитывается
+	var buf bytes.Buffer
+	if err := m.report.Write(&buf); err != nil {
+		return err
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for _, img := range m.images {
+		if err := f.AddPicture(img.sheet, img.cell, img.path, &excelize.GraphicOptions{AutoFit: img.fit}); err != nil {
+			return fmt.Errorf("sheet %q, cell %s, image %q: %v", img.sheet, img.cell, img.path, err)
+		}
+	}
+	return f.Write(writer)
 }
 
-func renderRows(sheet *xlsx.Sheet, rows []*xlsx.Row, ctx map[string]interface{}, options *Options) error {
+func (r *renderer) cellError(row, col int, placeholder string, err error) error {
+	return &CellError{
+		Sheet:       r.tplSheet.Name,
+		Cell:        xlsx.GetCellIDStringFromCoords(col, row),
+		Placeholder: placeholder,
+		Err:         err,
+	}
+}
+
+// renderRows renders template rows, base is the index of rows[0] in the template sheet
+func (r *renderer) renderRows(rows []*xlsx.Row, base int, ctx map[string]interface{}) error {
 	for ri := 0; ri < len(rows); ri++ {
 		row := rows[ri]
 
 		rangeProp := getRangeProp(row)
 		if rangeProp != "" {
-			ri++
-
-			rangeEndIndex := getRangeEndIndex(rows[ri:])
-			if rangeEndIndex == -1 {
-				return fmt.Errorf("End of range %q not found", rangeProp)
+			endIndex, _ := getBlockEnd(rows[ri+1:])
+			if endIndex == -1 {
+				return r.cellError(base+ri, 0, row.Cells[0].Value, fmt.Errorf("End of range %q not found", rangeProp))
 			}
+			endIndex += ri + 1
 
-			rangeEndIndex += ri
-
-			rangeCtx := getRangeCtx(ctx, rangeProp)
-			if rangeCtx == nil {
-				return fmt.Errorf("Not expected context property for range %q", rangeProp)
+			rangeCtx, ok := getRangeCtx(ctx, rangeProp)
+			if !ok {
+				return r.cellError(base+ri, 0, row.Cells[0].Value,
+					fmt.Errorf("Not expected context property for range %q", rangeProp))
 			}
 
 			for idx := range rangeCtx {
 				localCtx := mergeCtx(rangeCtx[idx], ctx)
-				err := renderRows(sheet, rows[ri:rangeEndIndex], localCtx, options)
+				err := r.renderRows(rows[ri+1:endIndex], base+ri+1, localCtx)
 				if err != nil {
 					return err
 				}
 			}
 
-			ri = rangeEndIndex
+			ri = endIndex
 
 			continue
 		}
 
-		prop := getListProp(row)
-		if prop == "" {
-			newRow := sheet.AddRow()
-			cloneRow(row, newRow, options)
-			err := renderRow(newRow, ctx)
+		if negate, condProp, ok := getIfProp(row); ok {
+			endIndex, elseIndex := getBlockEnd(rows[ri+1:])
+			if endIndex == -1 {
+				return r.cellError(base+ri, 0, row.Cells[0].Value, fmt.Errorf("End of condition %q not found", condProp))
+			}
+			endIndex += ri + 1
+
+			val, _ := lookup(ctx, condProp)
+			var err error
+			if isTruthy(val) != negate {
+				thenEnd := endIndex
+				if elseIndex != -1 {
+					thenEnd = elseIndex + ri + 1
+				}
+				err = r.renderRows(rows[ri+1:thenEnd], base+ri+1, ctx)
+			} else if elseIndex != -1 {
+				elseIndex += ri + 1
+				err = r.renderRows(rows[elseIndex+1:endIndex], base+elseIndex+1, ctx)
+			}
 			if err != nil {
 				return err
 			}
+
+			ri = endIndex
+
 			continue
 		}
 
-		if !isArray(ctx, prop) {
-			newRow := sheet.AddRow()
-			cloneRow(row, newRow, options)
-			err := renderRow(newRow, ctx)
-			if err != nil {
+		if len(row.Cells) != 0 && (rangeEndRgx.MatchString(row.Cells[0].Value) || elseRgx.MatchString(row.Cells[0].Value)) {
+			return r.cellError(base+ri, 0, row.Cells[0].Value, errors.New("Unexpected end of block"))
+		}
+
+		prop := getListProp(row)
+		if prop == "" || !isArray(ctx, prop) {
+			if err := r.addRow(row, base+ri, ctx); err != nil {
 				return err
 			}
 			continue
@@ -158,11 +307,9 @@ func renderRows(sheet *xlsx.Sheet, rows []*xlsx.Row, ctx map[string]interface{},
 		arr := reflect.ValueOf(ctx[prop])
 		arrBackup := ctx[prop]
 		for i := 0; i < arr.Len(); i++ {
-			newRow := sheet.AddRow()
-			cloneRow(row, newRow, options)
 			ctx[prop] = arr.Index(i).Interface()
-			err := renderRow(newRow, ctx)
-			if err != nil {
+			if err := r.addRow(row, base+ri, ctx); err != nil {
+				ctx[prop] = arrBackup
 				return err
 			}
 		}
@@ -172,6 +319,120 @@ func renderRows(sheet *xlsx.Sheet, rows []*xlsx.Row, ctx map[string]interface{},
 	return nil
 }
 
+// addRow adds a copy of the template row to the report and renders its cells
+func (r *renderer) addRow(row *xlsx.Row, tplRow int, ctx map[string]interface{}) error {
+	newRow := r.sheet.AddRow()
+	outRow := len(r.sheet.Rows) - 1
+	r.rowMap[tplRow] = append(r.rowMap[tplRow], outRow)
+	cloneRow(row, newRow, r.options)
+
+	for ci, cell := range newRow.Cells {
+		if formula := cell.Formula(); formula != "" {
+			r.formulas = append(r.formulas, formulaCell{cell: cell, formula: formula, tplRow: tplRow, outRow: outRow})
+			continue
+		}
+		if match := imageRgx.FindStringSubmatch(cell.Value); match != nil {
+			val, ok := lookup(ctx, match[1])
+			path, isStr := val.(string)
+			if !ok || !isStr || path == "" {
+				return r.cellError(tplRow, ci, cell.Value, fmt.Errorf("Image path %q not found", match[1]))
+			}
+			r.images = append(r.images, picture{
+				sheet: r.sheet.Name,
+				cell:  xlsx.GetCellIDStringFromCoords(ci, outRow),
+				path:  path,
+				fit:   match[2] != "",
+			})
+			cell.Value = ""
+			continue
+		}
+		placeholder := cell.Value
+		if err := renderCell(cell, ctx); err != nil {
+			return r.cellError(tplRow, ci, placeholder, err)
+		}
+	}
+	return nil
+}
+
+// shiftFormulas moves row references in formulas from template rows to report rows.
+// A reference to the row of the formula itself points to the same report row,
+// other references point to the nearest preceding report row produced by the referenced template row,
+// and a range spreads from the first to the last report row of its boundary template rows.
+func (r *renderer) shiftFormulas() {
+	for _, fc := range r.formulas {
+		parts := strings.Split(fc.formula, `"`)
+		for i := 0; i < len(parts); i += 2 {
+			// строковые литералы находятся в нечетных частях и не изменяются
+			parts[i] = r.shiftRefs(parts[i], fc)
+		}
+		formula := strings.Join(parts, `"`)
+		if fc.cell.Type() == xlsx.CellTypeStringFormula {
+			fc.cell.SetStringFormula(formula)
+		} else {
+			fc.cell.SetFormula(formula)
+		}
+	}
+}
+
+func (r *renderer) shiftRefs(s string, fc formulaCell) string {
+	matches := cellRefRgx.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		end := m[1]
+		if end < len(s) && strings.ContainsAny(s[end:end+1], "(!_") {
+			// имя функции или ссылка на другой лист
+			continue
+		}
+		refStart := m[2]
+		b.WriteString(s[last:refStart])
+		if m[10] == -1 {
+			b.WriteString(r.shiftRef(s, m[2:10], fc, 0))
+		} else {
+			b.WriteString(r.shiftRef(s, m[2:10], fc, -1))
+			b.WriteByte(':')
+			b.WriteString(r.shiftRef(s, m[10:18], fc, 1))
+		}
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// shiftRef rewrites one cell reference, bound is -1 for the start of a range, 1 for its end and 0 for a single cell
+func (r *renderer) shiftRef(s string, m []int, fc formulaCell, bound int) string {
+	absCol, col, absRow, row := s[m[0]:m[1]], s[m[2]:m[3]], s[m[4]:m[5]], s[m[6]:m[7]]
+	n, err := strconv.Atoi(row)
+	if err != nil {
+		return absCol + col + absRow + row
+	}
+	tplRow := n - 1
+	outRows := r.rowMap[tplRow]
+	var outRow int
+	switch {
+	case len(outRows) == 0:
+		return "#REF!"
+	case tplRow == fc.tplRow && bound == 0 && absRow == "":
+		outRow = fc.outRow
+	case bound < 0:
+		outRow = outRows[0]
+	case bound > 0:
+		outRow = outRows[len(outRows)-1]
+	default:
+		outRow = outRows[0]
+		for _, o := range outRows {
+			if o > fc.outRow {
+				break
+			}
+			outRow = o
+		}
+	}
+	return absCol + col + absRow + strconv.Itoa(outRow+1)
+}
+
 func cloneCell(from, to *xlsx.Cell, options *Options) {
 	to.Value = from.Value
 	style := from.GetStyle()
@@ -183,6 +444,13 @@ func cloneCell(from, to *xlsx.Cell, options *Options) {
 	to.VMerge = from.VMerge
 	to.Hidden = from.Hidden
 	to.NumFmt = from.NumFmt
+	if formula := from.Formula(); formula != "" {
+		if from.Type() == xlsx.CellTypeStringFormula {
+			to.SetStringFormula(formula)
+		} else {
+			to.SetFormula(formula)
+		}
+	}
 }
 
 func cloneRow(from, to *xlsx.Row, options *Options) {
@@ -241,17 +509,65 @@ func getCtx(in interface{}, i int) map[string]interface{} {
 	return nil
 }
 
-func getRangeCtx(ctx map[string]interface{}, prop string) []map[string]interface{} {
-	val, ok := ctx[prop]
+// getRangeCtx returns contexts for each element of the array prop.
+// Elements that are not maps are available by the name of the array itself.
+func getRangeCtx(ctx map[string]interface{}, prop string) ([]map[string]interface{}, bool) {
+	val, ok := lookup(ctx, prop)
 	if !ok {
-		return nil
+		return nil, false
 	}
 
-	if propCtx, ok := val.([]map[string]interface{}); ok {
-		return propCtx
+	switch propCtx := val.(type) {
+	case []map[string]interface{}:
+		return propCtx, true
+	case []interface{}:
+		res := make([]map[string]interface{}, len(propCtx))
+		for i, v := range propCtx {
+			if m, ok := v.(map[string]interface{}); ok {
+				res[i] = m
+			} else {
+				res[i] = map[string]interface{}{prop: v}
+			}
+		}
+		return res, true
+	case nil:
+		return nil, true
 	}
 
-	return nil
+	return nil, false
+}
+
+// lookup finds a value by a dotted path
+func lookup(ctx map[string]interface{}, path string) (interface{}, bool) {
+	var cur interface{} = ctx
+	for _, p := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[p]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func isTruthy(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len() != 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() != 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() != 0
+	}
+	return true
 }
 
 func mergeCtx(local, global map[string]interface{}) map[string]interface{} {
@@ -270,7 +586,7 @@ func mergeCtx(local, global map[string]interface{}) map[string]interface{} {
 
 func isArray(in map[string]interface{}, prop string) bool {
 	val, ok := in[prop]
-	if !ok {
+	if !ok || val == nil {
 		return false
 	}
 	switch reflect.TypeOf(val).Kind() {
@@ -303,8 +619,35 @@ func getRangeProp(in *xlsx.Row) string {
 	return ""
 }
 
-func getRangeEndIndex(rows []*xlsx.Row) int {
+func getIfProp(in *xlsx.Row) (negate bool, prop string, ok bool) {
+	if len(in.Cells) != 0 {
+		match := ifRgx.FindStringSubmatch(in.Cells[0].Value)
+		if match != nil {
+			return match[1] == "unless", match[2], true
+		}
+	}
+	return false, "", false
+}
+
+func getSheetProp(sheet *xlsx.Sheet) (prop, nameProp string) {
+	if len(sheet.Rows) == 0 || len(sheet.Rows[0].Cells) == 0 {
+		return "", ""
+	}
+	match := sheetRgx.FindStringSubmatch(sheet.Rows[0].Cells[0].Value)
+	if match == nil {
+		return "", ""
+	}
+	return match[1], match[2]
+}
+
+func isBlockStart(in *xlsx.Row) bool {
+	return rangeRgx.MatchString(in.Cells[0].Value) || ifRgx.MatchString(in.Cells[0].Value)
+}
+
+// getBlockEnd returns indexes of the {{end}} and {{else}} rows closing the current block, or -1
+func getBlockEnd(rows []*xlsx.Row) (endIndex, elseIndex int) {
 	var nesting int
+	elseIndex = -1
 	for idx := 0; idx < len(rows); idx++ {
 		if len(rows[idx].Cells) == 0 {
 			continue
@@ -312,27 +655,22 @@ func getRangeEndIndex(rows []*xlsx.Row) int {
 
 		if rangeEndRgx.MatchString(rows[idx].Cells[0].Value) {
 			if nesting == 0 {
-				return idx
+				return idx, elseIndex
 			}
 
 			nesting--
 			continue
 		}
 
-		if rangeRgx.MatchString(rows[idx].Cells[0].Value) {
-			nesting++
+		if nesting == 0 && elseIndex == -1 && elseRgx.MatchString(rows[idx].Cells[0].Value) {
+			elseIndex = idx
+			continue
 		}
-	}
-
-	return -1
-}
 
-func renderRow(in *xlsx.Row, ctx interface{}) error {
-	for _, cell := range in.Cells {
-		err := renderCell(cell, ctx)
-		if err != nil {
-			return err
+		if isBlockStart(rows[idx]) {
+			nesting++
 		}
 	}
-	return nil
+
+	return -1, -1
 }
@@ -0,0 +1,196 @@
+package xlst
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tealeg/xlsx"
+)
+
+type sheetTpl struct {
+	name string
+	rows [][]string
+}
+
+// newTemplate builds a template document, cells starting with "=" become formulas
+func newTemplate(t *testing.T, sheets ...sheetTpl) *Xlst {
+	t.Helper()
+	file := xlsx.NewFile()
+	for _, st := range sheets {
+		sheet, err := file.AddSheet(st.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, cells := range st.rows {
+			row := sheet.AddRow()
+			for _, v := range cells {
+				cell := row.AddCell()
+				if strings.HasPrefix(v, "=") {
+					cell.SetFormula(v[1:])
+				} else {
+					cell.Value = v
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := file.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := NewFromBinary(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// rows returns the report sheet contents, formulas are prefixed with "="
+func rows(sheet *xlsx.Sheet) [][]string {
+	var rv [][]string
+	for _, row := range sheet.Rows {
+		var cells []string
+		for _, cell := range row.Cells {
+			if f := cell.Formula(); f != "" {
+				cells = append(cells, "="+f)
+			} else {
+				cells = append(cells, cell.Value)
+			}
+		}
+		rv = append(rv, cells)
+	}
+	return rv
+}
+
+func groups() map[string]interface{} {
+	return map[string]interface{}{
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name": "Work",
+				"items": []interface{}{
+					map[string]interface{}{"name": "Pen", "quantity": 2},
+					map[string]interface{}{"name": "Pencil", "quantity": 1},
+				},
+			},
+			map[string]interface{}{
+				"name": "Weekend",
+				"items": []interface{}{
+					map[string]interface{}{"name": "Beer", "quantity": 24},
+				},
+			},
+		},
+	}
+}
+
+func TestNestedRanges(t *testing.T) {
+	doc := newTemplate(t, sheetTpl{"Лист", [][]string{
+		{"{{range groups}}"},
+		{"{{name}}"},
+		{"{{items.name}}", "{{items.quantity}}"},
+		{"{{end}}"},
+	}})
+	if err := doc.Render(groups()); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"Work"}, {"Pen", "2"}, {"Pencil", "1"}, {"Weekend"}, {"Beer", "24"}}
+	if got := rows(doc.report.Sheets[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestConditionalRows(t *testing.T) {
+	tpl := [][]string{
+		{"{{if paid}}"},
+		{"оплачено"},
+		{"{{else}}"},
+		{"не оплачено"},
+		{"{{end}}"},
+		{"{{unless items}}"},
+		{"пусто"},
+		{"{{end}}"},
+	}
+	for _, tc := range []struct {
+		ctx  map[string]interface{}
+		want [][]string
+	}{
+		{map[string]interface{}{"paid": true, "items": []interface{}{1}}, [][]string{{"оплачено"}}},
+		{map[string]interface{}{"paid": "", "items": []interface{}{}}, [][]string{{"не оплачено"}, {"пусто"}}},
+	} {
+		doc := newTemplate(t, sheetTpl{"Лист", tpl})
+		if err := doc.Render(tc.ctx); err != nil {
+			t.Fatal(err)
+		}
+		if got := rows(doc.report.Sheets[0]); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v: got %v, want %v", tc.ctx, got, tc.want)
+		}
+	}
+}
+
+func TestSheetPerElement(t *testing.T) {
+	doc := newTemplate(t, sheetTpl{"Группа", [][]string{
+		{"{{sheet groups name}}"},
+		{"{{name}}"},
+	}})
+	if err := doc.Render(groups()); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.report.Sheets) != 2 {
+		t.Fatalf("got %d sheets, want 2", len(doc.report.Sheets))
+	}
+	for i, name := range []string{"Work", "Weekend"} {
+		sheet := doc.report.Sheets[i]
+		if sheet.Name != name {
+			t.Errorf("sheet %d: got name %q, want %q", i, sheet.Name, name)
+		}
+		if got := rows(sheet); !reflect.DeepEqual(got, [][]string{{name}}) {
+			t.Errorf("sheet %q: got %v", name, got)
+		}
+	}
+}
+
+func TestFormulaShift(t *testing.T) {
+	doc := newTemplate(t, sheetTpl{"Лист", [][]string{
+		{"Наименование", "Количество"},
+		{"{{items.name}}", "{{items.quantity}}", "=B2*2"},
+		{"{{if none}}"},
+		{"скрыто"},
+		{"{{end}}"},
+		{"Итого", "=SUM(B2:B2)", "=A4"},
+	}})
+	ctx := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "Pen", "quantity": 2},
+			map[string]interface{}{"name": "Pencil", "quantity": 1},
+		},
+	}
+	if err := doc.Render(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"Наименование", "Количество"},
+		{"Pen", "2", "=B2*2"},
+		{"Pencil", "1", "=B3*2"},
+		{"Итого", "=SUM(B2:B3)", "=#REF!"},
+	}
+	if got := rows(doc.report.Sheets[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCellError(t *testing.T) {
+	doc := newTemplate(t, sheetTpl{"Лист", [][]string{
+		{"Заголовок"},
+		{"{{range groups}}"},
+		{"{{name}}"},
+	}})
+	err := doc.Render(groups())
+	var cellErr *CellError
+	if !errors.As(err, &cellErr) {
+		t.Fatalf("got %v, want *CellError", err)
+	}
+	if cellErr.Sheet != "Лист" || cellErr.Cell != "A2" || cellErr.Placeholder != "{{range groups}}" {
+		t.Errorf("got %+v", cellErr)
+	}
+}
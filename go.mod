@@ -12,6 +12,7 @@ require (
 	github.com/daviddengcn/go-colortext v1.0.0
 	github.com/dchest/siphash v1.2.3
 	github.com/djimenez/iconv-go v0.0.0-20160305225143-8960e66bd3da
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/hashicorp/go-cleanhttp v0.5.2
 	github.com/hashicorp/go-rootcerts v1.0.2
 	github.com/hashicorp/serf v0.9.8
@@ -21,6 +22,8 @@ require (
 	github.com/satori/go.uuid v1.2.0
 	github.com/tealeg/xlsx v1.0.5
 	github.com/xuri/excelize/v2 v2.8.0
+	golang.org/x/image v0.12.0
+	golang.org/x/net v0.14.0
 	modernc.org/sqlite v1.17.3
 	moul.io/number-to-words v0.7.0
 )
@@ -40,9 +43,8 @@ require (
 	github.com/xuri/nfp v0.0.0-20230819163627-dc951e3ffe1a // indirect
 	golang.org/x/crypto v0.12.0 // indirect
 	golang.org/x/mod v0.8.0 // indirect
-	golang.org/x/net v0.14.0 // indirect
 	golang.org/x/sys v0.12.0 // indirect
-	golang.org/x/text v0.12.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
 	golang.org/x/tools v0.6.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	lukechampine.com/uint128 v1.1.1 // indirect
@@ -32,9 +32,12 @@ var (
 	testingMode = fs.Bool("t", false, "Режим вывода отладочной информации")
 	toconsul    = fs.Bool("consul", false, "Зарегистрировать микросервис интерпретатора в Consul")
 	// stackvm     = fs.Bool("stack", false, "Старая стековая виртуальная машина версии 1.8b")
-	v    = fs.Bool("v", false, "Версия программы")
-	w    = fs.Bool("web", false, "Запустить вэб-сервер на порту 5000, если не указан параметр -p")
-	port = fs.String("p", "", "Номер порта вэб-сервера")
+	v       = fs.Bool("v", false, "Версия программы")
+	w       = fs.Bool("web", false, "Запустить вэб-сервер на порту 5000, если не указан параметр -p")
+	port    = fs.String("p", "", "Номер порта вэб-сервера")
+	boltcmd = fs.String("bolt", "", "Обслуживание файловой базы данных: compact, export, import, check, stats")
+	check   = fs.Bool("check", false, "Проверка типов в файлах с исходным кодом без исполнения")
+	typed   = fs.Bool("typed", false, "Проверка типов перед исполнением, операции над целыми числами компилируются в специализированные инструкции")
 
 	istty = isatty.IsTerminal(os.Stdout.Fd())
 
@@ -58,6 +61,26 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *boltcmd != "" {
+		if err := runBolt(*boltcmd, fs.Args()); err != nil {
+			colortext(ct.Red, false, func() {
+				fmt.Fprintln(os.Stderr, err)
+			})
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	if *check {
+		if err := runCheck(fs.Args()); err != nil {
+			colortext(ct.Red, false, func() {
+				fmt.Fprintln(os.Stderr, err)
+			})
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	var (
 		code      string
 		b         []byte
@@ -175,7 +198,16 @@ func main() {
 				log.Printf("--Выполняется код--\n%s\n", code)
 			}
 			// замер производительности
-			_, bins, err = bincode.ParseSrc(code)
+			if *typed {
+				var typeErrs []error
+				_, bins, typeErrs, err = bincode.ParseSrcChecked(code)
+				if err == nil && len(typeErrs) > 0 {
+					printTypeErrors(source, typeErrs)
+					err = fmt.Errorf("Найдено ошибок типов: %d", len(typeErrs))
+				}
+			} else {
+				_, bins, err = bincode.ParseSrc(code)
+			}
 			tsParse = time.Since(tstart)
 
 			if *testingMode {
@@ -274,6 +306,7 @@ func main() {
 			if interactive {
 				continue
 			} else {
+				env.Destroy()
 				os.Exit(1)
 			}
 		} else {
@@ -295,6 +328,8 @@ func main() {
 			}
 		}
 	}
+	// отменяет незавершенные транзакции и освобождает другие ресурсы окружения
+	env.Destroy()
 }
 
 // Run запускает микросервис интерпретатора на порту
@@ -327,3 +362,125 @@ func Run(port string, ext string) {
 		log.Println(err)
 	}
 }
+
+// runCheck проверяет типы в файлах с исходным кодом без их исполнения:
+//
+//	gonec -check модуль.gnc [другой.gnc ...]
+func runCheck(files []string) error {
+	if len(files) == 0 {
+		return fmt.Errorf("Не указано имя файла с исходным кодом на языке Гонец")
+	}
+	count := 0
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		_, _, typeErrs, err := bincode.ParseSrcChecked(string(b))
+		if err != nil {
+			if e, ok := err.(*parser.Error); ok {
+				return fmt.Errorf("%s:%d:%d %s", f, e.Pos.Line, e.Pos.Column, err)
+			}
+			return fmt.Errorf("%s: %s", f, err)
+		}
+		printTypeErrors(f, typeErrs)
+		count += len(typeErrs)
+	}
+	if count > 0 {
+		return fmt.Errorf("Найдено ошибок типов: %d", count)
+	}
+	return nil
+}
+
+func printTypeErrors(source string, errs []error) {
+	colortext(ct.Red, false, func() {
+		for _, err := range errs {
+			if e, ok := err.(*binstmt.Error); ok {
+				// учитываем вставку модуля _ по умолчанию
+				fmt.Fprintf(os.Stderr, "%s:%d:%d %s\n", source, e.Pos.Line-1, e.Pos.Column, e.Message)
+			} else {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+	})
+}
+
+// runBolt выполняет команду обслуживания файловой базы данных:
+//
+//	gonec -bolt compact исходная.db новая.db
+//	gonec -bolt export база.db [выгрузка.jsonl]
+//	gonec -bolt import база.db выгрузка.jsonl
+//	gonec -bolt check база.db
+//	gonec -bolt stats база.db
+func runBolt(cmd string, args []string) error {
+	need := map[string]int{"compact": 2, "export": 1, "import": 2, "check": 1, "stats": 1}
+	n, ok := need[cmd]
+	if !ok {
+		return fmt.Errorf("Неизвестная команда обслуживания базы данных: %s", cmd)
+	}
+	if len(args) < n {
+		return fmt.Errorf("Недостаточно параметров для команды %s", cmd)
+	}
+	readonly := cmd != "import"
+	if readonly {
+		// bolt создал бы пустой файл вместо отсутствующего
+		if _, err := os.Stat(args[0]); err != nil {
+			return err
+		}
+	}
+	db, err := core.BoltOpen(args[0], readonly)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch cmd {
+	case "compact":
+		if err := core.BoltCompact(db, args[1]); err != nil {
+			return err
+		}
+		src, _ := os.Stat(args[0])
+		dst, _ := os.Stat(args[1])
+		fmt.Printf("%s: %d байт -> %s: %d байт\n", args[0], src.Size(), args[1], dst.Size())
+	case "export":
+		out := os.Stdout
+		if len(args) > 1 {
+			f, err := os.Create(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		return core.BoltExport(db, out)
+	case "import":
+		f, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return core.BoltImport(db, f)
+	case "check":
+		errs, err := core.BoltCheck(db)
+		if err != nil {
+			return err
+		}
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("Найдено ошибок целостности: %d", len(errs))
+		}
+		fmt.Println("Ошибок не найдено")
+	case "stats":
+		st, err := core.BoltStats(db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-30s %10s %8s %8s %14s %14s\n", "Таблица", "Записей", "Таблиц", "Индексов", "Данных, байт", "Выделено, байт")
+		for _, s := range st {
+			fmt.Printf("%-30s %10d %8d %8d %14d %14d\n", s.Table, s.Keys, s.Tables, s.Indexes, s.Data, s.Alloc)
+		}
+	}
+	return nil
+}
@@ -2,7 +2,11 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/shinanca/gonec/bincode"
@@ -10,6 +14,28 @@ import (
 	"github.com/shinanca/gonec/parser"
 )
 
+func TestRunCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gonec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	good := filepath.Join(dir, "check")
+	bad := filepath.Join(dir, "bad.gnc")
+	ioutil.WriteFile(good, []byte("Перем х: Строка = \"x\"\n"), 0644)
+	ioutil.WriteFile(bad, []byte("Перем х: Число = \"x\"\n"), 0644)
+
+	if err := runCheck([]string{good}); err != nil {
+		t.Errorf("файл без ошибок: %v", err)
+	}
+	if err := runCheck([]string{good, bad}); err == nil || !strings.Contains(err.Error(), "Найдено ошибок типов: 1") {
+		t.Errorf("файл с ошибкой: %v", err)
+	}
+	if err := runCheck(nil); err == nil {
+		t.Errorf("без файлов должна быть ошибка")
+	}
+}
+
 func TestRun(t *testing.T) {
 	env := core.NewEnv()
 
@@ -44,10 +44,17 @@ type Scanner struct {
 
 // opName is correction of operation names.
 var opName = map[string]int{
-	"функция": FUNC,
-	"возврат": RETURN,
-	// "перем":             VAR,
+	"функция":           FUNC,
+	"процедура":         FUNC,
+	"экспорт":           EXPORT,
+	"знач":              BYVAL,
+	"перем":             VAR,
+	"класс":             CLASS,
+	"интерфейс":         INTERFACE,
+	"реализует":         IMPLEMENTS,
+	"возврат":           RETURN,
 	"вызватьисключение": THROW,
+	"выдать":            YIELD,
 	"если":              IF,
 	"для":               FOR,
 	"прервать":          BREAK,
@@ -70,21 +77,24 @@ var opName = map[string]int{
 	"канал":       CHAN,
 	"новый":       MAKE,
 
-	"или":          OROR,
-	"и":            ANDAND,
-	"не":           int('!'),
-	"конеццикла":   int('}'),
-	"конецесли":    int('}'),
-	"конецфункции": int('}'),
-	"конецпопытки": int('}'),
-	"конецвыбора":  int('}'),
-	"тогда":        int('{'),
-	"цикл":         int('{'),
-	"null":         NULL,
-	"каждого":      EACH,
-	"по":           TO,
-	"пока":         WHILE,
-	"иначеесли":    ELSIF,
+	"или":             OROR,
+	"и":               ANDAND,
+	"не":              int('!'),
+	"конеццикла":      int('}'),
+	"конецесли":       int('}'),
+	"конецфункции":    int('}'),
+	"конецпроцедуры":  int('}'),
+	"конецкласса":     int('}'),
+	"конецинтерфейса": int('}'),
+	"конецпопытки":    int('}'),
+	"конецвыбора":     int('}'),
+	"тогда":           int('{'),
+	"цикл":            int('{'),
+	"null":            NULL,
+	"каждого":         EACH,
+	"по":              TO,
+	"пока":            WHILE,
+	"иначеесли":       ELSIF,
 
 	"строка":       TYPECAST,
 	"число":        TYPECAST,
@@ -94,11 +104,15 @@ var opName = map[string]int{
 	"структура":    TYPECAST,
 	"дата":         TYPECAST,
 	"длительность": TYPECAST,
+
+	"двоичныеданные": TYPECAST,
+	"соответствие":   TYPECAST,
 }
 
 var opCanEqual = map[int]bool{
 	RETURN: true,
 	THROW:  true,
+	YIELD:  true,
 	IF:     true,
 	// FOR:      true,
 	IN: true,
@@ -155,7 +169,7 @@ retry:
 					s.afterNew = false
 				} else {
 					s.typecast = true
-					s.castType = lowlit
+					s.castType = lit
 					pos = s.pos()
 				}
 			case MAKE:
@@ -12,31 +12,35 @@ import (
 	"github.com/shinanca/gonec/names"
 )
 
-//line parser.y:30
+//line parser.y:46
 type yySymType struct {
-	yys          int
-	compstmt     ast.Stmts
-	modules      ast.Stmts
-	module       ast.Stmt
-	stmt_if      ast.Stmt
-	stmt_default ast.Stmt
-	stmt_elsif   ast.Stmt
-	stmt_elsifs  ast.Stmts
-	stmt_case    ast.Stmt
-	stmt_cases   ast.Stmts
-	stmts        ast.Stmts
-	stmt         ast.Stmt
-	typ          ast.Type
-	expr         ast.Expr
-	exprs        []ast.Expr
-	expr_many    []ast.Expr
-	expr_pair    ast.Expr
-	expr_pairs   []ast.Expr
-	expr_idents  []int
-	tok          ast.Token
-	term         ast.Token
-	terms        ast.Token
-	opt_terms    ast.Token
+	yys           int
+	compstmt      ast.Stmts
+	modules       ast.Stmts
+	module        ast.Stmt
+	stmt_if       ast.Stmt
+	stmt_default  ast.Stmt
+	stmt_elsif    ast.Stmt
+	stmt_elsifs   ast.Stmts
+	stmt_case     ast.Stmt
+	stmt_cases    ast.Stmts
+	stmts         ast.Stmts
+	stmt          ast.Stmt
+	typ           ast.Type
+	expr          ast.Expr
+	exprs         []ast.Expr
+	expr_many     []ast.Expr
+	expr_pair     ast.Expr
+	expr_pairs    []ast.Expr
+	func_param    *ast.FuncParam
+	func_params   []*ast.FuncParam
+	class_member  *ast.ClassMember
+	class_members []*ast.ClassMember
+	idents        []int
+	tok           ast.Token
+	term          ast.Token
+	terms         ast.Token
+	opt_terms     ast.Token
 }
 
 const IDENT = 57346
@@ -92,7 +96,14 @@ const ELSIF = 57395
 const WHILE = 57396
 const TERNARY = 57397
 const TYPECAST = 57398
-const UNARY = 57399
+const EXPORT = 57399
+const BYVAL = 57400
+const CLASS = 57401
+const INTERFACE = 57402
+const IMPLEMENTS = 57403
+const VAR = 57404
+const YIELD = 57405
+const UNARY = 57406
 
 var yyToknames = [...]string{
 	"$end",
@@ -151,6 +162,13 @@ var yyToknames = [...]string{
 	"WHILE",
 	"TERNARY",
 	"TYPECAST",
+	"EXPORT",
+	"BYVAL",
+	"CLASS",
+	"INTERFACE",
+	"IMPLEMENTS",
+	"VAR",
+	"YIELD",
 	"'='",
 	"'?'",
 	"':'",
@@ -165,11 +183,11 @@ var yyToknames = [...]string{
 	"UNARY",
 	"'{'",
 	"'}'",
+	"'('",
+	"')'",
 	"'.'",
 	"'!'",
 	"'^'",
-	"')'",
-	"'('",
 	"'['",
 	"']'",
 	"'|'",
@@ -184,7 +202,7 @@ const yyEofCode = 1
 const yyErrCode = 2
 const yyInitialStackSize = 16
 
-//line parser.y:743
+//line parser.y:1029
 
 //line yacctab:1
 var yyExca = [...]int16{
@@ -194,671 +212,777 @@ var yyExca = [...]int16{
 	-1, 6,
 	1, 7,
 	25, 7,
-	-2, 128,
+	-2, 175,
 	-1, 12,
-	60, 50,
+	67, 97,
 	-2, 5,
 	-1, 16,
-	60, 51,
-	-2, 25,
-	-1, 25,
+	67, 98,
+	-2, 34,
+	-1, 26,
 	27, 7,
-	-2, 128,
-	-1, 50,
-	60, 50,
-	-2, 129,
-	-1, 127,
+	-2, 175,
+	-1, 54,
+	67, 97,
+	-2, 176,
+	-1, 136,
 	16, 0,
 	17, 0,
-	-2, 83,
-	-1, 128,
+	-2, 130,
+	-1, 137,
 	16, 0,
 	17, 0,
-	-2, 84,
-	-1, 148,
-	60, 51,
-	-2, 45,
-	-1, 154,
-	70, 7,
-	-2, 128,
-	-1, 155,
-	70, 7,
-	-2, 128,
-	-1, 180,
+	-2, 131,
+	-1, 160,
+	67, 98,
+	-2, 92,
+	-1, 166,
+	77, 7,
+	-2, 175,
+	-1, 167,
+	77, 7,
+	-2, 175,
+	-1, 205,
 	13, 7,
 	53, 7,
-	70, 7,
-	-2, 128,
-	-1, 226,
+	77, 7,
+	-2, 175,
+	-1, 274,
 	16, 0,
-	60, 52,
-	-2, 46,
-	-1, 227,
-	1, 47,
-	13, 47,
-	16, 47,
-	25, 47,
-	27, 47,
-	43, 47,
-	44, 47,
-	53, 47,
-	57, 47,
-	60, 53,
-	70, 47,
-	80, 47,
-	81, 47,
-	-2, 54,
-	-1, 234,
-	1, 53,
-	8, 53,
-	13, 53,
-	25, 53,
-	27, 53,
-	43, 53,
-	44, 53,
-	53, 53,
-	60, 53,
-	70, 53,
-	74, 53,
-	77, 53,
-	80, 53,
-	81, 53,
-	-2, 54,
-	-1, 249,
-	70, 7,
-	-2, 128,
-	-1, 259,
-	1, 104,
-	8, 104,
-	13, 104,
-	25, 104,
-	27, 104,
-	43, 104,
-	44, 104,
-	52, 104,
-	53, 104,
-	57, 104,
-	59, 104,
-	60, 104,
-	69, 104,
-	70, 104,
-	74, 104,
-	77, 104,
-	80, 104,
-	81, 104,
-	-2, 102,
-	-1, 261,
-	1, 108,
-	8, 108,
-	13, 108,
-	25, 108,
-	27, 108,
-	43, 108,
-	44, 108,
-	52, 108,
-	53, 108,
-	57, 108,
-	59, 108,
-	60, 108,
-	69, 108,
-	70, 108,
-	74, 108,
-	77, 108,
-	80, 108,
-	81, 108,
-	-2, 106,
-	-1, 269,
-	70, 7,
-	-2, 128,
-	-1, 273,
+	67, 99,
+	-2, 93,
+	-1, 275,
+	1, 94,
+	13, 94,
+	16, 94,
+	25, 94,
+	27, 94,
+	43, 94,
+	44, 94,
+	53, 94,
+	64, 94,
+	67, 100,
+	77, 94,
+	87, 94,
+	88, 94,
+	-2, 101,
+	-1, 282,
+	1, 100,
+	13, 100,
+	25, 100,
+	27, 100,
+	43, 100,
+	44, 100,
+	53, 100,
+	67, 100,
+	77, 100,
+	79, 100,
+	84, 100,
+	87, 100,
+	88, 100,
+	-2, 101,
+	-1, 324,
+	1, 150,
+	8, 150,
+	12, 150,
+	13, 150,
+	25, 150,
+	27, 150,
+	43, 150,
+	44, 150,
+	52, 150,
+	53, 150,
+	64, 150,
+	66, 150,
+	67, 150,
+	76, 150,
+	77, 150,
+	79, 150,
+	84, 150,
+	87, 150,
+	88, 150,
+	-2, 148,
+	-1, 325,
+	1, 151,
+	8, 151,
+	12, 151,
+	13, 151,
+	25, 151,
+	27, 151,
+	43, 151,
+	44, 151,
+	52, 151,
+	53, 151,
+	64, 151,
+	66, 151,
+	67, 151,
+	76, 151,
+	77, 151,
+	79, 151,
+	84, 151,
+	87, 151,
+	88, 151,
+	-2, 149,
+	-1, 326,
+	1, 154,
+	8, 154,
+	12, 154,
+	13, 154,
+	25, 154,
+	27, 154,
+	43, 154,
+	44, 154,
+	52, 154,
+	53, 154,
+	64, 154,
+	66, 154,
+	67, 154,
+	76, 154,
+	77, 154,
+	79, 154,
+	84, 154,
+	87, 154,
+	88, 154,
+	-2, 152,
+	-1, 327,
+	1, 155,
+	8, 155,
+	12, 155,
+	13, 155,
+	25, 155,
+	27, 155,
+	43, 155,
+	44, 155,
+	52, 155,
+	53, 155,
+	64, 155,
+	66, 155,
+	67, 155,
+	76, 155,
+	77, 155,
+	79, 155,
+	84, 155,
+	87, 155,
+	88, 155,
+	-2, 153,
+	-1, 336,
+	77, 7,
+	-2, 175,
+	-1, 344,
 	43, 7,
 	44, 7,
-	70, 7,
-	-2, 128,
-	-1, 278,
-	70, 7,
-	-2, 128,
-	-1, 279,
-	70, 7,
-	-2, 128,
-	-1, 284,
-	1, 103,
-	8, 103,
-	13, 103,
-	25, 103,
-	27, 103,
-	43, 103,
-	44, 103,
-	52, 103,
-	53, 103,
-	57, 103,
-	59, 103,
-	60, 103,
-	69, 103,
-	70, 103,
-	74, 103,
-	77, 103,
-	80, 103,
-	81, 103,
-	-2, 101,
-	-1, 285,
-	1, 107,
-	8, 107,
-	13, 107,
-	25, 107,
-	27, 107,
-	43, 107,
-	44, 107,
-	52, 107,
-	53, 107,
-	57, 107,
-	59, 107,
-	60, 107,
-	69, 107,
-	70, 107,
-	74, 107,
-	77, 107,
-	80, 107,
-	81, 107,
-	-2, 105,
-	-1, 289,
-	70, 7,
-	-2, 128,
-	-1, 293,
-	70, 7,
-	-2, 128,
-	-1, 294,
-	70, 7,
-	-2, 128,
-	-1, 295,
+	77, 7,
+	-2, 175,
+	-1, 355,
+	77, 7,
+	-2, 175,
+	-1, 367,
+	77, 7,
+	-2, 175,
+	-1, 371,
+	77, 7,
+	-2, 175,
+	-1, 372,
+	77, 7,
+	-2, 175,
+	-1, 387,
+	77, 7,
+	-2, 175,
+	-1, 396,
 	43, 7,
 	44, 7,
-	70, 7,
-	-2, 128,
-	-1, 301,
-	70, 7,
-	-2, 128,
-	-1, 312,
+	77, 7,
+	-2, 175,
+	-1, 402,
+	77, 7,
+	-2, 175,
+	-1, 405,
 	13, 7,
 	53, 7,
-	70, 7,
-	-2, 128,
+	77, 7,
+	-2, 175,
+	-1, 414,
+	77, 7,
+	-2, 175,
+	-1, 420,
+	77, 7,
+	-2, 175,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 3073
+const yyLast = 3637
 
 var yyAct = [...]int16{
-	86, 168, 163, 10, 157, 194, 195, 8, 9, 94,
-	95, 173, 254, 16, 213, 178, 211, 175, 47, 17,
-	171, 95, 87, 260, 173, 90, 150, 92, 174, 91,
-	96, 97, 98, 165, 8, 9, 8, 9, 99, 85,
-	262, 285, 104, 106, 110, 258, 101, 113, 111, 115,
-	284, 16, 280, 117, 200, 119, 120, 121, 122, 123,
-	124, 125, 126, 127, 128, 129, 130, 131, 132, 133,
-	134, 135, 136, 137, 138, 150, 250, 139, 140, 141,
-	142, 181, 144, 146, 148, 148, 108, 206, 12, 261,
-	68, 69, 70, 71, 72, 73, 160, 150, 243, 143,
-	59, 251, 49, 147, 149, 206, 150, 229, 169, 82,
-	176, 259, 177, 159, 315, 109, 289, 100, 314, 207,
-	201, 166, 196, 197, 196, 197, 313, 311, 102, 103,
-	309, 89, 53, 150, 308, 304, 80, 81, 298, 76,
-	78, 256, 239, 150, 238, 114, 185, 182, 242, 240,
-	215, 193, 155, 188, 189, 153, 291, 84, 190, 191,
-	7, 192, 204, 205, 198, 199, 15, 11, 209, 107,
-	3, 187, 283, 290, 252, 51, 220, 208, 88, 225,
-	226, 169, 158, 276, 228, 230, 14, 233, 235, 218,
-	216, 217, 6, 219, 196, 197, 152, 241, 83, 210,
-	50, 164, 151, 179, 244, 118, 110, 5, 2, 167,
-	4, 51, 267, 288, 22, 112, 257, 116, 13, 1,
-	0, 0, 0, 264, 0, 265, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 270, 271, 186,
-	0, 0, 0, 0, 0, 158, 0, 0, 275, 0,
-	0, 0, 0, 277, 233, 212, 214, 282, 0, 0,
+	155, 388, 185, 311, 193, 310, 116, 290, 237, 187,
+	173, 154, 10, 16, 169, 228, 221, 222, 188, 152,
+	51, 90, 91, 92, 14, 153, 95, 320, 97, 203,
+	6, 198, 230, 198, 105, 106, 107, 7, 54, 96,
+	103, 89, 108, 411, 11, 104, 113, 115, 238, 101,
+	360, 122, 55, 124, 326, 16, 121, 126, 324, 128,
+	129, 130, 131, 132, 133, 134, 135, 136, 137, 138,
+	139, 140, 141, 142, 143, 144, 145, 146, 147, 17,
+	312, 148, 149, 150, 151, 229, 156, 158, 160, 160,
+	8, 9, 55, 298, 199, 12, 198, 243, 72, 73,
+	74, 75, 76, 77, 259, 182, 178, 172, 63, 53,
+	8, 9, 90, 8, 9, 257, 249, 86, 249, 201,
+	206, 202, 351, 180, 8, 9, 8, 9, 398, 181,
+	397, 194, 191, 8, 9, 8, 9, 110, 208, 111,
+	112, 60, 61, 62, 162, 196, 347, 84, 249, 57,
+	104, 249, 85, 200, 80, 82, 328, 230, 212, 423,
+	317, 119, 208, 250, 190, 215, 216, 159, 161, 120,
+	421, 418, 223, 224, 412, 407, 406, 239, 240, 217,
+	218, 208, 404, 219, 247, 248, 241, 225, 226, 400,
+	178, 230, 208, 255, 170, 171, 175, 207, 234, 236,
+	90, 268, 363, 117, 273, 274, 288, 399, 254, 327,
+	229, 109, 278, 393, 281, 283, 262, 264, 276, 204,
+	267, 207, 263, 265, 291, 348, 367, 72, 73, 74,
+	75, 76, 77, 325, 318, 118, 381, 63, 322, 301,
+	207, 299, 297, 287, 229, 302, 86, 305, 294, 304,
+	300, 207, 244, 313, 314, 286, 341, 251, 213, 227,
+	178, 162, 323, 209, 170, 179, 369, 223, 224, 233,
+	235, 330, 55, 331, 55, 123, 84, 179, 57, 384,
+	373, 85, 293, 80, 82, 337, 338, 258, 260, 334,
+	368, 261, 179, 389, 223, 224, 346, 102, 167, 343,
+	214, 220, 3, 277, 316, 256, 194, 15, 253, 353,
+	177, 340, 165, 88, 238, 179, 357, 358, 356, 354,
+	281, 186, 234, 359, 119, 94, 362, 296, 382, 119,
+	303, 295, 102, 189, 266, 232, 163, 127, 100, 99,
+	5, 291, 375, 376, 345, 309, 378, 379, 374, 370,
+	380, 189, 315, 319, 383, 321, 252, 377, 176, 189,
+	164, 87, 125, 2, 174, 4, 390, 231, 386, 192,
+	392, 339, 93, 333, 366, 189, 242, 289, 23, 13,
+	391, 1, 0, 0, 394, 395, 0, 0, 178, 344,
+	0, 0, 403, 349, 350, 0, 0, 0, 0, 0,
+	401, 0, 0, 409, 410, 0, 355, 0, 0, 408,
+	0, 416, 0, 0, 0, 413, 0, 417, 415, 0,
+	0, 0, 0, 0, 0, 0, 0, 419, 0, 0,
+	0, 0, 0, 422, 0, 0, 31, 32, 36, 0,
+	0, 42, 20, 21, 52, 0, 24, 0, 0, 0,
+	0, 0, 387, 0, 37, 38, 39, 0, 26, 0,
+	0, 0, 0, 0, 0, 0, 0, 18, 19, 396,
+	0, 0, 0, 0, 27, 0, 0, 46, 0, 47,
+	50, 48, 40, 0, 402, 0, 25, 41, 49, 0,
+	0, 28, 29, 0, 30, 22, 0, 0, 0, 414,
+	0, 0, 0, 33, 0, 0, 0, 0, 44, 0,
+	45, 0, 420, 34, 35, 43, 0, 0, 0, 8,
+	9, 66, 67, 69, 71, 81, 83, 0, 0, 0,
+	0, 0, 0, 0, 72, 73, 74, 75, 76, 77,
+	0, 0, 78, 79, 63, 64, 65, 0, 0, 0,
+	0, 0, 0, 86, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 272, 68, 70, 58, 59, 60, 61, 62,
+	0, 0, 0, 84, 271, 57, 0, 0, 85, 0,
+	80, 82, 66, 67, 69, 71, 81, 83, 0, 0,
+	0, 0, 0, 0, 0, 72, 73, 74, 75, 76,
+	77, 0, 0, 78, 79, 63, 64, 65, 0, 0,
+	0, 0, 0, 0, 86, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 270, 68, 70, 58, 59, 60, 61,
+	62, 0, 0, 0, 84, 269, 57, 0, 0, 85,
+	0, 80, 82, 66, 67, 69, 71, 81, 83, 0,
+	0, 0, 0, 0, 0, 0, 72, 73, 74, 75,
+	76, 77, 0, 0, 78, 79, 63, 64, 65, 0,
+	0, 0, 0, 0, 0, 86, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 246, 0, 68, 70, 58, 59, 60,
+	61, 62, 0, 0, 0, 84, 0, 57, 0, 0,
+	85, 245, 80, 82, 66, 67, 69, 71, 81, 83,
+	0, 0, 0, 0, 0, 0, 0, 72, 73, 74,
+	75, 76, 77, 0, 0, 78, 79, 63, 64, 65,
+	0, 0, 0, 0, 0, 0, 86, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 211, 0, 68, 70, 58, 59,
+	60, 61, 62, 0, 0, 0, 84, 0, 57, 0,
+	0, 85, 210, 80, 82, 66, 67, 69, 71, 81,
+	83, 0, 0, 0, 0, 0, 0, 0, 72, 73,
+	74, 75, 76, 77, 0, 0, 78, 79, 63, 64,
+	65, 0, 0, 0, 0, 0, 0, 86, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 68, 70, 58,
+	59, 60, 61, 62, 0, 405, 0, 84, 0, 57,
+	0, 0, 85, 0, 80, 82, 66, 67, 69, 71,
+	81, 83, 0, 0, 0, 0, 0, 0, 0, 72,
+	73, 74, 75, 76, 77, 0, 0, 78, 79, 63,
+	64, 65, 0, 0, 0, 0, 0, 0, 86, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 68, 70,
+	58, 59, 60, 61, 62, 0, 0, 0, 84, 385,
+	57, 0, 0, 85, 0, 80, 82, 66, 67, 69,
+	71, 81, 83, 0, 0, 0, 0, 0, 0, 0,
+	72, 73, 74, 75, 76, 77, 0, 0, 78, 79,
+	63, 64, 65, 0, 0, 0, 0, 0, 0, 86,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 68,
+	70, 58, 59, 60, 61, 62, 0, 372, 0, 84,
+	0, 57, 0, 0, 85, 0, 80, 82, 66, 67,
+	69, 71, 81, 83, 0, 0, 0, 0, 0, 0,
+	0, 72, 73, 74, 75, 76, 77, 0, 0, 78,
+	79, 63, 64, 65, 0, 0, 0, 0, 0, 0,
+	86, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	68, 70, 58, 59, 60, 61, 62, 0, 371, 0,
+	84, 0, 57, 0, 0, 85, 0, 80, 82, 66,
+	67, 69, 71, 81, 83, 0, 0, 0, 0, 0,
+	0, 0, 72, 73, 74, 75, 76, 77, 0, 0,
+	78, 79, 63, 64, 65, 0, 0, 0, 0, 0,
+	0, 86, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 68, 70, 58, 59, 60, 61, 62, 0, 0,
+	0, 84, 365, 57, 0, 0, 85, 0, 80, 82,
+	66, 67, 69, 71, 81, 83, 0, 0, 0, 0,
+	0, 0, 0, 72, 73, 74, 75, 76, 77, 0,
+	0, 78, 79, 63, 64, 65, 0, 0, 0, 0,
+	0, 0, 86, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 68, 70, 58, 59, 60, 61, 62, 0,
+	0, 0, 84, 364, 57, 0, 0, 85, 0, 80,
+	82, 66, 67, 69, 71, 81, 83, 0, 0, 0,
+	0, 0, 0, 0, 72, 73, 74, 75, 76, 77,
+	0, 0, 78, 79, 63, 64, 65, 0, 0, 0,
+	0, 0, 0, 86, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 68, 70, 58, 59, 60, 61, 62,
+	0, 0, 0, 84, 0, 57, 0, 0, 85, 352,
+	80, 82, 66, 67, 69, 71, 81, 83, 0, 0,
+	0, 0, 0, 0, 0, 72, 73, 74, 75, 76,
+	77, 0, 0, 78, 79, 63, 64, 65, 0, 0,
+	0, 0, 0, 0, 86, 0, 0, 0, 342, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 68, 70, 58, 59, 60, 61,
+	62, 0, 0, 0, 84, 0, 57, 0, 0, 85,
+	0, 80, 82, 66, 67, 69, 71, 81, 83, 0,
+	0, 0, 0, 0, 0, 0, 72, 73, 74, 75,
+	76, 77, 0, 0, 78, 79, 63, 64, 65, 0,
+	0, 0, 0, 0, 0, 86, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 68, 70, 58, 59, 60,
+	61, 62, 0, 336, 0, 84, 0, 57, 0, 0,
+	85, 0, 80, 82, 66, 67, 69, 71, 81, 83,
+	0, 0, 0, 0, 0, 0, 0, 72, 73, 74,
+	75, 76, 77, 0, 0, 78, 79, 63, 64, 65,
+	0, 0, 0, 0, 0, 0, 86, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 68, 70, 58, 59,
+	60, 61, 62, 0, 0, 0, 84, 0, 57, 0,
+	0, 85, 335, 80, 82, 66, 67, 69, 71, 81,
+	83, 0, 0, 0, 0, 0, 0, 0, 72, 73,
+	74, 75, 76, 77, 0, 0, 78, 79, 63, 64,
+	65, 0, 0, 0, 0, 0, 0, 86, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 68, 70, 58,
+	59, 60, 61, 62, 0, 0, 0, 84, 332, 57,
+	0, 0, 85, 0, 80, 82, 66, 67, 69, 71,
+	81, 83, 0, 0, 0, 0, 0, 0, 0, 72,
+	73, 74, 75, 76, 77, 0, 0, 78, 79, 63,
+	64, 65, 0, 0, 0, 0, 0, 0, 86, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 68, 70,
+	58, 59, 60, 61, 62, 0, 0, 0, 84, 329,
+	57, 0, 0, 85, 0, 80, 82, 66, 67, 69,
+	71, 81, 83, 0, 0, 0, 0, 0, 0, 0,
+	72, 73, 74, 75, 76, 77, 0, 0, 78, 79,
+	63, 64, 65, 0, 0, 0, 0, 0, 0, 86,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 308, 68,
+	70, 58, 59, 60, 61, 62, 0, 0, 0, 84,
+	0, 57, 0, 0, 85, 0, 80, 82, 66, 67,
+	69, 71, 81, 83, 0, 0, 0, 0, 0, 0,
+	0, 72, 73, 74, 75, 76, 77, 0, 0, 78,
+	79, 63, 64, 65, 0, 0, 0, 0, 0, 0,
+	86, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	68, 70, 58, 59, 60, 61, 62, 0, 0, 0,
+	84, 0, 57, 0, 0, 85, 307, 80, 82, 66,
+	67, 69, 71, 81, 83, 0, 0, 0, 0, 0,
+	0, 0, 72, 73, 74, 75, 76, 77, 0, 0,
+	78, 79, 63, 64, 65, 0, 0, 0, 0, 0,
+	0, 86, 0, 0, 0, 285, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 68, 70, 58, 59, 60, 61, 62, 0, 0,
+	0, 84, 0, 57, 0, 0, 85, 0, 80, 82,
+	66, 67, 69, 71, 81, 83, 0, 0, 0, 0,
+	0, 0, 0, 72, 73, 74, 75, 76, 77, 0,
+	0, 78, 79, 63, 64, 65, 0, 0, 0, 0,
+	0, 0, 86, 0, 0, 0, 284, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 68, 70, 58, 59, 60, 61, 62, 0,
+	0, 0, 84, 0, 57, 0, 0, 85, 0, 80,
+	82, 66, 67, 69, 71, 81, 83, 0, 0, 0,
+	0, 0, 0, 0, 72, 73, 74, 75, 76, 77,
+	0, 0, 78, 79, 63, 64, 65, 0, 0, 0,
+	0, 0, 0, 86, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 68, 70, 58, 59, 60, 61, 62,
+	0, 0, 0, 84, 0, 57, 0, 0, 85, 280,
+	80, 82, 66, 67, 69, 71, 81, 83, 0, 0,
+	0, 0, 0, 0, 0, 72, 73, 74, 75, 76,
+	77, 0, 0, 78, 79, 63, 64, 65, 0, 0,
+	0, 0, 0, 0, 86, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 68, 70, 58, 59, 60, 61,
+	62, 0, 205, 0, 84, 0, 57, 0, 0, 85,
+	0, 80, 82, 66, 67, 69, 71, 81, 83, 0,
+	0, 0, 0, 0, 0, 0, 72, 73, 74, 75,
+	76, 77, 0, 0, 78, 79, 63, 64, 65, 0,
+	0, 0, 0, 0, 0, 86, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 292, 0, 0, 0, 296, 0, 0,
-	0, 0, 299, 300, 0, 0, 0, 0, 0, 0,
-	0, 0, 303, 302, 0, 248, 249, 305, 306, 307,
-	253, 0, 255, 0, 0, 310, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 316, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 27, 28, 32,
-	0, 273, 38, 20, 21, 48, 0, 23, 0, 278,
-	279, 0, 0, 0, 0, 33, 34, 35, 0, 25,
-	0, 0, 0, 0, 0, 0, 0, 0, 18, 19,
-	0, 295, 0, 0, 0, 26, 0, 0, 42, 301,
-	43, 46, 44, 36, 0, 0, 0, 24, 37, 45,
-	0, 0, 0, 0, 0, 0, 0, 29, 0, 0,
-	0, 0, 40, 0, 0, 30, 31, 0, 41, 39,
-	0, 0, 0, 8, 9, 62, 63, 65, 67, 77,
-	79, 0, 0, 0, 0, 0, 0, 0, 68, 69,
-	70, 71, 72, 73, 0, 0, 74, 75, 59, 60,
-	61, 0, 0, 0, 0, 0, 0, 82, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 224,
-	64, 66, 54, 55, 56, 57, 58, 0, 0, 0,
-	53, 0, 0, 223, 80, 81, 0, 76, 78, 62,
-	63, 65, 67, 77, 79, 0, 0, 0, 0, 0,
-	0, 0, 68, 69, 70, 71, 72, 73, 0, 0,
-	74, 75, 59, 60, 61, 0, 0, 0, 0, 0,
-	0, 82, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 222, 64, 66, 54, 55, 56, 57,
-	58, 0, 0, 0, 53, 0, 0, 221, 80, 81,
-	0, 76, 78, 62, 63, 65, 67, 77, 79, 0,
-	0, 0, 0, 0, 0, 0, 68, 69, 70, 71,
-	72, 73, 0, 0, 74, 75, 59, 60, 61, 0,
-	0, 0, 0, 0, 0, 82, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 203, 0, 64, 66,
-	54, 55, 56, 57, 58, 0, 0, 0, 53, 0,
-	0, 0, 80, 81, 202, 76, 78, 62, 63, 65,
-	67, 77, 79, 0, 0, 0, 0, 0, 0, 0,
-	68, 69, 70, 71, 72, 73, 0, 0, 74, 75,
-	59, 60, 61, 0, 0, 0, 0, 0, 0, 82,
+	0, 0, 0, 0, 0, 68, 70, 58, 59, 60,
+	61, 62, 0, 0, 0, 84, 195, 57, 0, 0,
+	85, 0, 80, 82, 66, 67, 69, 71, 81, 83,
+	0, 0, 0, 0, 0, 0, 0, 72, 73, 74,
+	75, 76, 77, 0, 0, 78, 79, 63, 64, 65,
+	0, 0, 0, 0, 0, 0, 86, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	184, 0, 64, 66, 54, 55, 56, 57, 58, 0,
-	0, 0, 53, 0, 0, 0, 80, 81, 183, 76,
-	78, 62, 63, 65, 67, 77, 79, 0, 0, 0,
-	0, 0, 0, 0, 68, 69, 70, 71, 72, 73,
-	0, 0, 74, 75, 59, 60, 61, 0, 0, 0,
-	0, 0, 0, 82, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 64, 66, 54, 55,
-	56, 57, 58, 0, 312, 0, 53, 0, 0, 0,
-	80, 81, 0, 76, 78, 62, 63, 65, 67, 77,
-	79, 0, 0, 0, 0, 0, 0, 0, 68, 69,
-	70, 71, 72, 73, 0, 0, 74, 75, 59, 60,
-	61, 0, 0, 0, 0, 0, 0, 82, 0, 0,
+	0, 0, 0, 0, 0, 184, 68, 70, 58, 59,
+	60, 61, 62, 0, 0, 0, 84, 0, 57, 0,
+	0, 85, 0, 80, 82, 66, 67, 69, 71, 81,
+	83, 0, 0, 0, 0, 0, 0, 0, 72, 73,
+	74, 75, 76, 77, 0, 0, 78, 79, 63, 64,
+	65, 0, 0, 0, 0, 0, 0, 86, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	64, 66, 54, 55, 56, 57, 58, 0, 0, 0,
-	53, 0, 0, 297, 80, 81, 0, 76, 78, 62,
-	63, 65, 67, 77, 79, 0, 0, 0, 0, 0,
-	0, 0, 68, 69, 70, 71, 72, 73, 0, 0,
-	74, 75, 59, 60, 61, 0, 0, 0, 0, 0,
-	0, 82, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 64, 66, 54, 55, 56, 57,
-	58, 0, 294, 0, 53, 0, 0, 0, 80, 81,
-	0, 76, 78, 62, 63, 65, 67, 77, 79, 0,
-	0, 0, 0, 0, 0, 0, 68, 69, 70, 71,
-	72, 73, 0, 0, 74, 75, 59, 60, 61, 0,
-	0, 0, 0, 0, 0, 82, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 64, 66,
-	54, 55, 56, 57, 58, 0, 293, 0, 53, 0,
-	0, 0, 80, 81, 0, 76, 78, 62, 63, 65,
-	67, 77, 79, 0, 0, 0, 0, 0, 0, 0,
-	68, 69, 70, 71, 72, 73, 0, 0, 74, 75,
-	59, 60, 61, 0, 0, 0, 0, 0, 0, 82,
+	0, 0, 0, 0, 0, 168, 0, 68, 70, 58,
+	59, 60, 61, 62, 0, 0, 0, 84, 0, 57,
+	0, 0, 85, 0, 80, 82, 66, 67, 69, 71,
+	81, 83, 0, 0, 0, 0, 0, 0, 0, 72,
+	73, 74, 75, 76, 77, 0, 0, 78, 79, 63,
+	64, 65, 0, 0, 0, 0, 0, 0, 86, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 64, 66, 54, 55, 56, 57, 58, 0,
-	0, 0, 53, 0, 0, 287, 80, 81, 0, 76,
-	78, 62, 63, 65, 67, 77, 79, 0, 0, 0,
-	0, 0, 0, 0, 68, 69, 70, 71, 72, 73,
-	0, 0, 74, 75, 59, 60, 61, 0, 0, 0,
-	0, 0, 0, 82, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 64, 66, 54, 55,
-	56, 57, 58, 0, 0, 0, 53, 0, 0, 286,
-	80, 81, 0, 76, 78, 62, 63, 65, 67, 77,
-	79, 0, 0, 0, 0, 0, 0, 0, 68, 69,
-	70, 71, 72, 73, 0, 0, 74, 75, 59, 60,
-	61, 0, 0, 0, 0, 0, 0, 82, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 68, 70,
+	58, 59, 60, 61, 62, 0, 166, 0, 84, 0,
+	57, 0, 0, 85, 0, 80, 82, 66, 67, 69,
+	71, 81, 83, 0, 0, 0, 0, 0, 0, 0,
+	72, 73, 74, 75, 76, 77, 0, 0, 78, 79,
+	63, 64, 65, 0, 0, 0, 0, 0, 0, 86,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	64, 66, 54, 55, 56, 57, 58, 0, 0, 0,
-	53, 0, 0, 0, 80, 81, 274, 76, 78, 62,
-	63, 65, 67, 77, 79, 0, 0, 0, 0, 0,
-	0, 0, 68, 69, 70, 71, 72, 73, 0, 0,
-	74, 75, 59, 60, 61, 0, 0, 0, 0, 0,
-	0, 82, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 272, 0, 64, 66, 54, 55, 56, 57,
-	58, 0, 0, 0, 53, 0, 0, 0, 80, 81,
-	0, 76, 78, 62, 63, 65, 67, 77, 79, 0,
-	0, 0, 0, 0, 0, 0, 68, 69, 70, 71,
-	72, 73, 0, 0, 74, 75, 59, 60, 61, 0,
-	0, 0, 0, 0, 0, 82, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 64, 66,
-	54, 55, 56, 57, 58, 0, 269, 0, 53, 0,
-	0, 0, 80, 81, 0, 76, 78, 62, 63, 65,
-	67, 77, 79, 0, 0, 0, 0, 0, 0, 0,
-	68, 69, 70, 71, 72, 73, 0, 0, 74, 75,
-	59, 60, 61, 0, 0, 0, 0, 0, 0, 82,
+	0, 0, 0, 0, 0, 56, 0, 0, 0, 68,
+	70, 58, 59, 60, 61, 62, 0, 0, 0, 84,
+	0, 57, 0, 0, 85, 0, 80, 82, 66, 67,
+	69, 71, 81, 83, 0, 0, 0, 0, 0, 0,
+	0, 72, 73, 74, 75, 76, 77, 0, 0, 78,
+	79, 63, 64, 65, 0, 0, 0, 0, 0, 0,
+	86, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 64, 66, 54, 55, 56, 57, 58, 0,
-	0, 0, 53, 0, 0, 0, 80, 81, 268, 76,
-	78, 62, 63, 65, 67, 77, 79, 0, 0, 0,
-	0, 0, 0, 0, 68, 69, 70, 71, 72, 73,
-	0, 0, 74, 75, 59, 60, 61, 0, 0, 0,
-	0, 0, 0, 82, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 64, 66, 54, 55,
-	56, 57, 58, 0, 0, 0, 53, 0, 0, 266,
-	80, 81, 0, 76, 78, 62, 63, 65, 67, 77,
-	79, 0, 0, 0, 0, 0, 0, 0, 68, 69,
-	70, 71, 72, 73, 0, 0, 74, 75, 59, 60,
-	61, 0, 0, 0, 0, 0, 0, 82, 0, 0,
+	68, 70, 58, 59, 60, 61, 62, 0, 0, 0,
+	84, 0, 57, 0, 0, 85, 0, 80, 82, 66,
+	67, 69, 71, 81, 83, 0, 0, 0, 0, 0,
+	0, 0, 72, 73, 74, 75, 76, 77, 0, 0,
+	78, 79, 63, 64, 65, 0, 0, 0, 0, 0,
+	0, 86, 0, 0, 0, 0, 0, 0, 0, 0,
+	31, 32, 36, 0, 0, 42, 20, 21, 52, 0,
+	24, 68, 70, 58, 59, 60, 61, 62, 37, 38,
+	39, 197, 26, 57, 0, 0, 85, 0, 80, 82,
+	0, 18, 19, 0, 0, 0, 0, 0, 27, 0,
+	0, 46, 0, 47, 50, 48, 40, 0, 0, 0,
+	25, 41, 49, 0, 0, 28, 29, 0, 30, 22,
+	0, 0, 0, 0, 0, 0, 0, 33, 0, 0,
+	0, 0, 44, 0, 45, 0, 0, 34, 35, 43,
+	67, 69, 71, 81, 83, 0, 0, 0, 0, 0,
+	0, 0, 72, 73, 74, 75, 76, 77, 0, 0,
+	78, 79, 63, 64, 65, 0, 0, 0, 0, 0,
+	0, 86, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	64, 66, 54, 55, 56, 57, 58, 0, 0, 0,
-	53, 0, 0, 263, 80, 81, 0, 76, 78, 62,
-	63, 65, 67, 77, 79, 0, 0, 0, 0, 0,
-	0, 0, 68, 69, 70, 71, 72, 73, 0, 0,
-	74, 75, 59, 60, 61, 0, 0, 0, 0, 0,
-	0, 82, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 247, 64, 66, 54, 55, 56, 57,
-	58, 0, 0, 0, 53, 0, 0, 0, 80, 81,
-	0, 76, 78, 62, 63, 65, 67, 77, 79, 0,
-	0, 0, 0, 0, 0, 0, 68, 69, 70, 71,
-	72, 73, 0, 0, 74, 75, 59, 60, 61, 0,
-	0, 0, 0, 0, 0, 82, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 64, 66,
-	54, 55, 56, 57, 58, 0, 0, 0, 53, 0,
-	0, 0, 80, 81, 246, 76, 78, 62, 63, 65,
-	67, 77, 79, 0, 0, 0, 0, 0, 0, 0,
-	68, 69, 70, 71, 72, 73, 0, 0, 74, 75,
-	59, 60, 61, 0, 0, 0, 0, 0, 0, 82,
-	0, 0, 0, 237, 0, 0, 0, 0, 0, 0,
-	0, 0, 64, 66, 54, 55, 56, 57, 58, 0,
-	0, 0, 53, 0, 0, 0, 80, 81, 0, 76,
-	78, 62, 63, 65, 67, 77, 79, 0, 0, 0,
-	0, 0, 0, 0, 68, 69, 70, 71, 72, 73,
-	0, 0, 74, 75, 59, 60, 61, 0, 0, 0,
-	0, 0, 0, 82, 0, 0, 0, 236, 0, 0,
-	0, 0, 0, 0, 0, 0, 64, 66, 54, 55,
-	56, 57, 58, 0, 0, 0, 53, 0, 0, 0,
-	80, 81, 0, 76, 78, 62, 63, 65, 67, 77,
-	79, 0, 0, 0, 0, 0, 0, 0, 68, 69,
-	70, 71, 72, 73, 0, 0, 74, 75, 59, 60,
-	61, 0, 0, 0, 0, 0, 0, 82, 0, 0,
+	0, 68, 70, 58, 59, 60, 61, 62, 0, 0,
+	0, 84, 0, 57, 0, 0, 85, 0, 80, 82,
+	66, 67, 69, 71, 0, 83, 0, 0, 0, 0,
+	0, 0, 0, 72, 73, 74, 75, 76, 77, 0,
+	0, 78, 79, 63, 64, 65, 0, 0, 0, 0,
+	0, 0, 86, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	64, 66, 54, 55, 56, 57, 58, 0, 0, 0,
-	53, 0, 0, 0, 80, 81, 232, 76, 78, 62,
-	63, 65, 67, 77, 79, 0, 0, 0, 0, 0,
-	0, 0, 68, 69, 70, 71, 72, 73, 0, 0,
-	74, 75, 59, 60, 61, 0, 0, 0, 0, 0,
-	0, 82, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 64, 66, 54, 55, 56, 57,
-	58, 0, 180, 0, 53, 0, 0, 0, 80, 81,
-	0, 76, 78, 62, 63, 65, 67, 77, 79, 0,
-	0, 0, 0, 0, 0, 0, 68, 69, 70, 71,
-	72, 73, 0, 0, 74, 75, 59, 60, 61, 0,
-	0, 0, 0, 0, 0, 82, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 64, 66,
-	54, 55, 56, 57, 58, 0, 0, 0, 53, 0,
-	0, 170, 80, 81, 0, 76, 78, 62, 63, 65,
-	67, 77, 79, 0, 0, 0, 0, 0, 0, 0,
-	68, 69, 70, 71, 72, 73, 0, 0, 74, 75,
-	59, 60, 61, 0, 0, 0, 0, 0, 0, 82,
+	0, 0, 68, 70, 58, 59, 60, 61, 62, 0,
+	0, 0, 84, 0, 57, 0, 0, 85, 0, 80,
+	82, 66, 67, 69, 71, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 72, 73, 74, 75, 76, 77,
+	0, 0, 78, 79, 63, 64, 65, 0, 0, 0,
+	0, 0, 0, 86, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 162, 64, 66, 54, 55, 56, 57, 58, 0,
-	0, 0, 53, 0, 0, 0, 80, 81, 0, 76,
-	78, 62, 63, 65, 67, 77, 79, 0, 0, 0,
-	0, 0, 0, 0, 68, 69, 70, 71, 72, 73,
-	0, 0, 74, 75, 59, 60, 61, 0, 0, 0,
-	0, 0, 0, 82, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 156, 0, 64, 66, 54, 55,
-	56, 57, 58, 0, 0, 0, 53, 0, 0, 0,
-	80, 81, 0, 76, 78, 62, 63, 65, 67, 77,
-	79, 0, 0, 0, 0, 0, 0, 0, 68, 69,
-	70, 71, 72, 73, 0, 0, 74, 75, 59, 60,
-	61, 0, 0, 0, 0, 0, 0, 82, 0, 0,
+	0, 0, 0, 68, 70, 58, 59, 60, 61, 62,
+	0, 69, 71, 84, 0, 57, 0, 0, 85, 0,
+	80, 82, 72, 73, 74, 75, 76, 77, 0, 0,
+	78, 79, 63, 64, 65, 0, 0, 0, 0, 0,
+	0, 86, 0, 0, 0, 0, 0, 0, 0, 72,
+	73, 74, 75, 76, 77, 0, 0, 78, 79, 63,
+	0, 68, 70, 58, 59, 60, 61, 62, 86, 0,
+	0, 84, 0, 57, 0, 0, 85, 0, 80, 82,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	64, 66, 54, 55, 56, 57, 58, 0, 154, 0,
-	53, 0, 0, 0, 80, 81, 0, 76, 78, 62,
-	63, 65, 67, 77, 79, 0, 0, 0, 0, 0,
-	0, 0, 68, 69, 70, 71, 72, 73, 0, 0,
-	74, 75, 59, 60, 61, 0, 0, 0, 0, 0,
-	0, 82, 0, 0, 0, 0, 0, 0, 0, 0,
-	52, 0, 0, 0, 64, 66, 54, 55, 56, 57,
-	58, 0, 0, 0, 53, 0, 0, 0, 80, 81,
-	0, 76, 78, 62, 63, 65, 67, 77, 79, 0,
-	0, 0, 0, 0, 0, 0, 68, 69, 70, 71,
-	72, 73, 0, 0, 74, 75, 59, 60, 61, 0,
-	0, 0, 0, 0, 0, 82, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 64, 66,
-	54, 55, 56, 57, 58, 0, 0, 0, 53, 0,
-	0, 0, 80, 81, 0, 76, 78, 62, 63, 65,
-	67, 77, 79, 0, 0, 0, 0, 0, 0, 0,
-	68, 69, 70, 71, 72, 73, 0, 0, 74, 75,
-	59, 60, 61, 0, 0, 0, 0, 0, 0, 82,
+	58, 59, 60, 61, 62, 0, 0, 0, 84, 0,
+	57, 0, 0, 85, 0, 80, 82, 282, 32, 36,
+	0, 0, 42, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 37, 38, 39, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 64, 66, 54, 55, 56, 57, 58, 0,
-	0, 0, 53, 0, 0, 0, 172, 81, 0, 76,
-	78, 63, 65, 67, 77, 79, 0, 0, 0, 0,
-	0, 0, 0, 68, 69, 70, 71, 72, 73, 0,
-	0, 74, 75, 59, 60, 61, 0, 0, 0, 0,
-	0, 0, 82, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 64, 66, 54, 55, 56,
-	57, 58, 0, 0, 0, 53, 0, 0, 0, 80,
-	81, 0, 76, 78, 62, 63, 65, 67, 0, 79,
-	0, 0, 0, 0, 0, 0, 0, 68, 69, 70,
-	71, 72, 73, 0, 0, 74, 75, 59, 60, 61,
-	0, 0, 0, 0, 0, 0, 82, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 64,
-	66, 54, 55, 56, 57, 58, 0, 0, 0, 53,
-	0, 0, 0, 80, 81, 0, 76, 78, 62, 63,
-	65, 67, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 68, 69, 70, 71, 72, 73, 0, 0, 74,
-	75, 59, 60, 61, 0, 0, 0, 0, 0, 0,
-	82, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 64, 66, 54, 55, 56, 57, 58,
-	0, 65, 67, 53, 0, 0, 0, 80, 81, 0,
-	76, 78, 68, 69, 70, 71, 72, 73, 0, 0,
-	74, 75, 59, 60, 61, 0, 0, 0, 0, 0,
-	0, 82, 0, 27, 28, 32, 0, 0, 38, 20,
-	21, 48, 0, 23, 64, 66, 54, 55, 56, 57,
-	58, 33, 34, 35, 53, 25, 0, 0, 80, 81,
-	0, 76, 78, 0, 18, 19, 0, 0, 0, 0,
-	0, 26, 0, 0, 42, 0, 43, 46, 44, 36,
-	0, 0, 0, 24, 37, 45, 0, 68, 69, 70,
-	71, 72, 73, 29, 0, 74, 75, 59, 40, 0,
-	0, 30, 31, 0, 41, 39, 82, 0, 234, 28,
-	32, 0, 0, 38, 0, 0, 0, 0, 0, 0,
-	0, 54, 55, 56, 57, 58, 33, 34, 35, 53,
-	0, 0, 0, 80, 81, 0, 76, 78, 0, 0,
-	0, 0, 0, 27, 28, 32, 0, 0, 38, 42,
-	0, 43, 46, 44, 36, 0, 0, 0, 0, 37,
-	45, 33, 34, 35, 0, 0, 0, 0, 29, 0,
-	0, 0, 0, 40, 0, 0, 30, 31, 0, 41,
-	39, 281, 0, 0, 42, 0, 43, 46, 44, 36,
-	0, 0, 0, 0, 37, 45, 0, 0, 0, 27,
-	28, 32, 0, 29, 38, 0, 0, 0, 40, 0,
-	0, 30, 31, 0, 41, 39, 245, 33, 34, 35,
+	0, 0, 0, 0, 0, 0, 0, 0, 46, 0,
+	47, 50, 48, 40, 0, 0, 0, 0, 41, 49,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 27, 28, 32, 0, 0, 38,
-	42, 0, 43, 46, 44, 36, 0, 0, 0, 0,
-	37, 45, 33, 34, 35, 0, 0, 0, 0, 29,
-	0, 0, 0, 0, 40, 0, 0, 30, 31, 0,
-	41, 39, 231, 0, 0, 42, 0, 43, 46, 44,
-	36, 0, 0, 0, 0, 37, 45, 0, 0, 161,
-	27, 28, 32, 0, 29, 38, 0, 0, 0, 40,
-	0, 0, 30, 31, 0, 41, 39, 0, 33, 34,
-	35, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	31, 32, 36, 0, 33, 42, 0, 0, 0, 44,
+	0, 45, 0, 0, 34, 35, 43, 361, 37, 38,
+	39, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 42, 0, 43, 46, 44, 36, 0, 0, 0,
-	0, 37, 45, 0, 0, 145, 27, 28, 32, 0,
-	29, 38, 0, 0, 0, 40, 0, 0, 30, 31,
-	0, 41, 39, 0, 33, 34, 35, 0, 0, 0,
+	0, 46, 0, 47, 50, 48, 40, 0, 0, 0,
+	0, 41, 49, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 31, 32, 36, 0, 33, 42, 0,
+	0, 0, 44, 0, 45, 0, 0, 34, 35, 43,
+	306, 37, 38, 39, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 42, 0, 43,
-	46, 44, 36, 0, 0, 0, 0, 37, 45, 0,
-	0, 93, 27, 28, 32, 0, 29, 38, 0, 0,
-	0, 40, 0, 0, 30, 31, 0, 41, 39, 0,
-	33, 34, 35, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 234, 28, 32,
-	0, 0, 38, 42, 0, 43, 46, 44, 36, 0,
-	0, 0, 0, 37, 45, 33, 34, 35, 0, 0,
-	0, 0, 29, 0, 0, 0, 0, 40, 0, 0,
-	30, 31, 0, 41, 39, 0, 0, 0, 42, 0,
-	43, 46, 44, 36, 0, 0, 0, 0, 37, 45,
-	0, 0, 0, 227, 28, 32, 0, 29, 38, 0,
-	0, 0, 40, 0, 0, 30, 31, 0, 41, 39,
-	0, 33, 34, 35, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 105, 28,
-	32, 0, 0, 38, 42, 0, 43, 46, 44, 36,
-	0, 0, 0, 0, 37, 45, 33, 34, 35, 0,
-	0, 0, 0, 29, 0, 0, 0, 0, 40, 0,
-	0, 30, 31, 0, 41, 39, 0, 0, 0, 42,
-	0, 43, 46, 44, 36, 0, 0, 0, 0, 37,
-	45, 0, 68, 69, 70, 71, 72, 73, 29, 0,
-	0, 0, 59, 40, 0, 0, 30, 31, 0, 41,
-	39, 82, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 56, 57,
-	58, 0, 0, 0, 53, 0, 0, 0, 80, 81,
-	0, 76, 78,
+	0, 0, 0, 0, 46, 0, 47, 50, 48, 40,
+	0, 0, 0, 0, 41, 49, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 31, 32, 36, 0,
+	33, 42, 0, 0, 0, 44, 0, 45, 0, 0,
+	34, 35, 43, 279, 37, 38, 39, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 46, 0, 47,
+	50, 48, 40, 0, 0, 0, 0, 41, 49, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 183, 31,
+	32, 36, 0, 33, 42, 0, 0, 0, 44, 0,
+	45, 0, 0, 34, 35, 43, 0, 37, 38, 39,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	46, 0, 47, 50, 48, 40, 0, 0, 0, 0,
+	41, 49, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 157, 31, 32, 36, 0, 33, 42, 0, 0,
+	0, 44, 0, 45, 0, 0, 34, 35, 43, 0,
+	37, 38, 39, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 46, 0, 47, 50, 48, 40, 0,
+	0, 0, 0, 41, 49, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 98, 31, 32, 36, 0, 33,
+	42, 0, 0, 0, 44, 0, 45, 0, 0, 34,
+	35, 43, 0, 37, 38, 39, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 46, 0, 47, 50,
+	48, 40, 0, 0, 0, 0, 41, 49, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 31, 32,
+	36, 0, 33, 42, 0, 0, 0, 44, 0, 45,
+	0, 0, 34, 35, 43, 0, 37, 38, 39, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 46,
+	0, 47, 50, 48, 40, 0, 0, 0, 0, 41,
+	292, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 282, 32, 36, 0, 33, 42, 0, 0, 0,
+	44, 0, 45, 0, 0, 34, 35, 43, 0, 37,
+	38, 39, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 46, 0, 47, 50, 48, 40, 0, 0,
+	0, 0, 41, 49, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 275, 32, 36, 0, 33, 42,
+	0, 0, 0, 44, 0, 45, 0, 0, 34, 35,
+	43, 0, 37, 38, 39, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 46, 0, 47, 50, 48,
+	40, 0, 0, 0, 0, 41, 49, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 114, 32, 36,
+	0, 33, 42, 0, 0, 0, 44, 0, 45, 0,
+	0, 34, 35, 43, 0, 37, 38, 39, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 46, 0,
+	47, 50, 48, 40, 0, 0, 0, 0, 41, 49,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 33, 0, 0, 0, 0, 44,
+	0, 45, 0, 0, 34, 35, 43,
 }
 
 var yyPact = [...]int16{
-	145, 145, -1000, 203, -1000, -73, -73, -1000, -1000, -1000,
-	-1000, -1000, 2469, -73, -73, -1000, 2053, 141, -1000, -1000,
-	2838, 2838, -1000, 127, 2838, -73, 2782, -66, -1000, 2838,
-	2838, 2838, -1000, -1000, -1000, -1000, -1000, 2838, 42, -73,
-	-73, 2838, 2964, 40, -27, 202, 2838, 85, 2838, -1000,
-	323, -1000, 2838, 201, 2838, 2838, 2838, 2838, 2838, 2838,
-	2838, 2838, 2838, 2838, 2838, 2838, 2838, 2838, 2838, 2838,
-	2838, 2838, 2838, 2838, -1000, -1000, 2838, 2838, 2838, 2838,
-	2838, 2726, 2838, 2838, 2838, 83, 2117, 2117, 198, 139,
-	1989, 125, 1925, -73, 2838, 2670, 61, 61, 61, 1861,
-	197, -42, 2838, 175, 1797, -55, 2181, -47, -58, 2838,
-	-1000, 2838, -60, 2117, -73, 1733, -1000, 2117, -1000, 2993,
-	2993, 61, 61, 61, 2117, 2498, 2498, 2423, 2423, 2498,
-	2498, 2498, 2498, 2117, 2117, 2117, 2117, 2117, 2117, 2117,
-	2308, 2117, 2372, 73, 581, 2838, 2117, -1000, 2117, -1000,
-	-73, 156, 2838, 2838, -73, -73, -73, 81, 151, 46,
-	517, 2838, 2838, 45, 169, 195, -44, -46, -1000, 91,
-	-1000, 2838, 2838, 185, 2838, 2838, 453, 389, 2838, 2929,
-	-73, 33, -1000, -1000, 2635, 1669, 2873, 2838, 1605, 1541,
-	74, 72, 79, -1000, -1000, -1000, 2838, 89, -1000, -1000,
-	24, -1000, -1000, 2579, 1477, 1413, -73, -73, 2, 27,
-	166, -73, -65, -73, 71, 2838, 37, 15, -1000, -34,
-	1349, -1000, 2838, -1000, 2838, 1285, 2244, -66, -1000, -1000,
-	1221, -1000, -1000, 2117, -66, 1157, 2838, 2838, -1000, -1000,
-	-1000, 1093, -73, -1000, 1029, -1000, -1000, 2838, 179, -73,
-	-73, -73, -22, 2544, -1000, 102, -1000, 2117, -24, -1000,
-	-33, -1000, -1000, -1000, 965, 901, -1000, 103, -1000, -73,
-	837, 773, -73, -73, -1000, 709, -1000, 68, -73, -73,
-	-73, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -73,
-	-1000, 2838, 65, -73, -73, -73, -1000, -1000, -1000, 64,
-	60, -73, 57, 645, -1000, 56, 48, -1000, -1000, -1000,
-	44, -1000, -73, -1000, -1000, -1000, -1000,
+	277, 277, -1000, 336, -1000, 3, 3, -1000, -1000, -1000,
+	-1000, -1000, 2546, 3, 3, -1000, 2351, 297, -1000, -1000,
+	3301, 3301, 3301, -1000, 321, 3301, 3, 3238, 335, 334,
+	328, -38, -1000, 3301, 3301, 3301, -1000, -1000, -1000, -1000,
+	-1000, 3301, 133, 3, 3, 3301, 3553, 157, 91, 325,
+	3301, 208, 3301, -1000, 432, -1000, 3301, 333, 3301, 3301,
+	3301, 3301, 3301, 3301, 3301, 3301, 3301, 3301, 3301, 3301,
+	3301, 3301, 3301, 3301, 3301, 3301, 3301, 3301, -1000, -1000,
+	3301, 3301, 3301, 3301, 3301, 3175, 3301, 3301, 3301, 194,
+	2422, 2422, 2422, 332, 296, 2280, 271, 2209, 3, 46,
+	3, 294, 211, 3301, 3112, 198, 198, 198, 2138, 317,
+	86, 3301, 300, 2067, 67, 2493, 16, 75, 3301, -1000,
+	3301, -49, 2422, 3, 1996, -1000, 2422, -1000, 69, 69,
+	198, 198, 198, 2422, 2840, 2840, 2813, 2813, 2840, 2840,
+	2840, 2840, 2422, 2422, 2422, 2422, 2422, 2422, 2422, 2684,
+	2422, 2755, 41, 184, -1000, 2422, 718, 3301, 2422, -1000,
+	2422, -1000, 3, 285, 3301, 3301, 3, 3, 3, 224,
+	251, 182, 331, 3, 3, 305, 3301, 3301, -1000, 320,
+	18, 173, 647, 3301, 3301, 84, 249, -1000, 292, 328,
+	301, 48, 37, -1000, 225, -1000, 3301, 3301, 330, 3301,
+	3301, 576, 505, 3301, 3490, 3, -1000, 3, -1000, -1000,
+	-1000, 3049, 1925, 3427, 3301, 1854, 1783, 178, 166, 129,
+	-1000, -1000, -1000, 3364, 216, -1000, -1000, -1000, -1000, 328,
+	327, 26, -1000, 164, 23, 162, 39, -1000, 326, 2422,
+	2422, -47, 325, -1000, -1000, -1000, 2986, 1712, 1641, 3,
+	211, 1, 3301, 3301, 288, 81, 226, 3, -57, 3,
+	161, 3301, -21, 154, -25, 130, -1000, 77, 1570, -1000,
+	3301, -1000, 3301, 1499, 2613, -38, -1000, 3301, 1428, -1000,
+	-1000, 2422, -38, 1357, 3301, 3301, -1000, -1000, -1000, 244,
+	-1000, 1286, 325, 3, 280, 68, 148, 3, 3, -1000,
+	-1000, -1000, -1000, 44, -47, 1215, -1000, -1000, 3301, 293,
+	3, -1000, 211, 2422, 2422, 3301, 3301, 211, -29, 2923,
+	-1000, 125, -1000, 2422, -1000, -1000, -1000, -1000, -1000, -1000,
+	1144, 1073, -1000, 213, -1000, -1000, 3, 1002, 931, 214,
+	3364, 3301, 3301, -49, 3, 3301, 3301, 293, -1000, 159,
+	324, 275, -1000, 860, -1000, 3, 3, 2422, 2422, 236,
+	211, -1000, -1000, -1000, -1000, -1000, -1000, 3, -1000, 3301,
+	136, 3, 3, 3, -1000, 2422, 2422, -1000, 2422, 2422,
+	51, -1000, -1000, 49, 199, -1000, 112, 3, 3, -1000,
+	236, 105, 789, -1000, 99, 98, 3, 211, 211, -36,
+	-1000, 97, 3, 3, -1000, 3, -1000, -1000, -1000, 236,
+	-1000, 211, -1000, 94, 3, -1000, 3, -1000, -1000, 93,
+	3, -1000, 82, -1000,
 }
 
-var yyPgo = [...]uint8{
-	0, 3, 219, 208, 218, 166, 214, 6, 5, 4,
-	213, 212, 169, 0, 18, 19, 1, 209, 2, 186,
-	88, 160,
+var yyPgo = [...]int16{
+	0, 12, 381, 363, 379, 307, 378, 17, 16, 14,
+	7, 377, 374, 373, 6, 3, 5, 1, 371, 0,
+	20, 79, 4, 369, 9, 18, 2, 15, 10, 367,
+	11, 25, 19, 364, 8, 24, 95, 37,
 }
 
 var yyR1 = [...]int8{
 	0, 2, 2, 2, 3, 1, 1, 4, 4, 4,
 	5, 5, 5, 5, 5, 5, 5, 5, 5, 5,
-	5, 5, 5, 5, 5, 5, 11, 11, 10, 6,
-	6, 9, 9, 9, 9, 9, 8, 7, 16, 17,
-	17, 17, 18, 18, 18, 15, 15, 15, 12, 12,
-	14, 14, 14, 14, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 20, 20,
-	19, 19, 21, 21,
+	5, 5, 5, 5, 5, 5, 5, 5, 5, 5,
+	5, 5, 5, 5, 5, 29, 29, 28, 28, 27,
+	27, 27, 27, 33, 33, 34, 34, 13, 13, 12,
+	6, 6, 9, 9, 9, 9, 9, 8, 18, 18,
+	11, 11, 10, 10, 10, 7, 22, 23, 23, 23,
+	15, 15, 16, 16, 17, 17, 25, 25, 24, 24,
+	24, 24, 24, 24, 26, 26, 26, 30, 30, 31,
+	31, 32, 21, 21, 21, 14, 14, 20, 20, 20,
+	20, 19, 19, 19, 19, 19, 19, 19, 19, 19,
+	19, 19, 19, 19, 19, 19, 19, 19, 19, 19,
+	19, 19, 19, 19, 19, 19, 19, 19, 19, 19,
+	19, 19, 19, 19, 19, 19, 19, 19, 19, 19,
+	19, 19, 19, 19, 19, 19, 19, 19, 19, 19,
+	19, 19, 19, 19, 19, 19, 19, 19, 19, 19,
+	19, 19, 19, 19, 19, 19, 19, 19, 19, 19,
+	19, 19, 19, 19, 19, 36, 36, 35, 35, 37,
+	37,
 }
 
 var yyR2 = [...]int8{
 	0, 0, 1, 2, 4, 1, 2, 0, 2, 3,
-	3, 3, 3, 1, 1, 2, 2, 1, 8, 9,
-	9, 5, 5, 5, 4, 1, 0, 2, 4, 8,
-	6, 0, 2, 2, 2, 2, 5, 4, 3, 0,
-	1, 4, 0, 1, 4, 1, 4, 4, 1, 3,
-	0, 1, 4, 4, 1, 1, 2, 2, 2, 1,
-	1, 1, 1, 1, 7, 3, 7, 8, 8, 9,
-	5, 6, 5, 6, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 1, 1, 2, 2, 2, 1, 8,
+	9, 9, 5, 5, 5, 4, 4, 6, 5, 7,
+	5, 2, 4, 4, 1, 1, 4, 2, 3, 2,
+	4, 4, 10, 2, 3, 6, 7, 0, 2, 4,
+	8, 6, 0, 2, 2, 2, 2, 6, 0, 2,
+	1, 3, 1, 3, 2, 4, 3, 0, 1, 4,
+	2, 3, 0, 1, 0, 1, 1, 2, 1, 2,
+	3, 3, 4, 4, 0, 1, 4, 0, 1, 1,
+	4, 2, 1, 4, 4, 1, 3, 0, 1, 4,
+	4, 1, 1, 2, 2, 2, 1, 1, 1, 1,
+	1, 7, 3, 8, 9, 10, 11, 5, 6, 5,
+	6, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 2, 2, 3, 3, 3,
-	3, 5, 4, 6, 5, 5, 4, 6, 5, 4,
-	4, 6, 5, 5, 6, 5, 5, 2, 5, 2,
-	5, 4, 6, 5, 4, 6, 3, 2, 0, 1,
-	1, 2, 1, 1,
+	3, 3, 2, 2, 3, 3, 3, 3, 4, 4,
+	5, 5, 4, 4, 5, 5, 4, 4, 6, 5,
+	5, 6, 5, 5, 2, 5, 2, 5, 4, 6,
+	5, 4, 6, 3, 2, 0, 1, 1, 2, 1,
+	1,
 }
 
 var yyChk = [...]int16{
-	-1000, -2, -3, 25, -3, 4, -19, -21, 80, 81,
-	-1, -21, -20, -4, -19, -5, -13, -15, 35, 36,
-	10, 11, -6, 14, 54, 26, 42, 4, 5, 64,
-	72, 73, 6, 22, 23, 24, 50, 55, 9, 76,
-	69, 75, 45, 47, 49, 56, 48, -14, 12, -20,
-	-19, -21, 57, 71, 63, 64, 65, 66, 67, 39,
-	40, 41, 16, 17, 61, 18, 62, 19, 29, 30,
-	31, 32, 33, 34, 37, 38, 78, 20, 79, 21,
-	75, 76, 48, 57, 16, -14, -13, -13, 51, 4,
-	-13, -1, -13, 59, 75, 76, -13, -13, -13, -13,
-	75, 4, -20, -20, -13, 4, -13, -12, 46, 75,
-	4, 75, -12, -13, 60, -13, -5, -13, 4, -13,
-	-13, -13, -13, -13, -13, -13, -13, -13, -13, -13,
-	-13, -13, -13, -13, -13, -13, -13, -13, -13, -13,
-	-13, -13, -13, -14, -13, 59, -13, -15, -13, -15,
-	60, 4, 57, 16, 69, 27, 59, -9, -20, -14,
-	-13, 59, 60, -18, 4, 75, -14, -17, -16, 6,
-	74, 75, 75, 71, 75, 75, -13, -13, 75, -20,
-	69, 8, 74, 77, 59, -13, -20, 15, -13, -13,
-	-1, -1, -9, 70, -8, -7, 43, 44, -8, -7,
-	8, 74, 77, 59, -13, -13, 60, 74, 8, -18,
-	4, 60, -20, 60, -20, 59, -14, -14, 4, -14,
-	-13, 74, 60, 74, 60, -13, -13, 4, -1, 74,
-	-13, 77, 77, -13, 4, -13, 52, 52, 70, 70,
-	70, -13, 59, 74, -13, 77, 77, 60, -20, -20,
-	74, 74, 8, -20, 77, -20, 70, -13, 8, 74,
-	8, 74, 74, 74, -13, -13, 74, -11, 77, 69,
-	-13, -13, 59, -20, 77, -13, 4, -1, -20, -20,
-	74, 77, -16, 70, 74, 74, 74, 74, -10, 13,
-	70, 53, -1, 69, 69, -20, -1, 74, 70, -1,
-	-1, -20, -1, -13, 70, -1, -1, -1, 70, 70,
-	-1, 70, 69, 70, 70, 70, -1,
+	-1000, -2, -3, 25, -3, 4, -35, -37, 87, 88,
+	-1, -37, -36, -4, -35, -5, -19, -21, 35, 36,
+	10, 11, 63, -6, 14, 54, 26, 42, 59, 60,
+	62, 4, 5, 71, 81, 82, 6, 22, 23, 24,
+	50, 55, 9, 83, 76, 78, 45, 47, 49, 56,
+	48, -20, 12, -36, -35, -37, 64, 80, 70, 71,
+	72, 73, 74, 39, 40, 41, 16, 17, 68, 18,
+	69, 19, 29, 30, 31, 32, 33, 34, 37, 38,
+	85, 20, 86, 21, 78, 83, 48, 64, 16, -20,
+	-19, -19, -19, 51, 4, -19, -1, -19, 66, 4,
+	4, -25, 4, 78, 83, -19, -19, -19, -19, 78,
+	4, -36, -36, -19, 4, -19, -14, 46, 78, 4,
+	78, -14, -19, 67, -19, -5, -19, 4, -19, -19,
+	-19, -19, -19, -19, -19, -19, -19, -19, -19, -19,
+	-19, -19, -19, -19, -19, -19, -19, -19, -19, -19,
+	-19, -19, -32, -31, -30, -19, -19, 66, -19, -21,
+	-19, -21, 67, 4, 64, 16, 76, 27, 66, -9,
+	-36, -36, 61, -28, -33, -36, 64, 16, -15, 66,
+	-32, -31, -19, 66, 67, -26, 4, -24, -25, 58,
+	78, -20, -23, -22, 6, 79, 78, 78, 80, 78,
+	78, -19, -19, 78, -36, 76, 79, 67, 8, 79,
+	84, 66, -19, -36, 15, -19, -19, -1, -1, -9,
+	77, -8, -7, 43, 44, -8, -7, 77, -27, 62,
+	9, -29, 4, -36, -35, -36, -35, -34, 9, -19,
+	-19, -14, 56, 79, 79, 84, 66, -19, -19, 67,
+	79, 8, 64, 16, -25, -26, 4, 67, -36, 67,
+	-36, 66, -32, -31, -32, -31, 4, -20, -19, 79,
+	67, 79, 67, -19, -19, 4, -1, -36, -19, 84,
+	84, -19, 4, -19, 52, 52, 77, 77, 77, -11,
+	-10, -19, 56, 66, -25, 4, -36, -28, 67, 77,
+	-27, 77, -34, 4, -14, -19, 84, 84, 67, -36,
+	-16, -15, 79, -19, -19, 64, 16, 79, 8, -36,
+	84, -36, 77, -19, 79, 79, 79, 79, 79, 79,
+	-19, -19, 79, -13, -30, 84, 76, -19, -19, -18,
+	67, 12, 52, -14, -36, 64, 16, 78, 77, -36,
+	-36, 78, 84, -19, -24, -36, -16, -19, -19, -16,
+	79, 84, -22, 77, 79, 79, -12, 13, 77, 53,
+	-1, 76, 76, 66, -10, -19, -19, -1, -19, -19,
+	-26, 77, 4, -26, 4, 79, -1, -36, -17, 57,
+	-16, -1, -19, 77, -1, -1, -36, 79, 79, 8,
+	77, -1, -36, -17, 77, 76, 77, 77, -1, -16,
+	-16, 79, 77, -1, -36, -1, -17, -16, 77, -1,
+	-36, 77, -1, 77,
 }
 
 var yyDef = [...]int16{
-	1, -2, 2, 0, 3, 0, -2, 130, 132, 133,
-	4, 130, -2, 128, 129, 8, -2, 0, 13, 14,
-	50, 0, 17, 0, 0, -2, 0, 54, 55, 0,
-	0, 0, 59, 60, 61, 62, 63, 0, 0, 128,
-	128, 0, 0, 0, 0, 0, 0, 0, 0, 6,
-	-2, 131, 0, 0, 0, 0, 0, 0, 0, 0,
+	1, -2, 2, 0, 3, 0, -2, 177, 179, 180,
+	4, 177, -2, 175, 176, 8, -2, 0, 13, 14,
+	97, 0, 0, 18, 0, 0, -2, 0, 0, 0,
+	0, 101, 102, 0, 0, 0, 106, 107, 108, 109,
+	110, 0, 0, 175, 175, 0, 0, 0, 0, 0,
+	0, 0, 0, 6, -2, 178, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 95, 96, 0, 0, 0, 0,
-	50, 0, 0, 50, 50, 15, 51, 16, 0, 0,
-	0, 0, 0, 31, 50, 0, 56, 57, 58, 0,
-	42, 0, 50, 39, 0, 54, 0, 117, 119, 0,
-	48, 0, 0, 127, 128, 0, 9, 10, 65, 75,
-	76, 77, 78, 79, 80, 81, 82, -2, -2, 85,
-	86, 87, 88, 89, 90, 91, 92, 93, 94, 97,
-	98, 99, 100, 0, 0, 0, 126, 11, -2, 12,
-	128, 0, 0, 0, -2, -2, 31, 0, 0, 0,
-	0, 0, 0, 0, 43, 42, 128, 128, 40, 0,
-	74, 50, 50, 0, 50, 0, 0, 0, 0, 0,
-	-2, 0, 106, 110, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 24, 34, 35, 0, 0, 32, 33,
-	0, 102, 109, 0, 0, 0, 128, 128, 0, 0,
-	43, 128, 0, 128, 0, 0, 0, 0, 49, 0,
-	0, 124, 0, 121, 0, 0, -2, -2, 26, 105,
-	0, 115, 116, 52, -2, 0, 0, 0, 21, 22,
-	23, 0, 128, 101, 0, 112, 113, 0, 0, -2,
-	128, 128, 0, 0, 70, 0, 72, 38, 0, -2,
-	0, -2, 118, 120, 0, 0, 123, 0, 114, -2,
-	0, 0, 128, -2, 111, 0, 44, 0, -2, -2,
-	128, 71, 41, 73, -2, -2, 125, 122, 27, -2,
-	30, 0, 0, -2, -2, -2, 37, 64, 66, 0,
-	0, -2, 0, 0, 18, 0, 0, 36, 67, 68,
-	0, 29, -2, 19, 20, 69, 28,
+	0, 0, 0, 0, 0, 0, 0, 0, 142, 143,
+	0, 0, 0, 0, 87, 0, 0, 97, 97, 15,
+	98, 16, 17, 0, 0, 0, 0, 0, 52, 175,
+	175, 31, 76, 87, 0, 103, 104, 105, 0, 84,
+	0, 97, 67, 0, 101, 0, 164, 166, 0, 95,
+	0, 0, 174, 175, 0, 9, 10, 112, 122, 123,
+	124, 125, 126, 127, 128, 129, -2, -2, 132, 133,
+	134, 135, 136, 137, 138, 139, 140, 141, 144, 145,
+	146, 147, 0, 0, 89, 88, 0, 0, 173, 11,
+	-2, 12, 175, 0, 0, 0, -2, -2, 52, 0,
+	0, 0, 0, 175, 175, 0, 0, 0, 77, 0,
+	0, 0, 0, 0, 0, 0, 76, 85, 78, 0,
+	84, 175, 175, 68, 0, 121, 87, 87, 0, 97,
+	0, 0, 0, 0, 0, -2, 152, 175, 91, 153,
+	157, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	25, 55, 56, 0, 0, 53, 54, 26, 37, 0,
+	0, 175, 35, 0, 176, 0, 176, 43, 0, 32,
+	33, 70, 0, 148, 149, 156, 0, 0, 0, 175,
+	72, 0, 0, 0, 79, 0, 76, 175, 0, 175,
+	0, 0, 0, 0, 0, 0, 96, 0, 0, 171,
+	0, 168, 0, 0, -2, -2, 47, 87, 0, 162,
+	163, 99, -2, 0, 0, 0, 22, 23, 24, 58,
+	60, 62, 0, 175, 39, 0, 0, 175, 175, 28,
+	38, 30, 44, 0, 71, 0, 159, 160, 0, 0,
+	175, 73, 72, 80, 81, 0, 0, 72, 0, 0,
+	117, 0, 119, 66, -2, -2, -2, -2, 165, 167,
+	0, 0, 170, 0, 90, 161, -2, 0, 0, 0,
+	0, 0, 0, 64, -2, 0, 0, 84, 27, 0,
+	0, 84, 158, 0, 86, -2, 175, 82, 83, 74,
+	72, 118, 69, 120, 172, 169, 48, -2, 51, 0,
+	0, -2, -2, 175, 61, 59, 63, 65, 40, 41,
+	0, 29, 36, 0, 76, 111, 0, -2, 175, 75,
+	74, 0, 0, 19, 0, 0, -2, 72, 72, 0,
+	113, 0, -2, 175, 50, -2, 20, 21, 57, 74,
+	45, 72, 114, 0, -2, 49, 175, 46, 115, 0,
+	-2, 116, 0, 42,
 }
 
 var yyTok1 = [...]int8{
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	81, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	88, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 72, 3, 3, 3, 67, 79, 3,
-	75, 74, 65, 63, 60, 64, 71, 66, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 3, 59, 80,
-	62, 57, 61, 58, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 81, 3, 3, 3, 74, 86, 3,
+	78, 79, 72, 70, 67, 71, 80, 73, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 3, 66, 87,
+	69, 64, 68, 65, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 76, 3, 77, 73, 3, 3, 3, 3, 3,
+	3, 83, 3, 84, 82, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 69, 78, 70,
+	3, 3, 3, 76, 85, 77,
 }
 
 var yyTok2 = [...]int8{
@@ -867,7 +991,8 @@ var yyTok2 = [...]int8{
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
 	32, 33, 34, 35, 36, 37, 38, 39, 40, 41,
 	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
-	52, 53, 54, 55, 56, 68,
+	52, 53, 54, 55, 56, 57, 58, 59, 60, 61,
+	62, 63, 75,
 }
 
 var yyTok3 = [...]int8{
@@ -1213,7 +1338,7 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:72
+//line parser.y:92
 		{
 			yyVAL.modules = nil
 			if l, ok := yylex.(*Lexer); ok {
@@ -1222,7 +1347,7 @@ yydefault:
 		}
 	case 2:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:79
+//line parser.y:99
 		{
 			yyVAL.modules = ast.Stmts{yyDollar[1].module}
 			if l, ok := yylex.(*Lexer); ok {
@@ -1231,7 +1356,7 @@ yydefault:
 		}
 	case 3:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:86
+//line parser.y:106
 		{
 			if yyDollar[2].module != nil {
 				yyVAL.modules = append(yyDollar[1].modules, yyDollar[2].module)
@@ -1242,38 +1367,38 @@ yydefault:
 		}
 	case 4:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:97
+//line parser.y:117
 		{
 			yyVAL.module = &ast.ModuleStmt{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Stmts: yyDollar[4].compstmt}
 			yyVAL.module.SetPosition(yyDollar[1].tok.Position())
 		}
 	case 5:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:103
+//line parser.y:131
 		{
 			yyVAL.compstmt = nil
 		}
 	case 6:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:107
+//line parser.y:135
 		{
 			yyVAL.compstmt = yyDollar[1].stmts
 		}
 	case 7:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:112
+//line parser.y:140
 		{
 			yyVAL.stmts = nil
 		}
 	case 8:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:116
+//line parser.y:144
 		{
 			yyVAL.stmts = ast.Stmts{yyDollar[2].stmt}
 		}
 	case 9:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:120
+//line parser.y:148
 		{
 			if yyDollar[3].stmt != nil {
 				yyVAL.stmts = append(yyDollar[1].stmts, yyDollar[3].stmt)
@@ -1281,172 +1406,313 @@ yydefault:
 		}
 	case 10:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:128
+//line parser.y:156
 		{
 			yyVAL.stmt = &ast.LetsStmt{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "=", Rhss: []ast.Expr{yyDollar[3].expr}}
 		}
 	case 11:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:132
+//line parser.y:160
 		{
 			yyVAL.stmt = &ast.LetsStmt{Lhss: yyDollar[1].expr_many, Operator: "=", Rhss: yyDollar[3].expr_many}
 		}
 	case 12:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:136
+//line parser.y:164
 		{
 			yyVAL.stmt = &ast.ExprStmt{Expr: &ast.BinOpExpr{Lhss: yyDollar[1].expr_many, Operator: "==", Rhss: yyDollar[3].expr_many}}
 		}
 	case 13:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:140
+//line parser.y:168
 		{
 			yyVAL.stmt = &ast.BreakStmt{}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
 	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:145
+//line parser.y:173
 		{
 			yyVAL.stmt = &ast.ContinueStmt{}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
 	case 15:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:150
+//line parser.y:178
 		{
 			yyVAL.stmt = &ast.ReturnStmt{Exprs: yyDollar[2].exprs}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
 	case 16:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:155
+//line parser.y:183
 		{
 			yyVAL.stmt = &ast.ThrowStmt{Expr: yyDollar[2].expr}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
 	case 17:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:188
+		{
+			yyVAL.stmt = &ast.YieldStmt{Expr: yyDollar[2].expr}
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 18:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:160
+//line parser.y:193
 		{
 			yyVAL.stmt = yyDollar[1].stmt_if
 			yyVAL.stmt.SetPosition(yyDollar[1].stmt_if.Position())
 		}
-	case 18:
+	case 19:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line parser.y:165
+//line parser.y:198
 		{
 			yyVAL.stmt = &ast.ForStmt{Var: names.UniqueNames.Set(yyDollar[3].tok.Lit), Value: yyDollar[5].expr, Stmts: yyDollar[7].compstmt}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 19:
+	case 20:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line parser.y:170
+//line parser.y:203
 		{
 			yyVAL.stmt = &ast.NumForStmt{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Expr1: yyDollar[4].expr, Expr2: yyDollar[6].expr, Stmts: yyDollar[8].compstmt}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 20:
+	case 21:
 		yyDollar = yyS[yypt-9 : yypt+1]
-//line parser.y:175
+//line parser.y:208
 		{
 			yyVAL.stmt = &ast.NumForStmt{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Expr1: yyDollar[4].expr, Expr2: yyDollar[6].expr, Stmts: yyDollar[8].compstmt}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 21:
+	case 22:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:180
+//line parser.y:213
 		{
 			yyVAL.stmt = &ast.LoopStmt{Expr: yyDollar[2].expr, Stmts: yyDollar[4].compstmt}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 22:
+	case 23:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:185
+//line parser.y:218
 		{
 			yyVAL.stmt = &ast.TryStmt{Try: yyDollar[2].compstmt, Catch: yyDollar[4].compstmt}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 23:
+	case 24:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:190
+//line parser.y:223
 		{
 			yyVAL.stmt = &ast.SwitchStmt{Expr: yyDollar[2].expr, Cases: yyDollar[4].stmt_cases}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 24:
+	case 25:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:195
+//line parser.y:228
 		{
 			yyVAL.stmt = &ast.SelectStmt{Cases: yyDollar[3].stmt_cases}
 			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 25:
+	case 26:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:233
+		{
+			yyVAL.stmt = ast.NewClassStmt(names.UniqueNames.Set(yyDollar[2].tok.Lit), nil, nil)
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 27:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line parser.y:238
+		{
+			yyVAL.stmt = ast.NewClassStmt(names.UniqueNames.Set(yyDollar[2].tok.Lit), yyDollar[4].idents, nil)
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 28:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:243
+		{
+			yyVAL.stmt = ast.NewClassStmt(names.UniqueNames.Set(yyDollar[2].tok.Lit), nil, yyDollar[3].class_members)
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 29:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line parser.y:248
+		{
+			yyVAL.stmt = ast.NewClassStmt(names.UniqueNames.Set(yyDollar[2].tok.Lit), yyDollar[4].idents, yyDollar[5].class_members)
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 30:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:253
+		{
+			yyVAL.stmt = &ast.InterfaceStmt{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Methods: yyDollar[3].exprs}
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 31:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:258
+		{
+			yyVAL.stmt = &ast.VarStmt{Names: []int{yyDollar[2].func_param.Name}, Types: []int{yyDollar[2].func_param.Type}, Exprs: []ast.Expr{&ast.ConstExpr{Value: "неопределено"}}}
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 32:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:263
+		{
+			yyVAL.stmt = &ast.VarStmt{Names: []int{yyDollar[2].func_param.Name}, Types: []int{yyDollar[2].func_param.Type}, Exprs: []ast.Expr{yyDollar[4].expr}}
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 33:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:268
+		{
+			yyVAL.stmt = &ast.VarStmt{Names: []int{yyDollar[2].func_param.Name}, Types: []int{yyDollar[2].func_param.Type}, Exprs: []ast.Expr{yyDollar[4].expr}}
+			yyVAL.stmt.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 34:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:200
+//line parser.y:273
 		{
 			yyVAL.stmt = &ast.ExprStmt{Expr: yyDollar[1].expr}
 			yyVAL.stmt.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 26:
+	case 35:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:280
+		{
+			yyVAL.idents = []int{names.UniqueNames.Set(yyDollar[1].tok.Lit)}
+		}
+	case 36:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:284
+		{
+			yyVAL.idents = append(yyDollar[1].idents, names.UniqueNames.Set(yyDollar[4].tok.Lit))
+		}
+	case 37:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:290
+		{
+			yyVAL.class_members = []*ast.ClassMember{yyDollar[2].class_member}
+		}
+	case 38:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:294
+		{
+			yyVAL.class_members = append(yyDollar[1].class_members, yyDollar[3].class_member)
+		}
+	case 39:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:300
+		{
+			yyVAL.class_member = &ast.ClassMember{Field: yyDollar[2].func_param.Name, Type: yyDollar[2].func_param.Type}
+		}
+	case 40:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:304
+		{
+			yyVAL.class_member = &ast.ClassMember{Field: yyDollar[2].func_param.Name, Type: yyDollar[2].func_param.Type, Default: yyDollar[4].expr}
+		}
+	case 41:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:308
+		{
+			yyVAL.class_member = &ast.ClassMember{Field: yyDollar[2].func_param.Name, Type: yyDollar[2].func_param.Type, Default: yyDollar[4].expr}
+		}
+	case 42:
+		yyDollar = yyS[yypt-10 : yypt+1]
+//line parser.y:312
+		{
+			yyVAL.class_member = &ast.ClassMember{Method: ast.NewMethodExpr(names.UniqueNames.Set(yyDollar[2].tok.Lit), yyDollar[4].func_params, yyDollar[9].compstmt)}
+			yyVAL.class_member.Method.RetType = yyDollar[6].typ.Name
+			yyVAL.class_member.Method.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 43:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:320
+		{
+			yyVAL.exprs = []ast.Expr{yyDollar[2].expr}
+		}
+	case 44:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:324
+		{
+			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
+		}
+	case 45:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line parser.y:330
+		{
+			fn := &ast.FuncExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), RetType: yyDollar[6].typ.Name}
+			fn.SetParams(yyDollar[4].func_params)
+			yyVAL.expr = fn
+			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 46:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line parser.y:337
+		{
+			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Args: []int{names.UniqueNames.Set(yyDollar[4].tok.Lit)}, VarArg: true, RetType: yyDollar[7].typ.Name}
+			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 47:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:206
+//line parser.y:343
 		{
 			yyVAL.stmt_elsifs = ast.Stmts{}
 		}
-	case 27:
+	case 48:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:210
+//line parser.y:347
 		{
 			yyVAL.stmt_elsifs = append(yyDollar[1].stmt_elsifs, yyDollar[2].stmt_elsif)
 		}
-	case 28:
+	case 49:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:216
+//line parser.y:353
 		{
 			yyVAL.stmt_elsif = &ast.IfStmt{If: yyDollar[2].expr, Then: yyDollar[4].compstmt}
 		}
-	case 29:
+	case 50:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line parser.y:222
+//line parser.y:359
 		{
 			yyVAL.stmt_if = &ast.IfStmt{If: yyDollar[2].expr, Then: yyDollar[4].compstmt, ElseIf: yyDollar[5].stmt_elsifs, Else: yyDollar[7].compstmt}
 			yyVAL.stmt_if.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 30:
+	case 51:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:227
+//line parser.y:364
 		{
 			yyVAL.stmt_if = &ast.IfStmt{If: yyDollar[2].expr, Then: yyDollar[4].compstmt, ElseIf: yyDollar[5].stmt_elsifs, Else: nil}
 			yyVAL.stmt_if.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 31:
+	case 52:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:233
+//line parser.y:370
 		{
 			yyVAL.stmt_cases = ast.Stmts{}
 		}
-	case 32:
+	case 53:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:237
+//line parser.y:374
 		{
 			yyVAL.stmt_cases = ast.Stmts{yyDollar[2].stmt_case}
 		}
-	case 33:
+	case 54:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:241
+//line parser.y:378
 		{
 			yyVAL.stmt_cases = ast.Stmts{yyDollar[2].stmt_default}
 		}
-	case 34:
+	case 55:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:245
+//line parser.y:382
 		{
 			yyVAL.stmt_cases = append(yyDollar[1].stmt_cases, yyDollar[2].stmt_case)
 		}
-	case 35:
+	case 56:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:249
+//line parser.y:386
 		{
 			for _, stmt := range yyDollar[1].stmt_cases {
 				if _, ok := stmt.(*ast.DefaultStmt); ok {
@@ -1455,247 +1721,430 @@ yydefault:
 			}
 			yyVAL.stmt_cases = append(yyDollar[1].stmt_cases, yyDollar[2].stmt_default)
 		}
-	case 36:
-		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:260
+	case 57:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line parser.y:397
 		{
-			yyVAL.stmt_case = &ast.CaseStmt{Expr: yyDollar[2].expr, Stmts: yyDollar[5].compstmt}
+			yyVAL.stmt_case = ast.NewCaseStmt(yyDollar[2].exprs, yyDollar[3].expr, yyDollar[6].compstmt)
 		}
-	case 37:
+	case 58:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line parser.y:402
+		{
+			yyVAL.expr = nil
+		}
+	case 59:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:406
+		{
+			yyVAL.expr = yyDollar[2].expr
+		}
+	case 60:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:412
+		{
+			yyVAL.exprs = []ast.Expr{yyDollar[1].expr}
+		}
+	case 61:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:416
+		{
+			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
+		}
+	case 62:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:422
+		{
+			yyVAL.expr = yyDollar[1].expr
+		}
+	case 63:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:426
+		{
+			yyVAL.expr = &ast.RangePattern{From: yyDollar[1].expr, To: yyDollar[3].expr}
+			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
+		}
+	case 64:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:431
+		{
+			yyVAL.expr = &ast.TypePattern{Type: yyDollar[2].typ.Name}
+			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
+		}
+	case 65:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:266
+//line parser.y:438
 		{
 			yyVAL.stmt_default = &ast.DefaultStmt{Stmts: yyDollar[4].compstmt}
 		}
-	case 38:
+	case 66:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:272
+//line parser.y:444
 		{
 			yyVAL.expr_pair = &ast.PairExpr{Key: yyDollar[1].tok.Lit, Value: yyDollar[3].expr}
 		}
-	case 39:
+	case 67:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:277
+//line parser.y:449
 		{
 			yyVAL.expr_pairs = []ast.Expr{}
 		}
-	case 40:
+	case 68:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:281
+//line parser.y:453
 		{
 			yyVAL.expr_pairs = []ast.Expr{yyDollar[1].expr_pair}
 		}
-	case 41:
+	case 69:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:285
+//line parser.y:457
 		{
 			yyVAL.expr_pairs = append(yyDollar[1].expr_pairs, yyDollar[4].expr_pair)
 		}
-	case 42:
+	case 70:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:463
+		{
+			yyVAL.typ = yyDollar[2].typ
+		}
+	case 71:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:467
+		{
+			yyVAL.typ = yyDollar[3].typ
+		}
+	case 72:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:290
+//line parser.y:472
 		{
-			yyVAL.expr_idents = []int{}
+			yyVAL.typ = ast.Type{}
 		}
-	case 43:
+	case 73:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:294
+//line parser.y:476
 		{
-			yyVAL.expr_idents = []int{names.UniqueNames.Set(yyDollar[1].tok.Lit)}
+			yyVAL.typ = yyDollar[1].typ
 		}
-	case 44:
+	case 74:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line parser.y:481
+		{
+			yyVAL.tok = ast.Token{}
+		}
+	case 75:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:485
+		{
+			yyVAL.tok = yyDollar[1].tok
+		}
+	case 76:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:491
+		{
+			yyVAL.func_param = &ast.FuncParam{Name: names.UniqueNames.Set(yyDollar[1].tok.Lit)}
+		}
+	case 77:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:495
+		{
+			yyVAL.func_param = &ast.FuncParam{Name: names.UniqueNames.Set(yyDollar[1].tok.Lit), Type: yyDollar[2].typ.Name}
+		}
+	case 78:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:501
+		{
+			yyVAL.func_param = yyDollar[1].func_param
+		}
+	case 79:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:505
+		{
+			yyVAL.func_param = yyDollar[2].func_param
+			yyVAL.func_param.ByVal = true
+		}
+	case 80:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:510
+		{
+			yyVAL.func_param = yyDollar[1].func_param
+			yyVAL.func_param.Default = yyDollar[3].expr
+		}
+	case 81:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line parser.y:515
+		{
+			yyVAL.func_param = yyDollar[1].func_param
+			yyVAL.func_param.Default = yyDollar[3].expr
+		}
+	case 82:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:298
+//line parser.y:520
 		{
-			yyVAL.expr_idents = append(yyDollar[1].expr_idents, names.UniqueNames.Set(yyDollar[4].tok.Lit))
+			yyVAL.func_param = yyDollar[2].func_param
+			yyVAL.func_param.ByVal = true
+			yyVAL.func_param.Default = yyDollar[4].expr
 		}
-	case 45:
+	case 83:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:526
+		{
+			yyVAL.func_param = yyDollar[2].func_param
+			yyVAL.func_param.ByVal = true
+			yyVAL.func_param.Default = yyDollar[4].expr
+		}
+	case 84:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line parser.y:533
+		{
+			yyVAL.func_params = []*ast.FuncParam{}
+		}
+	case 85:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:304
+//line parser.y:537
+		{
+			yyVAL.func_params = []*ast.FuncParam{yyDollar[1].func_param}
+		}
+	case 86:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:541
+		{
+			yyVAL.func_params = append(yyDollar[1].func_params, yyDollar[4].func_param)
+		}
+	case 87:
+		yyDollar = yyS[yypt-0 : yypt+1]
+//line parser.y:546
+		{
+			yyVAL.expr = &ast.NoneExpr{}
+		}
+	case 88:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:550
+		{
+			yyVAL.expr = yyDollar[1].expr
+		}
+	case 89:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:556
+		{
+			if _, ok := yyDollar[1].expr.(*ast.NoneExpr); ok {
+				yyVAL.exprs = nil
+			} else {
+				yyVAL.exprs = []ast.Expr{yyDollar[1].expr}
+			}
+		}
+	case 90:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:564
+		{
+			if len(yyDollar[1].exprs) == 0 {
+				// пропущен первый параметр
+				yyDollar[1].exprs = []ast.Expr{&ast.NoneExpr{}}
+			}
+			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[4].expr)
+		}
+	case 91:
+		yyDollar = yyS[yypt-2 : yypt+1]
+//line parser.y:574
+		{
+			// разворачиваемый массив пропустить нельзя
+			if len(yyDollar[1].exprs) == 0 {
+				yylex.Error("не указан массив перед ...")
+			} else if _, ok := yyDollar[1].exprs[len(yyDollar[1].exprs)-1].(*ast.NoneExpr); ok {
+				yylex.Error("не указан массив перед ...")
+			}
+			yyVAL.exprs = yyDollar[1].exprs
+		}
+	case 92:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line parser.y:586
 		{
 			yyVAL.expr_many = []ast.Expr{yyDollar[1].expr}
 		}
-	case 46:
+	case 93:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:308
+//line parser.y:590
 		{
 			yyVAL.expr_many = append(yyDollar[1].exprs, yyDollar[4].expr)
 		}
-	case 47:
+	case 94:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:312
+//line parser.y:594
 		{
 			yyVAL.expr_many = append(yyDollar[1].exprs, &ast.IdentExpr{Lit: yyDollar[4].tok.Lit, Id: names.UniqueNames.Set(yyDollar[4].tok.Lit)})
 		}
-	case 48:
+	case 95:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:317
+//line parser.y:599
 		{
 			yyVAL.typ = ast.Type{Name: names.UniqueNames.Set(yyDollar[1].tok.Lit)}
 		}
-	case 49:
+	case 96:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:321
+//line parser.y:603
 		{
 			yyVAL.typ = ast.Type{Name: names.UniqueNames.Set(names.UniqueNames.Get(yyDollar[1].typ.Name) + "." + yyDollar[3].tok.Lit)}
 		}
-	case 50:
+	case 97:
 		yyDollar = yyS[yypt-0 : yypt+1]
-//line parser.y:326
+//line parser.y:608
 		{
 			yyVAL.exprs = nil
 		}
-	case 51:
+	case 98:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:330
+//line parser.y:612
 		{
 			yyVAL.exprs = []ast.Expr{yyDollar[1].expr}
 		}
-	case 52:
+	case 99:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:334
+//line parser.y:616
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[4].expr)
 		}
-	case 53:
+	case 100:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:338
+//line parser.y:620
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, &ast.IdentExpr{Lit: yyDollar[4].tok.Lit, Id: names.UniqueNames.Set(yyDollar[4].tok.Lit)})
 		}
-	case 54:
+	case 101:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:344
+//line parser.y:626
 		{
 			yyVAL.expr = &ast.IdentExpr{Lit: yyDollar[1].tok.Lit, Id: names.UniqueNames.Set(yyDollar[1].tok.Lit)}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 55:
+	case 102:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:349
+//line parser.y:631
 		{
 			yyVAL.expr = &ast.NumberExpr{Lit: yyDollar[1].tok.Lit}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 56:
+	case 103:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:354
+//line parser.y:636
 		{
 			yyVAL.expr = &ast.UnaryExpr{Operator: "-", Expr: yyDollar[2].expr}
 			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
 		}
-	case 57:
+	case 104:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:359
+//line parser.y:641
 		{
 			yyVAL.expr = &ast.UnaryExpr{Operator: "!", Expr: yyDollar[2].expr}
 			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
 		}
-	case 58:
+	case 105:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:364
+//line parser.y:646
 		{
 			yyVAL.expr = &ast.UnaryExpr{Operator: "^", Expr: yyDollar[2].expr}
 			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
 		}
-	case 59:
+	case 106:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:369
+//line parser.y:651
 		{
 			yyVAL.expr = &ast.StringExpr{Lit: yyDollar[1].tok.Lit}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 60:
+	case 107:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:374
+//line parser.y:656
 		{
 			yyVAL.expr = &ast.ConstExpr{Value: "истина"}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 61:
+	case 108:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:379
+//line parser.y:661
 		{
 			yyVAL.expr = &ast.ConstExpr{Value: "ложь"}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 62:
+	case 109:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:384
+//line parser.y:666
 		{
 			yyVAL.expr = &ast.ConstExpr{Value: "неопределено"}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 63:
+	case 110:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:389
+//line parser.y:671
 		{
 			yyVAL.expr = &ast.ConstExpr{Value: "null"}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 64:
+	case 111:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line parser.y:394
+//line parser.y:676
 		{
 			yyVAL.expr = &ast.TernaryOpExpr{Expr: yyDollar[2].expr, Lhs: yyDollar[4].expr, Rhs: yyDollar[6].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 65:
+	case 112:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:399
+//line parser.y:681
 		{
 			yyVAL.expr = &ast.MemberExpr{Expr: yyDollar[1].expr, Name: names.UniqueNames.Set(yyDollar[3].tok.Lit)}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 66:
-		yyDollar = yyS[yypt-7 : yypt+1]
-//line parser.y:404
+	case 113:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line parser.y:686
 		{
-			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set("<анонимная функция>"), Args: yyDollar[3].expr_idents, Stmts: yyDollar[6].compstmt}
+			fn := &ast.FuncExpr{Name: names.UniqueNames.Set("<анонимная функция>"), Stmts: yyDollar[7].compstmt, RetType: yyDollar[5].typ.Name}
+			fn.SetParams(yyDollar[3].func_params)
+			yyVAL.expr = fn
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 67:
-		yyDollar = yyS[yypt-8 : yypt+1]
-//line parser.y:409
+	case 114:
+		yyDollar = yyS[yypt-9 : yypt+1]
+//line parser.y:693
 		{
-			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set("<анонимная функция>"), Args: []int{names.UniqueNames.Set(yyDollar[3].tok.Lit)}, Stmts: yyDollar[7].compstmt, VarArg: true}
+			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set("<анонимная функция>"), Args: []int{names.UniqueNames.Set(yyDollar[3].tok.Lit)}, Stmts: yyDollar[8].compstmt, VarArg: true, RetType: yyDollar[6].typ.Name}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 68:
-		yyDollar = yyS[yypt-8 : yypt+1]
-//line parser.y:414
+	case 115:
+		yyDollar = yyS[yypt-10 : yypt+1]
+//line parser.y:698
 		{
-			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Args: yyDollar[4].expr_idents, Stmts: yyDollar[7].compstmt}
+			fn := &ast.FuncExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Stmts: yyDollar[9].compstmt, Export: yyDollar[7].tok.Tok == EXPORT, RetType: yyDollar[6].typ.Name}
+			fn.SetParams(yyDollar[4].func_params)
+			yyVAL.expr = fn
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 69:
-		yyDollar = yyS[yypt-9 : yypt+1]
-//line parser.y:419
+	case 116:
+		yyDollar = yyS[yypt-11 : yypt+1]
+//line parser.y:705
 		{
-			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Args: []int{names.UniqueNames.Set(yyDollar[4].tok.Lit)}, Stmts: yyDollar[8].compstmt, VarArg: true}
+			yyVAL.expr = &ast.FuncExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), Args: []int{names.UniqueNames.Set(yyDollar[4].tok.Lit)}, Stmts: yyDollar[10].compstmt, VarArg: true, Export: yyDollar[8].tok.Tok == EXPORT, RetType: yyDollar[7].typ.Name}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 70:
+	case 117:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:424
+//line parser.y:710
 		{
 			yyVAL.expr = &ast.ArrayExpr{Exprs: yyDollar[3].exprs}
 			if l, ok := yylex.(*Lexer); ok {
 				yyVAL.expr.SetPosition(l.pos)
 			}
 		}
-	case 71:
+	case 118:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:429
+//line parser.y:715
 		{
 			yyVAL.expr = &ast.ArrayExpr{Exprs: yyDollar[3].exprs}
 			if l, ok := yylex.(*Lexer); ok {
 				yyVAL.expr.SetPosition(l.pos)
 			}
 		}
-	case 72:
+	case 119:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:434
+//line parser.y:720
 		{
 			mapExpr := make(map[string]ast.Expr)
 			for _, v := range yyDollar[3].expr_pairs {
@@ -1706,9 +2155,9 @@ yydefault:
 				yyVAL.expr.SetPosition(l.pos)
 			}
 		}
-	case 73:
+	case 120:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:443
+//line parser.y:729
 		{
 			mapExpr := make(map[string]ast.Expr)
 			for _, v := range yyDollar[3].expr_pairs {
@@ -1719,404 +2168,404 @@ yydefault:
 				yyVAL.expr.SetPosition(l.pos)
 			}
 		}
-	case 74:
+	case 121:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:452
+//line parser.y:738
 		{
 			yyVAL.expr = &ast.ParenExpr{SubExpr: yyDollar[2].expr}
 			if l, ok := yylex.(*Lexer); ok {
 				yyVAL.expr.SetPosition(l.pos)
 			}
 		}
-	case 75:
+	case 122:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:457
+//line parser.y:743
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "+", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 76:
+	case 123:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:462
+//line parser.y:748
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "-", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 77:
+	case 124:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:467
+//line parser.y:753
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "*", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 78:
+	case 125:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:472
+//line parser.y:758
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "/", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 79:
+	case 126:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:477
+//line parser.y:763
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "%", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 80:
+	case 127:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:482
+//line parser.y:768
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "**", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 81:
+	case 128:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:487
+//line parser.y:773
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "<<", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 82:
+	case 129:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:492
+//line parser.y:778
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: ">>", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 83:
+	case 130:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:497
+//line parser.y:783
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "==", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 84:
+	case 131:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:502
+//line parser.y:788
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "!=", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 85:
+	case 132:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:507
+//line parser.y:793
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: ">", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 86:
+	case 133:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:512
+//line parser.y:798
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: ">=", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 87:
+	case 134:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:517
+//line parser.y:803
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "<", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 88:
+	case 135:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:522
+//line parser.y:808
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "<=", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 89:
+	case 136:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:527
+//line parser.y:813
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "+=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 90:
+	case 137:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:532
+//line parser.y:818
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "-=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 91:
+	case 138:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:537
+//line parser.y:823
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "*=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 92:
+	case 139:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:542
+//line parser.y:828
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "/=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 93:
+	case 140:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:547
+//line parser.y:833
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "&=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 94:
+	case 141:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:552
+//line parser.y:838
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "|=", Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 95:
+	case 142:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:557
+//line parser.y:843
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "++"}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 96:
+	case 143:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:562
+//line parser.y:848
 		{
 			yyVAL.expr = &ast.AssocExpr{Lhs: yyDollar[1].expr, Operator: "--"}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 97:
+	case 144:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:567
+//line parser.y:853
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "|", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 98:
+	case 145:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:572
+//line parser.y:858
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "||", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 99:
+	case 146:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:577
+//line parser.y:863
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "&", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 100:
+	case 147:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:582
+//line parser.y:868
 		{
 			yyVAL.expr = &ast.BinOpExpr{Lhss: []ast.Expr{yyDollar[1].expr}, Operator: "&&", Rhss: []ast.Expr{yyDollar[3].expr}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 101:
-		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:587
+	case 148:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:873
 		{
 			yyVAL.expr = &ast.CallExpr{Name: names.UniqueNames.Set(yyDollar[1].tok.Lit), SubExprs: yyDollar[3].exprs, VarArg: true}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 102:
+	case 149:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:592
+//line parser.y:878
 		{
 			yyVAL.expr = &ast.CallExpr{Name: names.UniqueNames.Set(yyDollar[1].tok.Lit), SubExprs: yyDollar[3].exprs}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 103:
-		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:597
+	case 150:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:883
 		{
 			yyVAL.expr = &ast.CallExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), SubExprs: yyDollar[4].exprs, VarArg: true, Go: true}
 			yyVAL.expr.SetPosition(yyDollar[2].tok.Position())
 		}
-	case 104:
+	case 151:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:602
+//line parser.y:888
 		{
 			yyVAL.expr = &ast.CallExpr{Name: names.UniqueNames.Set(yyDollar[2].tok.Lit), SubExprs: yyDollar[4].exprs, Go: true}
 			yyVAL.expr.SetPosition(yyDollar[2].tok.Position())
 		}
-	case 105:
-		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:607
+	case 152:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line parser.y:893
 		{
 			yyVAL.expr = &ast.AnonCallExpr{Expr: yyDollar[1].expr, SubExprs: yyDollar[3].exprs, VarArg: true}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 106:
+	case 153:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:612
+//line parser.y:898
 		{
 			yyVAL.expr = &ast.AnonCallExpr{Expr: yyDollar[1].expr, SubExprs: yyDollar[3].exprs}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 107:
-		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:617
+	case 154:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line parser.y:903
 		{
 			yyVAL.expr = &ast.AnonCallExpr{Expr: yyDollar[2].expr, SubExprs: yyDollar[4].exprs, VarArg: true, Go: true}
 			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
 		}
-	case 108:
+	case 155:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:622
+//line parser.y:908
 		{
 			yyVAL.expr = &ast.AnonCallExpr{Expr: yyDollar[2].expr, SubExprs: yyDollar[4].exprs, Go: true}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 109:
+	case 156:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:627
+//line parser.y:913
 		{
 			yyVAL.expr = &ast.ItemExpr{Value: &ast.IdentExpr{Lit: yyDollar[1].tok.Lit, Id: names.UniqueNames.Set(yyDollar[1].tok.Lit)}, Index: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 110:
+	case 157:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:632
+//line parser.y:918
 		{
 			yyVAL.expr = &ast.ItemExpr{Value: yyDollar[1].expr, Index: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 111:
+	case 158:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:637
+//line parser.y:923
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: &ast.IdentExpr{Lit: yyDollar[1].tok.Lit, Id: names.UniqueNames.Set(yyDollar[1].tok.Lit)}, Begin: yyDollar[3].expr, End: yyDollar[5].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 112:
+	case 159:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:642
+//line parser.y:928
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: &ast.IdentExpr{Lit: yyDollar[1].tok.Lit, Id: names.UniqueNames.Set(yyDollar[1].tok.Lit)}, Begin: yyDollar[3].expr, End: &ast.NoneExpr{}}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 113:
+	case 160:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:647
+//line parser.y:933
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: &ast.IdentExpr{Lit: yyDollar[1].tok.Lit, Id: names.UniqueNames.Set(yyDollar[1].tok.Lit)}, Begin: &ast.NoneExpr{}, End: yyDollar[4].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 114:
+	case 161:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:652
+//line parser.y:938
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: yyDollar[1].expr, Begin: yyDollar[3].expr, End: yyDollar[5].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 115:
+	case 162:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:657
+//line parser.y:943
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: yyDollar[1].expr, Begin: yyDollar[3].expr, End: &ast.NoneExpr{}}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 116:
+	case 163:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:662
+//line parser.y:948
 		{
 			yyVAL.expr = &ast.SliceExpr{Value: yyDollar[1].expr, Begin: &ast.NoneExpr{}, End: yyDollar[4].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 117:
+	case 164:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:667
+//line parser.y:953
 		{
 			yyVAL.expr = &ast.MakeExpr{Type: yyDollar[2].typ.Name}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 118:
+	case 165:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:672
+//line parser.y:958
 		{
 			yyVAL.expr = &ast.MakeExpr{Type: yyDollar[2].typ.Name, SubExprs: yyDollar[4].exprs}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 119:
+	case 166:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:677
+//line parser.y:963
 		{
 			yyVAL.expr = &ast.MakeChanExpr{SizeExpr: &ast.NoneExpr{}}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 120:
+	case 167:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:682
+//line parser.y:968
 		{
 			yyVAL.expr = &ast.MakeChanExpr{SizeExpr: yyDollar[4].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 121:
+	case 168:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:687
+//line parser.y:973
 		{
 			yyVAL.expr = &ast.MakeArrayExpr{LenExpr: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 122:
+	case 169:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:692
+//line parser.y:978
 		{
 			yyVAL.expr = &ast.MakeArrayExpr{LenExpr: yyDollar[3].expr, CapExpr: yyDollar[5].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 123:
+	case 170:
 		yyDollar = yyS[yypt-5 : yypt+1]
-//line parser.y:697
+//line parser.y:983
 		{
 			yyVAL.expr = &ast.TypeCast{Type: yyDollar[2].typ.Name, CastExpr: yyDollar[4].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 124:
+	case 171:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line parser.y:702
+//line parser.y:988
 		{
 			yyVAL.expr = &ast.MakeExpr{TypeExpr: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 125:
+	case 172:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line parser.y:707
+//line parser.y:993
 		{
 			yyVAL.expr = &ast.TypeCast{TypeExpr: yyDollar[3].expr, CastExpr: yyDollar[5].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].tok.Position())
 		}
-	case 126:
+	case 173:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line parser.y:712
+//line parser.y:998
 		{
 			yyVAL.expr = &ast.ChanExpr{Lhs: yyDollar[1].expr, Rhs: yyDollar[3].expr}
 			yyVAL.expr.SetPosition(yyDollar[1].expr.Position())
 		}
-	case 127:
+	case 174:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:717
+//line parser.y:1003
 		{
 			yyVAL.expr = &ast.ChanExpr{Rhs: yyDollar[2].expr}
 			yyVAL.expr.SetPosition(yyDollar[2].expr.Position())
 		}
-	case 130:
+	case 177:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:728
+//line parser.y:1014
 		{
 		}
-	case 131:
+	case 178:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line parser.y:731
+//line parser.y:1017
 		{
 		}
-	case 132:
+	case 179:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:736
+//line parser.y:1022
 		{
 		}
-	case 133:
+	case 180:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line parser.y:739
+//line parser.y:1025
 		{
 		}
 	}
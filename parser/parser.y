@@ -17,15 +17,31 @@ import (
 %type<stmt_default> stmt_default
 %type<stmt_case> stmt_case
 %type<stmt_cases> stmt_cases
+%type<expr> case_pattern
+%type<exprs> case_patterns
 %type<stmt_elsif> stmt_elsif
 %type<stmt_elsifs> stmt_elsifs
 %type<typ> typ
+%type<typ> type_ann
+%type<typ> func_ret
+%type<tok> opt_export
+%type<expr> opt_guard
 %type<expr> expr
 %type<exprs> exprs
 %type<expr_many> expr_many
 %type<expr_pair> expr_pair
 %type<expr_pairs> expr_pairs
-%type<expr_idents> expr_idents
+%type<func_param> func_param
+%type<func_param> typed_ident
+%type<func_params> func_params
+%type<class_member> class_member
+%type<class_members> class_members
+%type<idents> idents
+%type<expr> call_arg
+%type<exprs> call_args
+%type<exprs> call_varargs
+%type<exprs> exprs_iface
+%type<expr> expr_iface
 
 %union{
 	compstmt               ast.Stmts
@@ -45,14 +61,18 @@ import (
 	expr_many              []ast.Expr
 	expr_pair              ast.Expr
 	expr_pairs             []ast.Expr
-	expr_idents            []int
+	func_param             *ast.FuncParam
+	func_params            []*ast.FuncParam
+	class_member           *ast.ClassMember
+	class_members          []*ast.ClassMember
+	idents                 []int
 	tok                    ast.Token
 	term                   ast.Token
 	terms                  ast.Token
 	opt_terms              ast.Token
 }
 
-%token<tok> IDENT NUMBER STRING ARRAY VARARG FUNC RETURN THROW IF ELSE FOR IN EQEQ NEQ GE LE OROR ANDAND TRUE FALSE NIL MODULE TRY CATCH FINALLY PLUSEQ MINUSEQ MULEQ DIVEQ ANDEQ OREQ BREAK CONTINUE PLUSPLUS MINUSMINUS POW SHIFTLEFT SHIFTRIGHT SWITCH CASE DEFAULT GO CHAN MAKE OPCHAN ARRAYLIT NULL EACH TO ELSIF WHILE TERNARY TYPECAST
+%token<tok> IDENT NUMBER STRING ARRAY VARARG FUNC RETURN THROW IF ELSE FOR IN EQEQ NEQ GE LE OROR ANDAND TRUE FALSE NIL MODULE TRY CATCH FINALLY PLUSEQ MINUSEQ MULEQ DIVEQ ANDEQ OREQ BREAK CONTINUE PLUSPLUS MINUSMINUS POW SHIFTLEFT SHIFTRIGHT SWITCH CASE DEFAULT GO CHAN MAKE OPCHAN ARRAYLIT NULL EACH TO ELSIF WHILE TERNARY TYPECAST EXPORT BYVAL CLASS INTERFACE IMPLEMENTS VAR YIELD
 
 %right '='
 %right '?' ':'
@@ -99,6 +119,14 @@ module :
 		$$.SetPosition($1.Position())
 	}
 
+/*
+ * Пустые stmts и opt_terms перед compstmt дают конфликты shift/reduce на ';', '\n'
+ * и reduce/reduce на '}' в каждом правиле с телом блока (функции, методы классов, циклы, ветки),
+ * а неоднозначное правило terms - reduce/reduce на каждом ключевом слове начала оператора.
+ * Все они разрешаются по умолчанию в пользу сдвига и первого правила, это ожидаемое поведение.
+ * Новые правила не должны добавлять конфликтов других видов:
+ * необязательные части (Экспорт, условие Когда) выносятся в opt_export и opt_guard, а не дублируют правило.
+ */
 compstmt : opt_terms
 	{
 		$$ = nil
@@ -156,6 +184,11 @@ stmt :
 		$$ = &ast.ThrowStmt{Expr: $2}
 		$$.SetPosition($1.Position())
 	}
+	| YIELD expr
+	{
+		$$ = &ast.YieldStmt{Expr: $2}
+		$$.SetPosition($1.Position())
+	}
 	| stmt_if
 	{
 		$$ = $1
@@ -196,12 +229,116 @@ stmt :
 		$$ = &ast.SelectStmt{Cases: $3}
 		$$.SetPosition($1.Position())
 	}
+	| CLASS IDENT opt_terms '}'
+	{
+		$$ = ast.NewClassStmt(names.UniqueNames.Set($2.Lit), nil, nil)
+		$$.SetPosition($1.Position())
+	}
+	| CLASS IDENT IMPLEMENTS idents opt_terms '}'
+	{
+		$$ = ast.NewClassStmt(names.UniqueNames.Set($2.Lit), $4, nil)
+		$$.SetPosition($1.Position())
+	}
+	| CLASS IDENT class_members opt_terms '}'
+	{
+		$$ = ast.NewClassStmt(names.UniqueNames.Set($2.Lit), nil, $3)
+		$$.SetPosition($1.Position())
+	}
+	| CLASS IDENT IMPLEMENTS idents class_members opt_terms '}'
+	{
+		$$ = ast.NewClassStmt(names.UniqueNames.Set($2.Lit), $4, $5)
+		$$.SetPosition($1.Position())
+	}
+	| INTERFACE IDENT exprs_iface opt_terms '}'
+	{
+		$$ = &ast.InterfaceStmt{Name: names.UniqueNames.Set($2.Lit), Methods: $3}
+		$$.SetPosition($1.Position())
+	}
+	| VAR typed_ident
+	{
+		$$ = &ast.VarStmt{Names: []int{$2.Name}, Types: []int{$2.Type}, Exprs: []ast.Expr{&ast.ConstExpr{Value: "неопределено"}}}
+		$$.SetPosition($1.Position())
+	}
+	| VAR typed_ident '=' expr
+	{
+		$$ = &ast.VarStmt{Names: []int{$2.Name}, Types: []int{$2.Type}, Exprs: []ast.Expr{$4}}
+		$$.SetPosition($1.Position())
+	}
+	| VAR typed_ident EQEQ expr
+	{
+		$$ = &ast.VarStmt{Names: []int{$2.Name}, Types: []int{$2.Type}, Exprs: []ast.Expr{$4}}
+		$$.SetPosition($1.Position())
+	}
 	| expr
 	{
 		$$ = &ast.ExprStmt{Expr: $1}
 		$$.SetPosition($1.Position())
 	}
 
+idents :
+	IDENT
+	{
+		$$ = []int{names.UniqueNames.Set($1.Lit)}
+	}
+	| idents ',' opt_terms IDENT
+	{
+		$$ = append($1, names.UniqueNames.Set($4.Lit))
+	}
+
+class_members :
+	opt_terms class_member
+	{
+		$$ = []*ast.ClassMember{$2}
+	}
+	| class_members terms class_member
+	{
+		$$ = append($1, $3)
+	}
+
+class_member :
+	VAR typed_ident
+	{
+		$$ = &ast.ClassMember{Field: $2.Name, Type: $2.Type}
+	}
+	| VAR typed_ident '=' expr
+	{
+		$$ = &ast.ClassMember{Field: $2.Name, Type: $2.Type, Default: $4}
+	}
+	| VAR typed_ident EQEQ expr
+	{
+		$$ = &ast.ClassMember{Field: $2.Name, Type: $2.Type, Default: $4}
+	}
+	| FUNC IDENT '(' func_params ')' func_ret opt_export opt_terms compstmt '}'
+	{
+		$$ = &ast.ClassMember{Method: ast.NewMethodExpr(names.UniqueNames.Set($2.Lit), $4, $9)}
+		$$.Method.RetType = $6.Name
+		$$.Method.SetPosition($1.Position())
+	}
+
+exprs_iface :
+	opt_terms expr_iface
+	{
+		$$ = []ast.Expr{$2}
+	}
+	| exprs_iface terms expr_iface
+	{
+		$$ = append($1, $3)
+	}
+
+expr_iface :
+	FUNC IDENT '(' func_params ')' func_ret
+	{
+		fn := &ast.FuncExpr{Name: names.UniqueNames.Set($2.Lit), RetType: $6.Name}
+		fn.SetParams($4)
+		$$ = fn
+		$$.SetPosition($1.Position())
+	}
+	| FUNC IDENT '(' IDENT VARARG ')' func_ret
+	{
+		$$ = &ast.FuncExpr{Name: names.UniqueNames.Set($2.Lit), Args: []int{names.UniqueNames.Set($4.Lit)}, VarArg: true, RetType: $7.Name}
+		$$.SetPosition($1.Position())
+	}
+
 stmt_elsifs:
 	{
 		$$ = ast.Stmts{}
@@ -256,9 +393,44 @@ stmt_cases :
 	}
 
 stmt_case :
-	CASE expr ':' opt_terms compstmt
+	CASE case_patterns opt_guard ':' opt_terms compstmt
+	{
+		$$ = ast.NewCaseStmt($2, $3, $6)
+	}
+
+opt_guard :
+	{
+		$$ = nil
+	}
+	| IF expr
+	{
+		$$ = $2
+	}
+
+case_patterns :
+	case_pattern
+	{
+		$$ = []ast.Expr{$1}
+	}
+	| case_patterns ',' case_pattern
 	{
-		$$ = &ast.CaseStmt{Expr: $2, Stmts: $5}
+		$$ = append($1, $3)
+	}
+
+case_pattern :
+	expr
+	{
+		$$ = $1
+	}
+	| expr TO expr
+	{
+		$$ = &ast.RangePattern{From: $1, To: $3}
+		$$.SetPosition($1.Position())
+	}
+	| TYPECAST typ
+	{
+		$$ = &ast.TypePattern{Type: $2.Name}
+		$$.SetPosition($1.Position())
 	}
 
 stmt_default :
@@ -286,17 +458,127 @@ expr_pairs :
 		$$ = append($1, $4)
 	}
 
-expr_idents :
+type_ann :
+	':' typ
 	{
-		$$ = []int{}
+		$$ = $2
 	}
-	| IDENT
+	| ':' TYPECAST typ
 	{
-		$$ = []int{names.UniqueNames.Set($1.Lit)}
+		$$ = $3
 	}
-	| expr_idents ',' opt_terms IDENT
+
+func_ret :
 	{
-		$$ = append($1, names.UniqueNames.Set($4.Lit))
+		$$ = ast.Type{}
+	}
+	| type_ann
+	{
+		$$ = $1
+	}
+
+opt_export :
+	{
+		$$ = ast.Token{}
+	}
+	| EXPORT
+	{
+		$$ = $1
+	}
+
+typed_ident :
+	IDENT
+	{
+		$$ = &ast.FuncParam{Name: names.UniqueNames.Set($1.Lit)}
+	}
+	| IDENT type_ann
+	{
+		$$ = &ast.FuncParam{Name: names.UniqueNames.Set($1.Lit), Type: $2.Name}
+	}
+
+func_param :
+	typed_ident
+	{
+		$$ = $1
+	}
+	| BYVAL typed_ident
+	{
+		$$ = $2
+		$$.ByVal = true
+	}
+	| typed_ident '=' expr
+	{
+		$$ = $1
+		$$.Default = $3
+	}
+	| typed_ident EQEQ expr
+	{
+		$$ = $1
+		$$.Default = $3
+	}
+	| BYVAL typed_ident '=' expr
+	{
+		$$ = $2
+		$$.ByVal = true
+		$$.Default = $4
+	}
+	| BYVAL typed_ident EQEQ expr
+	{
+		$$ = $2
+		$$.ByVal = true
+		$$.Default = $4
+	}
+
+func_params :
+	{
+		$$ = []*ast.FuncParam{}
+	}
+	| func_param
+	{
+		$$ = []*ast.FuncParam{$1}
+	}
+	| func_params ',' opt_terms func_param
+	{
+		$$ = append($1, $4)
+	}
+
+call_arg :
+	{
+		$$ = &ast.NoneExpr{}
+	}
+	| expr
+	{
+		$$ = $1
+	}
+
+call_args :
+	call_arg
+	{
+		if _, ok := $1.(*ast.NoneExpr); ok {
+			$$ = nil
+		} else {
+			$$ = []ast.Expr{$1}
+		}
+	}
+	| call_args ',' opt_terms call_arg
+	{
+		if len($1) == 0 {
+			// пропущен первый параметр
+			$1 = []ast.Expr{&ast.NoneExpr{}}
+		}
+		$$ = append($1, $4)
+	}
+
+call_varargs :
+	call_args VARARG
+	{
+		// разворачиваемый массив пропустить нельзя
+		if len($1) == 0 {
+			yylex.Error("не указан массив перед ...")
+		} else if _, ok := $1[len($1)-1].(*ast.NoneExpr); ok {
+			yylex.Error("не указан массив перед ...")
+		}
+		$$ = $1
 	}
 
 expr_many :
@@ -400,24 +682,28 @@ expr :
 		$$ = &ast.MemberExpr{Expr: $1, Name: names.UniqueNames.Set($3.Lit)}
 		$$.SetPosition($1.Position())
 	}
-	| FUNC '(' expr_idents ')' opt_terms compstmt '}'
+	| FUNC '(' func_params ')' func_ret opt_terms compstmt '}'
 	{
-		$$ = &ast.FuncExpr{Name:names.UniqueNames.Set("<анонимная функция>"), Args: $3, Stmts: $6}
+		fn := &ast.FuncExpr{Name:names.UniqueNames.Set("<анонимная функция>"), Stmts: $7, RetType: $5.Name}
+		fn.SetParams($3)
+		$$ = fn
 		$$.SetPosition($1.Position())
 	}
-	| FUNC '(' IDENT VARARG ')' opt_terms compstmt '}'
+	| FUNC '(' IDENT VARARG ')' func_ret opt_terms compstmt '}'
 	{
-		$$ = &ast.FuncExpr{Name:names.UniqueNames.Set("<анонимная функция>"), Args: []int{names.UniqueNames.Set($3.Lit)}, Stmts: $7, VarArg: true}
+		$$ = &ast.FuncExpr{Name:names.UniqueNames.Set("<анонимная функция>"), Args: []int{names.UniqueNames.Set($3.Lit)}, Stmts: $8, VarArg: true, RetType: $6.Name}
 		$$.SetPosition($1.Position())
 	}
-	| FUNC IDENT '(' expr_idents ')' opt_terms compstmt '}'
+	| FUNC IDENT '(' func_params ')' func_ret opt_export opt_terms compstmt '}'
 	{
-		$$ = &ast.FuncExpr{Name: names.UniqueNames.Set($2.Lit), Args: $4, Stmts: $7}
+		fn := &ast.FuncExpr{Name: names.UniqueNames.Set($2.Lit), Stmts: $9, Export: $7.Tok == EXPORT, RetType: $6.Name}
+		fn.SetParams($4)
+		$$ = fn
 		$$.SetPosition($1.Position())
 	}
-	| FUNC IDENT '(' IDENT VARARG ')' opt_terms compstmt '}'
+	| FUNC IDENT '(' IDENT VARARG ')' func_ret opt_export opt_terms compstmt '}'
 	{
-		$$ = &ast.FuncExpr{Name: names.UniqueNames.Set($2.Lit), Args: []int{names.UniqueNames.Set($4.Lit)}, Stmts: $8, VarArg: true}
+		$$ = &ast.FuncExpr{Name: names.UniqueNames.Set($2.Lit), Args: []int{names.UniqueNames.Set($4.Lit)}, Stmts: $10, VarArg: true, Export: $8.Tok == EXPORT, RetType: $7.Name}
 		$$.SetPosition($1.Position())
 	}
 	| '[' opt_terms exprs opt_terms ']'
@@ -583,42 +869,42 @@ expr :
 		$$ = &ast.BinOpExpr{Lhss: []ast.Expr{$1}, Operator: "&&", Rhss: []ast.Expr{$3}}
 		$$.SetPosition($1.Position())
 	}
-	| IDENT '(' exprs VARARG ')'
+	| IDENT '(' call_varargs ')'
 	{
 		$$ = &ast.CallExpr{Name: names.UniqueNames.Set($1.Lit), SubExprs: $3, VarArg: true}
 		$$.SetPosition($1.Position())
 	}
-	| IDENT '(' exprs ')'
+	| IDENT '(' call_args ')'
 	{
 		$$ = &ast.CallExpr{Name: names.UniqueNames.Set($1.Lit), SubExprs: $3}
 		$$.SetPosition($1.Position())
 	}
-	| GO IDENT '(' exprs VARARG ')'
+	| GO IDENT '(' call_varargs ')'
 	{
 		$$ = &ast.CallExpr{Name: names.UniqueNames.Set($2.Lit), SubExprs: $4, VarArg: true, Go: true}
 		$$.SetPosition($2.Position())
 	}
-	| GO IDENT '(' exprs ')'
+	| GO IDENT '(' call_args ')'
 	{
 		$$ = &ast.CallExpr{Name: names.UniqueNames.Set($2.Lit), SubExprs: $4, Go: true}
 		$$.SetPosition($2.Position())
 	}
-	| expr '(' exprs VARARG ')'
+	| expr '(' call_varargs ')'
 	{
 		$$ = &ast.AnonCallExpr{Expr: $1, SubExprs: $3, VarArg: true}
 		$$.SetPosition($1.Position())
 	}
-	| expr '(' exprs ')'
+	| expr '(' call_args ')'
 	{
 		$$ = &ast.AnonCallExpr{Expr: $1, SubExprs: $3}
 		$$.SetPosition($1.Position())
 	}
-	| GO expr '(' exprs VARARG ')'
+	| GO expr '(' call_varargs ')'
 	{
 		$$ = &ast.AnonCallExpr{Expr: $2, SubExprs: $4, VarArg: true, Go: true}
 		$$.SetPosition($2.Position())
 	}
-	| GO expr '(' exprs ')'
+	| GO expr '(' call_args ')'
 	{
 		$$ = &ast.AnonCallExpr{Expr: $2, SubExprs: $4, Go: true}
 		$$.SetPosition($1.Position())
@@ -66,6 +66,9 @@ func (x *VMGonecInterpreterService) Start() error {
 			x.lockSessions.Lock()
 			for id, lat := range x.lastAccess {
 				if time.Since(lat) >= 10*time.Minute {
+					if env, ok := x.sessions[id]; ok {
+						env.Destroy()
+					}
 					delete(x.sessions, id)
 					delete(x.lastAccess, id)
 					log.Println("Закрыта сессия Sid=" + id)
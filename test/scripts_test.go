@@ -0,0 +1,95 @@
+package test
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/shinanca/gonec/bincode"
+	"github.com/shinanca/gonec/core"
+)
+
+// scripts тесты на языке Гонец, выполняемые в каталоге test, как и при запуске gonec core/имя_test.gnc
+var scripts = []string{
+	"core/sqlite_test.gnc",
+	"core/csv_test.gnc",
+	"core/pdf_test.gnc",
+	"core/binarydata_test.gnc",
+	"core/archive_test.gnc",
+	"core/bolt_cursor_test.gnc",
+	"core/bolt_index_test.gnc",
+	"core/bolt_tx_test.gnc",
+	"core/bolt_query_test.gnc",
+	"core/bolt_maint_test.gnc",
+	"core/calendar_test.gnc",
+	"core/timezone_test.gnc",
+	"core/scheduler_test.gnc",
+	"core/money_test.gnc",
+	"core/format_test.gnc",
+	"core/collections_test.gnc",
+	"core/orderedmap_test.gnc",
+	"core/containers_test.gnc",
+	"core/closures_test.gnc",
+	"core/params_test.gnc",
+	"core/classes_test.gnc",
+	"core/patterns_test.gnc",
+	"core/generators_test.gnc",
+	"core/template_test.gnc",
+}
+
+func TestScripts(t *testing.T) {
+	for _, name := range scripts {
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, bins, err := bincode.ParseSrc(string(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := bincode.Run(bins, core.NewEnv()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestAbandonedGenerators проверяет, что генераторы, брошенные скриптом в долгоживущем окружении,
+// не оставляют горутин
+func TestAbandonedGenerators(t *testing.T) {
+	src := `
+Функция Числа()
+  н = 0
+  Пока Истина Цикл
+    Выдать н
+    н = н + 1
+  КонецЦикла
+КонецФункции
+
+Для к = 1 По 500 Цикл
+  г = Числа()
+  г.Следующий()
+КонецЦикла
+г = Неопределено
+`
+	_, bins, err := bincode.ParseSrc(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := core.NewEnv()
+	before := runtime.NumGoroutine()
+	if _, err := bincode.Run(bins, env); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before+10 {
+		if time.Now().After(deadline) {
+			t.Fatalf("горутин %d, было %d", runtime.NumGoroutine(), before)
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	env.Destroy()
+}